@@ -9,14 +9,19 @@ sleep 5
 
 MSVIDEO_BUCKET_NAME="cks-hackathon-video-system"
 MSVIDEO_QUEUE_NAME="MSVideo-Queue"
+MSVIDEO_DLQ_NAME="MSVideo-DLQueue"
 MSVIDEO_TABLE_NAME="MSVideo.Video"
 
 # Create S3 bucket
 awslocal s3 mb s3://$MSVIDEO_BUCKET_NAME
 echo "✓ Created S3 bucket: $MSVIDEO_BUCKET_NAME"
 
-# Create SQS queue
-awslocal sqs create-queue --queue-name "$MSVIDEO_QUEUE_NAME" --region "$AWS_REGION"
+# Create SQS queue and its dead-letter queue, with the redrive of terraform
+awslocal sqs create-queue --queue-name "$MSVIDEO_DLQ_NAME" --region "$AWS_REGION"
+echo "✓ Created SQS queue: $MSVIDEO_DLQ_NAME"
+
+awslocal sqs create-queue --queue-name "$MSVIDEO_QUEUE_NAME" --region "$AWS_REGION" \
+    --attributes "{\"RedrivePolicy\":\"{\\\"deadLetterTargetArn\\\":\\\"arn:aws:sqs:$AWS_REGION:000000000000:$MSVIDEO_DLQ_NAME\\\",\\\"maxReceiveCount\\\":\\\"6\\\"}\"}"
 echo "✓ Created SQS queue: $MSVIDEO_QUEUE_NAME"
 
 # Create DynamoDB table
@@ -27,6 +32,8 @@ awslocal dynamodb create-table \
         AttributeName=id,AttributeType=S \
         AttributeName=user_id,AttributeType=S \
         AttributeName=created_at,AttributeType=S \
+        AttributeName=updated_at,AttributeType=S \
+        AttributeName=content_hash,AttributeType=S \
     --key-schema \
         AttributeName=id,KeyType=HASH \
     --global-secondary-indexes \
@@ -44,12 +51,134 @@ awslocal dynamodb create-table \
                     \"ReadCapacityUnits\": 5,
                     \"WriteCapacityUnits\": 5
                 }
+            },
+            {
+                \"IndexName\": \"user_id-updated_at-index\",
+                \"KeySchema\": [
+                    {\"AttributeName\":\"user_id\",\"KeyType\":\"HASH\"},
+                    {\"AttributeName\":\"updated_at\",\"KeyType\":\"RANGE\"}
+                ],
+                \"Projection\": {
+                    \"ProjectionType\":\"ALL\"
+                },
+                \"ProvisionedThroughput\": {
+                    \"ReadCapacityUnits\": 5,
+                    \"WriteCapacityUnits\": 5
+                }
+            },
+            {
+                \"IndexName\": \"content_hash-index\",
+                \"KeySchema\": [
+                    {\"AttributeName\":\"content_hash\",\"KeyType\":\"HASH\"},
+                    {\"AttributeName\":\"created_at\",\"KeyType\":\"RANGE\"}
+                ],
+                \"Projection\": {
+                    \"ProjectionType\":\"ALL\"
+                },
+                \"ProvisionedThroughput\": {
+                    \"ReadCapacityUnits\": 5,
+                    \"WriteCapacityUnits\": 5
+                }
             }
         ]" \
     --provisioned-throughput \
-        ReadCapacityUnits=5,WriteCapacityUnits=5
+        ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --stream-specification \
+        StreamEnabled=true,StreamViewType=NEW_IMAGE
+
+echo "✓ Created DynamoDB table: $MSVIDEO_TABLE_NAME with user_id-index, user_id-updated_at-index, content_hash-index and a stream"
+
+# Resumable (tus) upload sessions
+awslocal dynamodb create-table \
+    --table-name "MSVideo.UploadSession" \
+    --region "$AWS_REGION" \
+    --attribute-definitions \
+        AttributeName=id,AttributeType=S \
+    --key-schema \
+        AttributeName=id,KeyType=HASH \
+    --billing-mode PAY_PER_REQUEST
+
+awslocal dynamodb update-time-to-live \
+    --table-name "MSVideo.UploadSession" \
+    --region "$AWS_REGION" \
+    --time-to-live-specification Enabled=true,AttributeName=expires_at
+
+echo "✓ Created DynamoDB table: MSVideo.UploadSession"
+
+# Webhooks: delivery queue, subscriptions and delivery log
+awslocal sqs create-queue --queue-name "MSVideo-WebhookDLQueue" --region "$AWS_REGION"
+echo "✓ Created SQS queue: MSVideo-WebhookDLQueue"
+
+awslocal sqs create-queue --queue-name "MSVideo-WebhookQueue" --region "$AWS_REGION" \
+    --attributes "{\"RedrivePolicy\":\"{\\\"deadLetterTargetArn\\\":\\\"arn:aws:sqs:$AWS_REGION:000000000000:MSVideo-WebhookDLQueue\\\",\\\"maxReceiveCount\\\":\\\"10\\\"}\"}"
+echo "✓ Created SQS queue: MSVideo-WebhookQueue"
+
+awslocal dynamodb create-table \
+    --table-name "MSVideo.Webhook" \
+    --region "$AWS_REGION" \
+    --attribute-definitions \
+        AttributeName=id,AttributeType=S \
+        AttributeName=user_id,AttributeType=S \
+    --key-schema \
+        AttributeName=id,KeyType=HASH \
+    --global-secondary-indexes \
+        "[
+            {
+                \"IndexName\": \"user_id-index\",
+                \"KeySchema\": [
+                    {\"AttributeName\":\"user_id\",\"KeyType\":\"HASH\"}
+                ],
+                \"Projection\": {
+                    \"ProjectionType\":\"ALL\"
+                }
+            }
+        ]" \
+    --billing-mode PAY_PER_REQUEST
+
+echo "✓ Created DynamoDB table: MSVideo.Webhook with user_id-index"
+
+awslocal dynamodb create-table \
+    --table-name "MSVideo.WebhookDelivery" \
+    --region "$AWS_REGION" \
+    --attribute-definitions \
+        AttributeName=id,AttributeType=S \
+        AttributeName=webhook_id,AttributeType=S \
+        AttributeName=created_at,AttributeType=S \
+    --key-schema \
+        AttributeName=id,KeyType=HASH \
+    --global-secondary-indexes \
+        "[
+            {
+                \"IndexName\": \"webhook_id-created_at-index\",
+                \"KeySchema\": [
+                    {\"AttributeName\":\"webhook_id\",\"KeyType\":\"HASH\"},
+                    {\"AttributeName\":\"created_at\",\"KeyType\":\"RANGE\"}
+                ],
+                \"Projection\": {
+                    \"ProjectionType\":\"ALL\"
+                }
+            }
+        ]" \
+    --billing-mode PAY_PER_REQUEST
+
+awslocal dynamodb update-time-to-live \
+    --table-name "MSVideo.WebhookDelivery" \
+    --region "$AWS_REGION" \
+    --time-to-live-specification Enabled=true,AttributeName=expires_at
+
+echo "✓ Created DynamoDB table: MSVideo.WebhookDelivery with webhook_id-created_at-index"
+
+# Per-user usage counters for the quotas
+awslocal dynamodb create-table \
+    --table-name "MSVideo.Usage" \
+    --region "$AWS_REGION" \
+    --attribute-definitions \
+        AttributeName=user_id,AttributeType=S \
+    --key-schema \
+        AttributeName=user_id,KeyType=HASH \
+    --billing-mode PAY_PER_REQUEST
 
-echo "✓ Created DynamoDB table: $MSVIDEO_TABLE_NAME with user_id-index"
+echo "✓ Created DynamoDB table: MSVideo.Usage"
 
 echo "Initializing LocalStack resources for ms-notify..."
 
@@ -50,6 +50,30 @@ resource "aws_s3_bucket_lifecycle_configuration" "video_system" {
       storage_class = "GLACIER"
     }
   }
+
+  rule {
+    id     = "abort-incomplete-multipart-uploads"
+    status = "Enabled"
+
+    filter {}
+
+    abort_incomplete_multipart_upload {
+      days_after_initiation = 2
+    }
+  }
+
+  rule {
+    id     = "delete-resumable-upload-tails"
+    status = "Enabled"
+
+    filter {
+      prefix = "uploads/"
+    }
+
+    expiration {
+      days = 2
+    }
+  }
 }
 
 resource "aws_s3_bucket_public_access_block" "video_system" {
@@ -83,7 +107,26 @@ module "ms_video_sqs" {
   visibility_timeout_seconds = 300
   message_retention_seconds  = 345600
   receive_wait_time_seconds  = 20
-  max_receive_count          = 3
+  # The worker retries up to PROCESS_MAX_ATTEMPTS (5) times and moves failed
+  # messages to the DLQ itself; the redrive policy only catches messages
+  # whose worker kept crashing
+  max_receive_count          = 6
+  tags                       = local.ms_video_tags
+}
+
+# SQS queue for webhook deliveries
+module "ms_video_webhook_sqs" {
+  source = "../../modules/sqs-queue"
+
+  queue_name                 = "MSVideo-WebhookQueue"
+  create_dlq                 = true
+  dlq_name                   = "MSVideo-WebhookDLQueue"
+  visibility_timeout_seconds = 60
+  message_retention_seconds  = 345600
+  receive_wait_time_seconds  = 20
+  # Deliveries are attempted up to WEBHOOK_MAX_ATTEMPTS (8) times and the
+  # worker drops them itself once they are exhausted
+  max_receive_count          = 10
   tags                       = local.ms_video_tags
 }
 
@@ -108,6 +151,16 @@ resource "aws_dynamodb_table" "ms_video_videos" {
     type = "S"
   }
 
+  attribute {
+    name = "updated_at"
+    type = "S"
+  }
+
+  attribute {
+    name = "content_hash"
+    type = "S"
+  }
+
   global_secondary_index {
     name            = "user_id-index"
     hash_key        = "user_id"
@@ -115,6 +168,122 @@ resource "aws_dynamodb_table" "ms_video_videos" {
     projection_type = "ALL"
   }
 
+  # Lists a user's videos by last update, e.g. the most recently finished
+  global_secondary_index {
+    name            = "user_id-updated_at-index"
+    hash_key        = "user_id"
+    range_key       = "updated_at"
+    projection_type = "ALL"
+  }
+
+  # Finds earlier uploads of the same content to reuse their outputs. Only
+  # videos whose upload was hashed are in it.
+  global_secondary_index {
+    name            = "content_hash-index"
+    hash_key        = "content_hash"
+    range_key       = "created_at"
+    projection_type = "ALL"
+  }
+
+  # Every ms-video replica reads the stream to push progress to its SSE
+  # clients
+  stream_enabled   = true
+  stream_view_type = "NEW_IMAGE"
+
+  tags = local.ms_video_tags
+}
+
+
+# DynamoDB table for resumable (tus) upload sessions
+resource "aws_dynamodb_table" "ms_video_upload_sessions" {
+  name         = "MSVideo.UploadSession"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  ttl {
+    attribute_name = "expires_at"
+    enabled        = true
+  }
+
+  tags = local.ms_video_tags
+}
+
+# DynamoDB table for user-registered webhooks
+resource "aws_dynamodb_table" "ms_video_webhooks" {
+  name         = "MSVideo.Webhook"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  attribute {
+    name = "user_id"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "user_id-index"
+    hash_key        = "user_id"
+    projection_type = "ALL"
+  }
+
+  tags = local.ms_video_tags
+}
+
+# DynamoDB table for the per-user usage counters checked against the quotas
+resource "aws_dynamodb_table" "ms_video_usage" {
+  name         = "MSVideo.Usage"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "user_id"
+
+  attribute {
+    name = "user_id"
+    type = "S"
+  }
+
   tags = local.ms_video_tags
 }
 
+# DynamoDB table for the webhook delivery log
+resource "aws_dynamodb_table" "ms_video_webhook_deliveries" {
+  name         = "MSVideo.WebhookDelivery"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "id"
+
+  attribute {
+    name = "id"
+    type = "S"
+  }
+
+  attribute {
+    name = "webhook_id"
+    type = "S"
+  }
+
+  attribute {
+    name = "created_at"
+    type = "S"
+  }
+
+  global_secondary_index {
+    name            = "webhook_id-created_at-index"
+    hash_key        = "webhook_id"
+    range_key       = "created_at"
+    projection_type = "ALL"
+  }
+
+  ttl {
+    attribute_name = "expires_at"
+    enabled        = true
+  }
+
+  tags = local.ms_video_tags
+}
@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
 	http_internal "github.com/cks-solutions/hackathon/ms-auth/cmd/http"
 	"github.com/cks-solutions/hackathon/ms-auth/internal/adapters/driven/sm"
@@ -14,6 +18,8 @@ import (
 	"github.com/cks-solutions/hackathon/ms-auth/pkg/utils"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	ctx := context.Background()
 	
@@ -90,8 +96,27 @@ func main() {
 
 	router := http_internal.NewRouter(ctx, db, jwtSecret, jwtExpiration)
 
-	log.Printf("🚀 Server starting on port %s", port)
-	if err := http.ListenAndServe(":"+port, router); err != nil {
-		log.Fatal("Server error:", err)
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+
+	go func() {
+		log.Printf("🚀 Server starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Server error:", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("Server shutdown error:", err)
 	}
+	log.Println("Server stopped")
 }
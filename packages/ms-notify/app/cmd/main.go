@@ -2,25 +2,60 @@ package main
 
 import (
 	"context"
+	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	http_internal "github.com/cks-solutions/hackathon/ms-notify/cmd/http"
 	sqs_internal "github.com/cks-solutions/hackathon/ms-notify/cmd/sqs"
 	"github.com/cks-solutions/hackathon/ms-notify/pkg/utils"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	region := utils.GetRegion()
 	stage := utils.GetStage()
 
-	ctx := context.TODO()
+	ctx := context.Background()
 
 	router := http_internal.NewRouter(ctx, region, stage)
 	consumer := sqs_internal.NewSQSConsumer(ctx, region, stage)
 
 	go consumer.Start()
 
-	if err := http.ListenAndServe(":8080", router); err != nil {
-		panic("ListenAndServe: " + err.Error())
+	srv := &http.Server{
+		Addr:    ":8080",
+		Handler: router,
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			panic("ListenAndServe: " + err.Error())
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	consumerStopped := make(chan error, 1)
+	go func() {
+		consumerStopped <- consumer.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("Server shutdown error:", err)
+	}
+	if err := <-consumerStopped; err != nil {
+		log.Println("Consumer shutdown error:", err)
 	}
+	log.Println("Server stopped")
 }
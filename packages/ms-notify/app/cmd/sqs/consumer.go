@@ -6,6 +6,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/cks-solutions/hackathon/ms-notify/internal/adapters/driven/dynamo"
 	"github.com/cks-solutions/hackathon/ms-notify/internal/adapters/driven/ses"
 	"github.com/cks-solutions/hackathon/ms-notify/internal/adapters/driven/sqs"
@@ -19,6 +20,14 @@ type SQSConsumer struct {
 	Ctx               context.Context
 	NotificationQueue ports.NotificationQueue
 	Usecase           usecases.NotificationConsumerUsecase
+
+	// receiveCtx stops the receive loop and processCtx the batch in
+	// flight; see Shutdown
+	receiveCtx      context.Context
+	stopReceiving   context.CancelFunc
+	processCtx      context.Context
+	abortProcessing context.CancelFunc
+	done            chan struct{}
 }
 
 func NewSQSConsumer(ctx context.Context, region awsinfra.Region, stage awsinfra.Stage) *SQSConsumer {
@@ -32,52 +41,97 @@ func NewSQSConsumer(ctx context.Context, region awsinfra.Region, stage awsinfra.
 
 	usecase := usecases.NewNotificationConsumerUsecase(notificationTable, emailService)
 
+	receiveCtx, stopReceiving := context.WithCancel(ctx)
+	processCtx, abortProcessing := context.WithCancel(ctx)
+
 	return &SQSConsumer{
 		Ctx:               ctx,
 		NotificationQueue: notificationQueue,
 		Usecase:           usecase,
+		receiveCtx:        receiveCtx,
+		stopReceiving:     stopReceiving,
+		processCtx:        processCtx,
+		abortProcessing:   abortProcessing,
+		done:              make(chan struct{}),
 	}
 }
 
+// Start receives and processes messages until Shutdown is called. The batch
+// received last is still processed before it returns.
 func (c *SQSConsumer) Start() {
-	// TODO: add graceful shutdown
+	defer close(c.done)
 	// TODO: add retry mechanism using a Dead Letter Queue (DLQ)
 
-	for {
-		select {
-		case <-c.Ctx.Done():
-			log.Println("Consumer shutting down")
-			return
-		default:
-			messages, err := c.NotificationQueue.Get(c.Ctx)
-			if err != nil {
-				log.Println("[QUEUE_READ] Consumer error:", err)
-				time.Sleep(5 * time.Second)
+	for c.receiveCtx.Err() == nil {
+		messages, err := c.NotificationQueue.Get(c.receiveCtx)
+		if err != nil {
+			if c.receiveCtx.Err() != nil {
 				continue
 			}
+			log.Println("[QUEUE_READ] Consumer error:", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
 
-			for _, message := range messages {
-				input := dto.NotificationInput{}
+		for _, message := range messages {
+			if c.processCtx.Err() != nil {
+				c.release(message)
+				continue
+			}
+			c.handleMessage(message)
+		}
+	}
 
-				data := []byte(*message.Body)
+	log.Println("Consumer shutting down")
+}
 
-				err := json.Unmarshal(data, &input)
-				if err != nil {
-					log.Println("[INVALID_DATA] Consumer error:", err)
-					c.NotificationQueue.Delete(c.Ctx, message)
-					continue
-				}
+// Shutdown stops receiving messages and waits for the batch in flight. If ctx
+// expires first, processing is cancelled and the rest of the batch is made
+// visible again right away for another consumer.
+func (c *SQSConsumer) Shutdown(ctx context.Context) error {
+	c.stopReceiving()
 
-				err = c.Usecase.Run(c.Ctx, input)
-				if err != nil {
-					log.Println("[USE_CASE_ERR] Consumer error:", err)
-					continue
-				}
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+	}
 
-				if err := c.NotificationQueue.Delete(c.Ctx, message); err != nil {
-					log.Println("[DELETE_ERR] Failed to delete message:", err)
-				}
-			}
-		}
+	log.Println("Shutdown deadline reached, releasing messages in flight")
+	c.abortProcessing()
+	<-c.done
+	return ctx.Err()
+}
+
+func (c *SQSConsumer) handleMessage(message types.Message) {
+	input := dto.NotificationInput{}
+
+	data := []byte(*message.Body)
+
+	err := json.Unmarshal(data, &input)
+	if err != nil {
+		log.Println("[INVALID_DATA] Consumer error:", err)
+		c.NotificationQueue.Delete(c.Ctx, message)
+		return
+	}
+
+	err = c.Usecase.Run(c.processCtx, input)
+	if err != nil && c.processCtx.Err() != nil {
+		c.release(message)
+		return
+	}
+	if err != nil {
+		log.Println("[USE_CASE_ERR] Consumer error:", err)
+		return
+	}
+
+	if err := c.NotificationQueue.Delete(c.Ctx, message); err != nil {
+		log.Println("[DELETE_ERR] Failed to delete message:", err)
+	}
+}
+
+func (c *SQSConsumer) release(message types.Message) {
+	if err := c.NotificationQueue.Release(c.Ctx, message); err != nil {
+		log.Println("[RELEASE_ERR] Failed to release message:", err)
 	}
 }
@@ -20,6 +20,7 @@ type sqsClientInterface interface {
 	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
 	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
 	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
 }
 
 type NotificationQueueImpl struct {
@@ -105,3 +106,21 @@ func (i *NotificationQueueImpl) Delete(ctx context.Context, message types.Messag
 
 	return nil
 }
+
+func (i *NotificationQueueImpl) Release(ctx context.Context, message types.Message) error {
+	url, err := i.getQueueUrl()
+	if err != nil {
+		return err
+	}
+
+	_, err = i.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          url,
+		ReceiptHandle:     message.ReceiptHandle,
+		VisibilityTimeout: 0,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -18,6 +18,8 @@ type fakeSQSClient struct {
 	receiveMessageOut *sqs.ReceiveMessageOutput
 	receiveMessageErr error
 	deleteMessageErr  error
+	changeVisibility  *sqs.ChangeMessageVisibilityInput
+	changeVisErr      error
 }
 
 func (f *fakeSQSClient) GetQueueUrl(ctx context.Context, params *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error) {
@@ -52,6 +54,14 @@ func (f *fakeSQSClient) DeleteMessage(ctx context.Context, params *sqs.DeleteMes
 	return &sqs.DeleteMessageOutput{}, nil
 }
 
+func (f *fakeSQSClient) ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.changeVisibility = params
+	if f.changeVisErr != nil {
+		return nil, f.changeVisErr
+	}
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
 func TestNotificationQueueImpl_Push(t *testing.T) {
 	ctx := context.Background()
 	msg := entities.Notification{Id: "1", Subject: "s", From: "f@x.com", To: []string{"t@x.com"}, Html: "h"}
@@ -147,6 +157,39 @@ func TestNotificationQueueImpl_Delete(t *testing.T) {
 	})
 }
 
+func TestNotificationQueueImpl_Release(t *testing.T) {
+	ctx := context.Background()
+	message := types.Message{ReceiptHandle: aws.String("handle"), Body: aws.String("{}")}
+
+	t.Run("success", func(t *testing.T) {
+		client := &fakeSQSClient{}
+		queue := NewNotificationQueue(client).(*NotificationQueueImpl)
+		err := queue.Release(ctx, message)
+		if err != nil {
+			t.Errorf("Release: %v", err)
+		}
+		if client.changeVisibility == nil || client.changeVisibility.VisibilityTimeout != 0 || *client.changeVisibility.ReceiptHandle != "handle" {
+			t.Errorf("ChangeMessageVisibility input = %+v", client.changeVisibility)
+		}
+	})
+
+	t.Run("getQueueUrl error", func(t *testing.T) {
+		queue := NewNotificationQueue(&fakeSQSClient{getQueueUrlErr: errors.New("no queue")}).(*NotificationQueueImpl)
+		err := queue.Release(ctx, message)
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("ChangeMessageVisibility error", func(t *testing.T) {
+		queue := NewNotificationQueue(&fakeSQSClient{changeVisErr: errors.New("change failed")}).(*NotificationQueueImpl)
+		err := queue.Release(ctx, message)
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
 func TestNotificationQueueImpl_getQueueUrl_env(t *testing.T) {
 	const key = "SQS_QUEUE_URL"
 	restore := os.Getenv(key)
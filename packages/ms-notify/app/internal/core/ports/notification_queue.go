@@ -11,4 +11,7 @@ type NotificationQueue interface {
 	Push(ctx context.Context, message entities.Notification) error
 	Get(ctx context.Context) ([]types.Message, error)
 	Delete(ctx context.Context, message types.Message) error
+	// Release makes a received message visible again right away, for
+	// messages left unprocessed on shutdown
+	Release(ctx context.Context, message types.Message) error
 }
@@ -26,6 +26,10 @@ func (f *fakeNotificationQueue) Delete(ctx context.Context, _ types.Message) err
 	return nil
 }
 
+func (f *fakeNotificationQueue) Release(ctx context.Context, _ types.Message) error {
+	return nil
+}
+
 func TestNotificationProducerUsecase_Run(t *testing.T) {
 	ctx := context.Background()
 	input := dto.NotificationInput{
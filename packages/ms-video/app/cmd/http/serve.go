@@ -8,6 +8,8 @@ import (
 	"net/http"
 
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/dynamodb"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/events"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/ffprobe"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/jwt"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/s3"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/sqs"
@@ -25,6 +27,16 @@ type ErrorResponse struct {
 type Router struct {
 	*http.ServeMux
 	Ctx context.Context
+
+	// streams is done once the server shuts down, ending the event streams
+	streams      context.Context
+	closeStreams context.CancelFunc
+}
+
+// CloseStreams ends the open event streams, which would otherwise hold up a
+// graceful shutdown until its deadline.
+func (r *Router) CloseStreams() {
+	r.closeStreams()
 }
 
 func handleError(w http.ResponseWriter, err error) {
@@ -44,22 +56,69 @@ func handleError(w http.ResponseWriter, err error) {
 }
 
 func NewRouter(ctx context.Context, region awsinfra.Region, stage awsinfra.Stage, jwtSecret string) *Router {
-	mux := &Router{ServeMux: http.NewServeMux(), Ctx: ctx}
+	streams, closeStreams := context.WithCancel(ctx)
+	mux := &Router{ServeMux: http.NewServeMux(), Ctx: ctx, streams: streams, closeStreams: closeStreams}
 
 	s3Client := awsinfra.NewS3Client(region, stage)
 	dynamoClient := awsinfra.NewDynamoClient(region, stage)
 	sqsClient := awsinfra.NewSQSClient(region, stage)
+	streamsClient := awsinfra.NewDynamoStreamsClient(region, stage)
 
 	storageService := s3.NewS3StorageService(s3Client)
 	videoRepository := dynamodb.NewDynamoVideoRepository(dynamoClient)
+	uploadSessionRepository := dynamodb.NewDynamoUploadSessionRepository(dynamoClient)
+	usageRepository := dynamodb.NewDynamoUsageRepository(dynamoClient)
 	videoQueue := sqs.NewSQSVideoQueue(sqsClient)
 	tokenService := jwt.NewTokenService(jwtSecret)
+	videoProber := ffprobe.NewFFProbeVideoProber()
+	videoEventBus := events.NewVideoEventBus()
+	videoStreamReader := dynamodb.NewVideoStreamReader(dynamoClient, streamsClient, videoEventBus)
+
+	go func() {
+		if err := videoStreamReader.Run(streams); err != nil {
+			log.Printf("Video event streams will stay silent: %v", err)
+		}
+	}()
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepository, storageService, videoQueue)
+	videoValidator := usecases.NewVideoValidator(videoProber, storageService, usecases.VideoLimitsFromEnv())
+	usageLimits := usecases.UsageLimitsFromEnv()
+	deduplicator := usecases.NewVideoDeduplicator(videoRepository, usecases.DedupAcrossUsersFromEnv())
+
+	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepository, storageService, videoQueue, videoValidator, usageRepository, usageLimits, deduplicator)
 	listUsecase := usecases.NewListVideosUsecase(videoRepository)
 	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepository, storageService)
+	requestUploadURLUsecase := usecases.NewRequestUploadURLUsecase(videoRepository, storageService, videoValidator, usageRepository, usageLimits)
+	confirmUploadUsecase := usecases.NewConfirmUploadUsecase(videoRepository, storageService, videoQueue, videoValidator, usageRepository)
+	previewsUsecase := usecases.NewGetPreviewsUsecase(videoRepository, storageService)
+	streamUsecase := usecases.NewGetStreamUsecase(videoRepository, storageService)
+	deleteUsecase := usecases.NewDeleteVideoUsecase(videoRepository, uploadSessionRepository, storageService, usageRepository)
+	reprocessUsecase := usecases.NewReprocessVideoUsecase(videoRepository, storageService, videoQueue, videoValidator, usageRepository, usecases.MaxProcessingAttemptsFromEnv())
+	cancelUsecase := usecases.NewCancelVideoUsecase(videoRepository)
+	pinUsecase := usecases.NewPinVideoUsecase(videoRepository)
+	framesUsecase := usecases.NewGetFramesUsecase(videoRepository, storageService)
+	watchUsecase := usecases.NewWatchVideosUsecase(videoRepository, videoEventBus)
+	resumableUploadUsecase := usecases.NewResumableUploadUsecase(videoRepository, uploadSessionRepository, storageService, videoQueue, videoValidator, usageRepository, usageLimits, deduplicator)
+	webhooksUsecase := usecases.NewWebhooksUsecase(dynamodb.NewDynamoWebhookRepository(dynamoClient), dynamodb.NewDynamoWebhookDeliveryRepository(dynamoClient))
+	usageUsecase := usecases.NewGetUsageUsecase(usageRepository, usageLimits)
 
-	videoController := controller.NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	videoController := controller.NewVideoController(
+		uploadUsecase,
+		listUsecase,
+		downloadUsecase,
+		requestUploadURLUsecase,
+		confirmUploadUsecase,
+		previewsUsecase,
+		streamUsecase,
+		deleteUsecase,
+		reprocessUsecase,
+		cancelUsecase,
+		pinUsecase,
+		framesUsecase,
+	)
+	tusController := controller.NewTusController(resumableUploadUsecase)
+	eventsController := controller.NewEventsController(watchUsecase)
+	webhookController := controller.NewWebhookController(webhooksUsecase)
+	usageController := controller.NewUsageController(usageUsecase)
 
 	healthResp := []byte(`{"status":"healthy","service":"ms-video"}`)
 	mux.HandleFunc("/video/health", func(w http.ResponseWriter, r *http.Request) {
@@ -80,6 +139,87 @@ func NewRouter(ctx context.Context, region awsinfra.Region, stage awsinfra.Stage
 		}
 	}))
 
+	mux.HandleFunc("/video/upload-url", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := videoController.RequestUploadURL(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	}))
+
+	mux.HandleFunc("/video/{id}/confirm", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := videoController.ConfirmUpload(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	}))
+
+	mux.HandleFunc("/video/{id}/previews", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := videoController.Previews(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	}))
+
+	mux.HandleFunc("/video/{id}/reprocess", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := videoController.Reprocess(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	}))
+
+	mux.HandleFunc("/video/{id}/cancel", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := videoController.Cancel(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	}))
+
+	mux.HandleFunc("/video/{id}/pin", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := videoController.Pin(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	}))
+
+	framesHandler := middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := videoController.Frames(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	})
+	mux.HandleFunc("/video/{id}/frames", framesHandler)
+	mux.HandleFunc("/video/{id}/frames/{n}", framesHandler)
+
+	streamHandler := middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		if err := videoController.Stream(r.Context(), w, r); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			handleError(w, err)
+		}
+	})
+	mux.HandleFunc("/video/{id}/stream", streamHandler)
+	mux.HandleFunc("/video/{id}/stream/{playlist}", streamHandler)
+
+	createTusUpload := middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		if err := tusController.Create(r.Context(), w, r); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			handleError(w, err)
+		}
+	})
+	mux.HandleFunc("/video/tus/uploads", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			tusController.Options(w, r)
+			return
+		}
+		createTusUpload(w, r)
+	})
+
+	mux.HandleFunc("/video/tus/uploads/{id}", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		if err := tusController.Upload(r.Context(), w, r); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			handleError(w, err)
+		}
+	}))
+
 	mux.HandleFunc("/video/list", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		if err := videoController.List(r.Context(), w, r); err != nil {
@@ -87,6 +227,52 @@ func NewRouter(ctx context.Context, region awsinfra.Region, stage awsinfra.Stage
 		}
 	}))
 
+	eventsHandler := func(handle func(ctx context.Context, w http.ResponseWriter, r *http.Request) error) http.HandlerFunc {
+		return middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			stop := context.AfterFunc(streams, cancel)
+			defer stop()
+
+			if err := handle(ctx, w, r); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				handleError(w, err)
+			}
+		})
+	}
+	mux.HandleFunc("/video/events", eventsHandler(eventsController.UserEvents))
+	mux.HandleFunc("/video/{id}/events", eventsHandler(eventsController.VideoEvents))
+
+	// Webhooks are addressed by query parameter, as /video/webhooks/{id}
+	// would clash with the /video/{id}/... routes
+	mux.HandleFunc("/video/webhooks", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := webhookController.Webhooks(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	}))
+
+	mux.HandleFunc("/video/webhooks/deliveries", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := webhookController.Deliveries(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	}))
+
+	mux.HandleFunc("/video/usage", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := usageController.Usage(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	}))
+
+	mux.HandleFunc("/video/{id}", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := videoController.Delete(r.Context(), w, r); err != nil {
+			handleError(w, err)
+		}
+	}))
+
 	mux.HandleFunc("/video/download", middleware.AuthMiddleware(tokenService, func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		if err := videoController.Download(r.Context(), w, r); err != nil {
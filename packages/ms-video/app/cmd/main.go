@@ -2,21 +2,33 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	http_internal "github.com/cks-solutions/hackathon/ms-video/cmd/http"
 	sqs_internal "github.com/cks-solutions/hackathon/ms-video/cmd/sqs"
+	sweeper_internal "github.com/cks-solutions/hackathon/ms-video/cmd/sweeper"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/sm"
 	awsinfra "github.com/cks-solutions/hackathon/ms-video/internal/infra/aws"
 	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
 )
 
 func main() {
-	ctx := context.TODO()
+	ctx := context.Background()
 	
 	region := awsinfra.Region(utils.GetRegion())
 	stage := awsinfra.Stage(utils.GetStage())
+
+	if len(os.Args) > 1 && os.Args[1] == "sweep" {
+		runRetentionSweeper(region, stage, os.Args[2:])
+		return
+	}
+
 	port := utils.GetEnv("PORT", "8080")
 	
 	var jwtSecret string
@@ -45,9 +57,72 @@ func main() {
 	consumer := sqs_internal.NewSQSConsumer(ctx, region, stage)
 	go consumer.Start()
 
-	log.Printf("🚀 Server starting on port %s", port)
-	log.Printf("📦 Stage: %s, Region: %s", stage, region)
-	if err := http.ListenAndServe(":"+port, router); err != nil {
-		log.Fatal("Server error:", err)
+	webhookConsumer := sqs_internal.NewWebhookConsumer(ctx, region, stage)
+	go webhookConsumer.Start()
+
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+	srv.RegisterOnShutdown(router.CloseStreams)
+
+	go func() {
+		log.Printf("🚀 Server starting on port %s", port)
+		log.Printf("📦 Stage: %s, Region: %s", stage, region)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Server error:", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	// The HTTP server and the consumers drain in parallel under one deadline,
+	// which has to fit in the pod's termination grace period
+	shutdownTimeout := time.Duration(utils.GetEnvInt("SHUTDOWN_TIMEOUT_SECONDS", 25)) * time.Second
+	log.Printf("Shutting down, waiting up to %s for requests and videos in flight", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	consumerStopped := make(chan error, 1)
+	go func() {
+		consumerStopped <- consumer.Shutdown(shutdownCtx)
+	}()
+	webhookConsumerStopped := make(chan error, 1)
+	go func() {
+		webhookConsumerStopped <- webhookConsumer.Shutdown(shutdownCtx)
+	}()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Println("Server shutdown error:", err)
+	}
+	if err := <-consumerStopped; err != nil {
+		log.Println("Consumer shutdown error:", err)
+	}
+	if err := <-webhookConsumerStopped; err != nil {
+		log.Println("Webhook consumer shutdown error:", err)
+	}
+	log.Println("Server stopped")
+}
+
+// runRetentionSweeper is the sweep command mode: instead of serving the API
+// and processing videos, it deletes storage past its retention period.
+func runRetentionSweeper(region awsinfra.Region, stage awsinfra.Stage, args []string) {
+	flags := flag.NewFlagSet("sweep", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "log what would be deleted without deleting anything")
+	once := flags.Bool("once", false, "sweep once and exit instead of every RETENTION_SWEEP_INTERVAL_MINUTES")
+	flags.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	sweeper := sweeper_internal.NewRetentionSweeper(region, stage, *dryRun)
+	if *once {
+		sweeper.Interval = 0
+	}
+	if err := sweeper.Run(ctx); err != nil {
+		log.Println("Retention sweep failed:", err)
+		os.Exit(1)
 	}
 }
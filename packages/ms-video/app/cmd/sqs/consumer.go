@@ -3,10 +3,15 @@ package sqs
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/dynamodb"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/ffprobe"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/notification"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/s3"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/sqs"
@@ -14,12 +19,31 @@ import (
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
 	awsinfra "github.com/cks-solutions/hackathon/ms-video/internal/infra/aws"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
 )
 
+// MAX_RECEIVE_MESSAGES is the most messages SQS returns per receive call.
+const MAX_RECEIVE_MESSAGES = 10
+
 type SQSConsumer struct {
 	Ctx        context.Context
 	VideoQueue ports.VideoQueue
 	Usecase    *usecases.ProcessVideoUsecase
+
+	// Concurrency is how many videos are processed at the same time.
+	Concurrency int
+	// VisibilityTimeout is how long a received message stays hidden. It is
+	// extended every HeartbeatInterval while the message is processed.
+	VisibilityTimeout time.Duration
+	HeartbeatInterval time.Duration
+
+	// receiveCtx stops the receive loop and processCtx the videos in
+	// flight; see Shutdown
+	receiveCtx      context.Context
+	stopReceiving   context.CancelFunc
+	processCtx      context.Context
+	abortProcessing context.CancelFunc
+	done            chan struct{}
 }
 
 func NewSQSConsumer(ctx context.Context, region awsinfra.Region, stage awsinfra.Stage) *SQSConsumer {
@@ -32,63 +56,213 @@ func NewSQSConsumer(ctx context.Context, region awsinfra.Region, stage awsinfra.
 	videoQueue := sqs.NewSQSVideoQueue(sqsClient)
 	notificationService := notification.NewNotificationService()
 
-	processUsecase := usecases.NewProcessVideoUsecase(videoRepository, storageService, notificationService)
+	videoProber := ffprobe.NewFFProbeVideoProber()
+
+	webhookPublisher := usecases.NewWebhookPublisher(
+		dynamodb.NewDynamoWebhookRepository(dynamoClient),
+		dynamodb.NewDynamoWebhookDeliveryRepository(dynamoClient),
+		sqs.NewSQSWebhookQueue(sqsClient),
+	)
+
+	processUsecase := usecases.NewProcessVideoUsecase(videoRepository, storageService, notificationService, videoProber, usecases.HLSLadderFromEnv(), usecases.RetryPolicyFromEnv(), webhookPublisher, dynamodb.NewDynamoUsageRepository(dynamoClient))
+
+	visibilityTimeout := time.Duration(utils.GetEnvInt("SQS_VISIBILITY_TIMEOUT_SECONDS", 300)) * time.Second
+
+	receiveCtx, stopReceiving := context.WithCancel(ctx)
+	processCtx, abortProcessing := context.WithCancel(ctx)
 
 	return &SQSConsumer{
-		Ctx:        ctx,
-		VideoQueue: videoQueue,
-		Usecase:    processUsecase,
+		Ctx:               ctx,
+		VideoQueue:        videoQueue,
+		Usecase:           processUsecase,
+		Concurrency:       max(utils.GetEnvInt("WORKER_CONCURRENCY", 2), 1),
+		VisibilityTimeout: visibilityTimeout,
+		HeartbeatInterval: visibilityTimeout / 3,
+		receiveCtx:        receiveCtx,
+		stopReceiving:     stopReceiving,
+		processCtx:        processCtx,
+		abortProcessing:   abortProcessing,
+		done:              make(chan struct{}),
 	}
 }
 
+// Start runs up to Concurrency workers. It only receives messages when a
+// worker is free, and no more than there are free workers, so messages never
+// wait in the consumer while their visibility timeout runs out. It returns
+// once Shutdown has stopped it and the messages in flight are done.
 func (c *SQSConsumer) Start() {
-	log.Println("🎬 Video processing worker started")
+	defer close(c.done)
+	log.Printf("🎬 Video processing worker started with %d workers", c.Concurrency)
 
-	for {
+	workers := make(chan struct{}, c.Concurrency)
+	var inFlight sync.WaitGroup
+
+	for c.receiveCtx.Err() == nil {
+		// Wait for a free worker, then claim every other free one
 		select {
-		case <-c.Ctx.Done():
-			log.Println("Consumer shutting down")
-			return
-		default:
-			messages, err := c.VideoQueue.Get(c.Ctx)
-			if err != nil {
-				log.Println("[QUEUE_READ] Consumer error:", err)
-				time.Sleep(5 * time.Second)
-				continue
+		case <-c.receiveCtx.Done():
+			continue
+		case workers <- struct{}{}:
+		}
+		free := 1
+	claim:
+		for free < min(c.Concurrency, MAX_RECEIVE_MESSAGES) {
+			select {
+			case workers <- struct{}{}:
+				free++
+			default:
+				break claim
 			}
+		}
 
-			if len(messages) == 0 {
+		messages, err := c.VideoQueue.Get(c.receiveCtx, free, c.VisibilityTimeout)
+		if err != nil {
+			releaseWorkers(workers, free)
+			if c.receiveCtx.Err() != nil {
 				continue
 			}
+			log.Println("[QUEUE_READ] Consumer error:", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
 
-			log.Printf("Received %d messages from queue", len(messages))
+		releaseWorkers(workers, free-len(messages))
 
-			for _, message := range messages {
-				var input dto.VideoProcessMessage
+		if len(messages) == 0 {
+			continue
+		}
 
-				data := []byte(*message.Body)
+		log.Printf("Received %d messages from queue", len(messages))
 
-				err := json.Unmarshal(data, &input)
-				if err != nil {
-					log.Println("[INVALID_DATA] Consumer error:", err)
-					c.VideoQueue.Delete(c.Ctx, message)
-					continue
-				}
+		for _, message := range messages {
+			inFlight.Add(1)
+			go func(message types.Message) {
+				defer func() {
+					<-workers
+					inFlight.Done()
+				}()
+				c.handleMessage(message)
+			}(message)
+		}
+	}
 
-				log.Printf("Processing video: %s", input.VideoID)
+	log.Println("Consumer shutting down")
+	inFlight.Wait()
+}
 
-				err = c.Usecase.Execute(c.Ctx, input)
-				if err != nil {
-					log.Println("[USE_CASE_ERR] Consumer error:", err)
-					continue
-				}
+// Shutdown stops receiving messages and waits for the ones in flight to
+// finish. If ctx expires first, their processing is cancelled and they are
+// made visible again right away, so another worker picks them up without
+// waiting out the visibility timeout.
+func (c *SQSConsumer) Shutdown(ctx context.Context) error {
+	c.stopReceiving()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutdown deadline reached, releasing messages in flight")
+	c.abortProcessing()
+	<-c.done
+	return ctx.Err()
+}
 
-				if err := c.VideoQueue.Delete(c.Ctx, message); err != nil {
-					log.Println("[DELETE_ERR] Failed to delete message:", err)
-				} else {
-					log.Printf("Successfully processed and deleted message for video: %s", input.VideoID)
+func (c *SQSConsumer) handleMessage(message types.Message) {
+	var input dto.VideoProcessMessage
+
+	data := []byte(*message.Body)
+
+	err := json.Unmarshal(data, &input)
+	if err != nil {
+		log.Println("[INVALID_DATA] Consumer error:", err)
+		c.VideoQueue.Delete(c.Ctx, message)
+		return
+	}
+
+	attempt := receiveCount(message)
+	log.Printf("Processing video: %s (attempt %d)", input.VideoID, attempt)
+
+	stopHeartbeat := c.startHeartbeat(message, input.VideoID)
+	err = c.Usecase.Execute(c.processCtx, input, attempt)
+	stopHeartbeat()
+
+	if c.processCtx.Err() != nil {
+		log.Printf("[RELEASED] Processing of video %s interrupted by shutdown", input.VideoID)
+		if err := c.VideoQueue.ChangeVisibility(c.Ctx, message, 0); err != nil {
+			log.Println("[VISIBILITY_ERR] Failed to release message:", err)
+		}
+		return
+	}
+
+	var retryErr *usecases.RetryError
+	if errors.As(err, &retryErr) {
+		// Leave the message on the queue and let it reappear after the backoff
+		log.Printf("[USE_CASE_RETRY] Retrying video %s in %s: %v", input.VideoID, retryErr.Delay, retryErr.Err)
+		if err := c.VideoQueue.ChangeVisibility(c.Ctx, message, retryErr.Delay); err != nil {
+			log.Println("[VISIBILITY_ERR] Failed to delay retry:", err)
+		}
+		return
+	}
+
+	if err != nil {
+		log.Println("[USE_CASE_ERR] Consumer error:", err)
+		if err := c.VideoQueue.DeadLetter(c.Ctx, message, err.Error()); err != nil {
+			log.Println("[DEAD_LETTER_ERR] Failed to move message to dead-letter queue:", err)
+		}
+		return
+	}
+
+	if err := c.VideoQueue.Delete(c.Ctx, message); err != nil {
+		log.Println("[DELETE_ERR] Failed to delete message:", err)
+	} else {
+		log.Printf("Successfully processed and deleted message for video: %s", input.VideoID)
+	}
+}
+
+// receiveCount is how many times SQS has delivered the message, including
+// this time.
+func receiveCount(message types.Message) int {
+	count, err := strconv.Atoi(message.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+	if err != nil || count < 1 {
+		return 1
+	}
+	return count
+}
+
+// startHeartbeat keeps the message hidden while it is processed, so a long
+// video is not delivered to another worker halfway through. The returned
+// function stops it.
+func (c *SQSConsumer) startHeartbeat(message types.Message, videoID string) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(c.HeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := c.VideoQueue.ChangeVisibility(c.Ctx, message, c.VisibilityTimeout); err != nil {
+					log.Printf("[HEARTBEAT_ERR] Failed to extend visibility for video %s: %v", videoID, err)
 				}
 			}
 		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func releaseWorkers(workers chan struct{}, count int) {
+	for i := 0; i < count; i++ {
+		<-workers
 	}
 }
@@ -0,0 +1,175 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/dynamodb"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/sqs"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/webhook"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
+	awsinfra "github.com/cks-solutions/hackathon/ms-video/internal/infra/aws"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// WebhookConsumer posts queued webhook deliveries, apart from the video
+// workers so a slow receiver never holds up processing.
+type WebhookConsumer struct {
+	Ctx          context.Context
+	WebhookQueue ports.WebhookQueue
+	Usecase      *usecases.DeliverWebhookUsecase
+
+	// Concurrency is how many deliveries are posted at the same time.
+	Concurrency int
+	// VisibilityTimeout is how long a received message stays hidden; it
+	// has to outlast the webhook client's timeout.
+	VisibilityTimeout time.Duration
+
+	receiveCtx      context.Context
+	stopReceiving   context.CancelFunc
+	deliverCtx      context.Context
+	abortDeliveries context.CancelFunc
+	done            chan struct{}
+}
+
+func NewWebhookConsumer(ctx context.Context, region awsinfra.Region, stage awsinfra.Stage) *WebhookConsumer {
+	dynamoClient := awsinfra.NewDynamoClient(region, stage)
+	sqsClient := awsinfra.NewSQSClient(region, stage)
+
+	deliverUsecase := usecases.NewDeliverWebhookUsecase(
+		dynamodb.NewDynamoWebhookDeliveryRepository(dynamoClient),
+		dynamodb.NewDynamoWebhookRepository(dynamoClient),
+		webhook.NewHTTPWebhookClient(),
+		usecases.WebhookRetryPolicyFromEnv(),
+	)
+
+	receiveCtx, stopReceiving := context.WithCancel(ctx)
+	deliverCtx, abortDeliveries := context.WithCancel(ctx)
+
+	return &WebhookConsumer{
+		Ctx:               ctx,
+		WebhookQueue:      sqs.NewSQSWebhookQueue(sqsClient),
+		Usecase:           deliverUsecase,
+		Concurrency:       max(utils.GetEnvInt("WEBHOOK_CONCURRENCY", 10), 1),
+		VisibilityTimeout: 60 * time.Second,
+		receiveCtx:        receiveCtx,
+		stopReceiving:     stopReceiving,
+		deliverCtx:        deliverCtx,
+		abortDeliveries:   abortDeliveries,
+		done:              make(chan struct{}),
+	}
+}
+
+// Start posts up to Concurrency deliveries at a time until Shutdown stops
+// it, then waits for the ones in flight.
+func (c *WebhookConsumer) Start() {
+	defer close(c.done)
+	log.Printf("🔔 Webhook delivery worker started with %d workers", c.Concurrency)
+
+	workers := make(chan struct{}, c.Concurrency)
+	var inFlight sync.WaitGroup
+
+	for c.receiveCtx.Err() == nil {
+		select {
+		case <-c.receiveCtx.Done():
+			continue
+		case workers <- struct{}{}:
+		}
+		free := 1
+	claim:
+		for free < min(c.Concurrency, MAX_RECEIVE_MESSAGES) {
+			select {
+			case workers <- struct{}{}:
+				free++
+			default:
+				break claim
+			}
+		}
+
+		messages, err := c.WebhookQueue.Get(c.receiveCtx, free, c.VisibilityTimeout)
+		if err != nil {
+			releaseWorkers(workers, free)
+			if c.receiveCtx.Err() != nil {
+				continue
+			}
+			log.Println("[WEBHOOK_QUEUE_READ] Consumer error:", err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+
+		releaseWorkers(workers, free-len(messages))
+
+		for _, message := range messages {
+			inFlight.Add(1)
+			go func(message types.Message) {
+				defer func() {
+					<-workers
+					inFlight.Done()
+				}()
+				c.handleMessage(message)
+			}(message)
+		}
+	}
+
+	log.Println("Webhook consumer shutting down")
+	inFlight.Wait()
+}
+
+// Shutdown stops receiving deliveries and waits for the ones in flight. If
+// ctx expires first they are aborted and released for another worker.
+func (c *WebhookConsumer) Shutdown(ctx context.Context) error {
+	c.stopReceiving()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	c.abortDeliveries()
+	<-c.done
+	return ctx.Err()
+}
+
+func (c *WebhookConsumer) handleMessage(message types.Message) {
+	var input dto.WebhookDeliveryMessage
+	if err := json.Unmarshal([]byte(*message.Body), &input); err != nil {
+		log.Println("[INVALID_DATA] Webhook consumer error:", err)
+		c.WebhookQueue.Delete(c.Ctx, message)
+		return
+	}
+
+	err := c.Usecase.Execute(c.deliverCtx, input, receiveCount(message))
+
+	if c.deliverCtx.Err() != nil {
+		if err := c.WebhookQueue.ChangeVisibility(c.Ctx, message, 0); err != nil {
+			log.Println("[VISIBILITY_ERR] Failed to release webhook delivery:", err)
+		}
+		return
+	}
+
+	var retryErr *usecases.RetryError
+	if errors.As(err, &retryErr) {
+		log.Printf("[WEBHOOK_RETRY] Retrying webhook delivery %s in %s: %v", input.DeliveryID, retryErr.Delay, retryErr.Err)
+		if err := c.WebhookQueue.ChangeVisibility(c.Ctx, message, retryErr.Delay); err != nil {
+			log.Println("[VISIBILITY_ERR] Failed to delay webhook retry:", err)
+		}
+		return
+	}
+
+	// The outcome is in the delivery log, so failed deliveries are not kept
+	// on the queue
+	if err != nil {
+		log.Printf("[WEBHOOK_ERR] Webhook delivery %s failed: %v", input.DeliveryID, err)
+	}
+	if err := c.WebhookQueue.Delete(c.Ctx, message); err != nil {
+		log.Println("[DELETE_ERR] Failed to delete webhook delivery message:", err)
+	}
+}
@@ -0,0 +1,72 @@
+package sweeper
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/dynamodb"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driven/s3"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
+	awsinfra "github.com/cks-solutions/hackathon/ms-video/internal/infra/aws"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// RetentionSweeper applies the retention policy to every video, once or
+// periodically, as the sweep command mode of ms-video.
+type RetentionSweeper struct {
+	Usecase *usecases.SweepRetentionUsecase
+	DryRun  bool
+	// Interval is the time between sweeps. With zero it sweeps once, for
+	// running from a scheduler.
+	Interval time.Duration
+}
+
+func NewRetentionSweeper(region awsinfra.Region, stage awsinfra.Stage, dryRun bool) *RetentionSweeper {
+	dynamoClient := awsinfra.NewDynamoClient(region, stage)
+	s3Client := awsinfra.NewS3Client(region, stage)
+
+	sweepUsecase := usecases.NewSweepRetentionUsecase(
+		dynamodb.NewDynamoVideoRepository(dynamoClient),
+		s3.NewS3StorageService(s3Client),
+		dynamodb.NewDynamoUsageRepository(dynamoClient),
+		usecases.RetentionPolicyFromEnv(),
+	)
+
+	return &RetentionSweeper{
+		Usecase:  sweepUsecase,
+		DryRun:   dryRun,
+		Interval: time.Duration(max(utils.GetEnvInt("RETENTION_SWEEP_INTERVAL_MINUTES", 60), 0)) * time.Minute,
+	}
+}
+
+// Run sweeps until ctx is cancelled, or once without an Interval. A single
+// sweep returns its error; a periodic one logs it and tries again at the
+// next interval.
+func (s *RetentionSweeper) Run(ctx context.Context) error {
+	for {
+		err := s.sweep(ctx)
+		if s.Interval == 0 {
+			return err
+		}
+		if err != nil {
+			log.Println("[RETENTION_SWEEP] Sweep failed:", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(s.Interval):
+		}
+	}
+}
+
+func (s *RetentionSweeper) sweep(ctx context.Context) error {
+	log.Printf("Starting retention sweep (dry run: %v)", s.DryRun)
+	output, err := s.Usecase.Execute(ctx, s.DryRun)
+
+	summary, _ := json.Marshal(output)
+	log.Printf("Retention sweep finished: %s", summary)
+	return err
+}
@@ -0,0 +1,112 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+type DynamoUploadSessionRepository struct {
+	client *dynamodb.Client
+}
+
+const UPLOAD_SESSION_TABLE_NAME = "MSVideo.UploadSession"
+
+func NewDynamoUploadSessionRepository(client *dynamodb.Client) ports.UploadSessionRepository {
+	return &DynamoUploadSessionRepository{
+		client: client,
+	}
+}
+
+func (r *DynamoUploadSessionRepository) Save(ctx context.Context, session *entities.UploadSession) error {
+	item, err := attributevalue.MarshalMap(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal upload session: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(UPLOAD_SESSION_TABLE_NAME),
+		Item:      item,
+	})
+
+	return err
+}
+
+func (r *DynamoUploadSessionRepository) FindByID(ctx context.Context, sessionID string) (*entities.UploadSession, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(UPLOAD_SESSION_TABLE_NAME),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: sessionID},
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Item == nil {
+		return nil, ports.ErrUploadSessionNotFound
+	}
+
+	var session entities.UploadSession
+	if err := attributevalue.UnmarshalMap(result.Item, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal upload session: %w", err)
+	}
+
+	return &session, nil
+}
+
+func (r *DynamoUploadSessionRepository) Update(ctx context.Context, session *entities.UploadSession) error {
+	next := *session
+	next.Version++
+
+	item, err := attributevalue.MarshalMap(next)
+	if err != nil {
+		return fmt.Errorf("failed to marshal upload session: %w", err)
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(UPLOAD_SESSION_TABLE_NAME),
+		Item:      item,
+	}
+	// Sessions created before versioning have no version attribute
+	if session.Version == 0 {
+		input.ConditionExpression = aws.String("attribute_exists(id) AND attribute_not_exists(version)")
+	} else {
+		input.ConditionExpression = aws.String("version = :version")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{Value: strconv.FormatInt(session.Version, 10)},
+		}
+	}
+
+	_, err = r.client.PutItem(ctx, input)
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ports.ErrUploadSessionConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	session.Version = next.Version
+	return nil
+}
+
+func (r *DynamoUploadSessionRepository) Delete(ctx context.Context, sessionID string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(UPLOAD_SESSION_TABLE_NAME),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: sessionID},
+		},
+	})
+
+	return err
+}
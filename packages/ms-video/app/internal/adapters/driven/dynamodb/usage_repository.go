@@ -0,0 +1,218 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+type DynamoUsageRepository struct {
+	client *dynamodb.Client
+}
+
+const USAGE_TABLE_NAME = "MSVideo.Usage"
+
+// usageWriteAttempts bounds how often a write starts over because a
+// concurrent write changed the usage between its read and its update.
+const usageWriteAttempts = 3
+
+func NewDynamoUsageRepository(client *dynamodb.Client) ports.UsageRepository {
+	return &DynamoUsageRepository{
+		client: client,
+	}
+}
+
+func (r *DynamoUsageRepository) Get(ctx context.Context, userID string) (*entities.Usage, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(USAGE_TABLE_NAME),
+		Key: map[string]types.AttributeValue{
+			"user_id": &types.AttributeValueMemberS{Value: userID},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	usage := entities.Usage{UserID: userID}
+	if result.Item == nil {
+		return &usage, nil
+	}
+
+	if err := attributevalue.UnmarshalMap(result.Item, &usage); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal usage: %w", err)
+	}
+
+	return &usage, nil
+}
+
+// Reserve checks the quotas in the condition of the update itself, so the
+// counters never pass a limit however many uploads race. The hourly window
+// either still is the stored one, and its uploads are incremented, or it
+// has rolled over, and they start again from one.
+func (r *DynamoUsageRepository) Reserve(ctx context.Context, userID string, bytes int64, limits entities.UsageLimits, now time.Time) error {
+	if limits.MaxStoredBytes > 0 && bytes > limits.MaxStoredBytes {
+		return &ports.QuotaExceededError{Quota: entities.QuotaStorage}
+	}
+
+	updatedAt, err := attributevalue.Marshal(now)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage: %w", err)
+	}
+
+	values := map[string]types.AttributeValue{
+		":bytes":      numberValue(bytes),
+		":one":        numberValue(1),
+		":window":     numberValue(entities.UploadWindowStart(now)),
+		":updated_at": updatedAt,
+	}
+	var conditions []string
+	if limits.MaxStoredBytes > 0 {
+		conditions = append(conditions, "(attribute_not_exists(stored_bytes) OR stored_bytes <= :max_stored_before)")
+		values[":max_stored_before"] = numberValue(limits.MaxStoredBytes - bytes)
+	}
+	if limits.MaxVideos > 0 {
+		conditions = append(conditions, "(attribute_not_exists(video_count) OR video_count < :max_videos)")
+		values[":max_videos"] = numberValue(int64(limits.MaxVideos))
+	}
+
+	sameWindow := append([]string{"upload_window = :window"}, conditions...)
+	if limits.MaxUploadsPerHour > 0 {
+		sameWindow = append(sameWindow, "window_uploads < :max_uploads")
+		values[":max_uploads"] = numberValue(int64(limits.MaxUploadsPerHour))
+	}
+	newWindow := append([]string{"(attribute_not_exists(upload_window) OR upload_window <> :window)"}, conditions...)
+	newWindowValues := maps.Clone(values)
+	delete(newWindowValues, ":max_uploads")
+
+	for attempt := 1; attempt <= usageWriteAttempts; attempt++ {
+		err := r.update(ctx, userID,
+			"ADD stored_bytes :bytes, video_count :one, window_uploads :one SET updated_at = :updated_at",
+			strings.Join(sameWindow, " AND "), values)
+		if !isConditionFailed(err) {
+			return err
+		}
+
+		err = r.update(ctx, userID,
+			"ADD stored_bytes :bytes, video_count :one SET upload_window = :window, window_uploads = :one, updated_at = :updated_at",
+			strings.Join(newWindow, " AND "), newWindowValues)
+		if !isConditionFailed(err) {
+			return err
+		}
+
+		usage, err := r.Get(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if quota := usage.Exceeded(limits, bytes, now); quota != "" {
+			return &ports.QuotaExceededError{Quota: quota}
+		}
+		// The window was rolled over by a concurrent upload in between
+	}
+
+	return fmt.Errorf("usage of user %s kept changing concurrently", userID)
+}
+
+// Adjust adds the deltas in a single update unless that would take a
+// counter below zero, which happens for videos uploaded before usage was
+// tracked. Those counters are clamped at zero instead.
+func (r *DynamoUsageRepository) Adjust(ctx context.Context, userID string, bytes int64, videos int) error {
+	if bytes == 0 && videos == 0 {
+		return nil
+	}
+
+	updatedAt, err := attributevalue.Marshal(time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage: %w", err)
+	}
+
+	values := map[string]types.AttributeValue{
+		":bytes":      numberValue(bytes),
+		":videos":     numberValue(int64(videos)),
+		":updated_at": updatedAt,
+	}
+	var conditions []string
+	if bytes < 0 {
+		conditions = append(conditions, "stored_bytes >= :min_bytes")
+		values[":min_bytes"] = numberValue(-bytes)
+	}
+	if videos < 0 {
+		conditions = append(conditions, "video_count >= :min_videos")
+		values[":min_videos"] = numberValue(int64(-videos))
+	}
+
+	err = r.update(ctx, userID, "ADD stored_bytes :bytes, video_count :videos SET updated_at = :updated_at", strings.Join(conditions, " AND "), values)
+	if !isConditionFailed(err) {
+		return err
+	}
+
+	for attempt := 1; attempt <= usageWriteAttempts; attempt++ {
+		usage, err := r.Get(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		// Only written if nothing else updated the usage since it was read
+		updatedAt, err := attributevalue.Marshal(time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to marshal usage: %w", err)
+		}
+		values := map[string]types.AttributeValue{
+			":bytes":      numberValue(max(usage.StoredBytes+bytes, 0)),
+			":videos":     numberValue(int64(max(usage.VideoCount+videos, 0))),
+			":updated_at": updatedAt,
+		}
+		condition := "attribute_not_exists(updated_at)"
+		if !usage.UpdatedAt.IsZero() {
+			condition = "updated_at = :read_at"
+			if values[":read_at"], err = attributevalue.Marshal(usage.UpdatedAt); err != nil {
+				return fmt.Errorf("failed to marshal usage: %w", err)
+			}
+		}
+
+		err = r.update(ctx, userID, "SET stored_bytes = :bytes, video_count = :videos, updated_at = :updated_at", condition, values)
+		if !isConditionFailed(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("usage of user %s kept changing concurrently", userID)
+}
+
+func (r *DynamoUsageRepository) update(ctx context.Context, userID, expression, condition string, values map[string]types.AttributeValue) error {
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(USAGE_TABLE_NAME),
+		Key: map[string]types.AttributeValue{
+			"user_id": &types.AttributeValueMemberS{Value: userID},
+		},
+		UpdateExpression:          aws.String(expression),
+		ExpressionAttributeValues: values,
+	}
+	if condition != "" {
+		input.ConditionExpression = aws.String(condition)
+	}
+
+	_, err := r.client.UpdateItem(ctx, input)
+	return err
+}
+
+func numberValue(n int64) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(n, 10)}
+}
+
+func isConditionFailed(err error) bool {
+	var conditionFailed *types.ConditionalCheckFailedException
+	return errors.As(err, &conditionFailed)
+}
@@ -2,7 +2,11 @@ package dynamodb
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -25,17 +29,7 @@ func NewDynamoVideoRepository(client *dynamodb.Client) ports.VideoRepository {
 }
 
 func (r *DynamoVideoRepository) Save(ctx context.Context, video *entities.Video) error {
-	item, err := attributevalue.MarshalMap(video)
-	if err != nil {
-		return fmt.Errorf("failed to marshal video: %w", err)
-	}
-
-	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(TABLE_NAME),
-		Item:      item,
-	})
-
-	return err
+	return r.put(ctx, video, "attribute_not_exists(id)", nil)
 }
 
 func (r *DynamoVideoRepository) FindByID(ctx context.Context, videoID string) (*entities.Video, error) {
@@ -51,7 +45,7 @@ func (r *DynamoVideoRepository) FindByID(ctx context.Context, videoID string) (*
 	}
 
 	if result.Item == nil {
-		return nil, fmt.Errorf("video not found")
+		return nil, ports.ErrVideoNotFound
 	}
 
 	var video entities.Video
@@ -62,17 +56,86 @@ func (r *DynamoVideoRepository) FindByID(ctx context.Context, videoID string) (*
 	return &video, nil
 }
 
-func (r *DynamoVideoRepository) FindByUserID(ctx context.Context, userID string) ([]*entities.Video, error) {
+// FindByUserID queries the index sorted by query.SortBy. With a status
+// filter DynamoDB counts the videos it skips against Limit, so it keeps
+// querying until the page is full or the user has no more videos.
+func (r *DynamoVideoRepository) FindByUserID(ctx context.Context, userID string, query ports.VideoListQuery) (*ports.VideoPage, error) {
+	sortKey := string(query.SortBy)
+	startKey, err := decodeCursor(query.Cursor, userID, sortKey)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]types.AttributeValue{
+		":user_id": &types.AttributeValueMemberS{Value: userID},
+	}
+	var filter *string
+	var names map[string]string
+	if query.Status != "" {
+		filter = aws.String("#status = :status")
+		names = map[string]string{"#status": "status"}
+		values[":status"] = &types.AttributeValueMemberS{Value: string(query.Status)}
+	}
+
+	videos := make([]*entities.Video, 0, query.Limit)
+	for {
+		result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(TABLE_NAME),
+			IndexName:                 aws.String(userIndexName(query.SortBy)),
+			KeyConditionExpression:    aws.String("user_id = :user_id"),
+			FilterExpression:          filter,
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ExclusiveStartKey:         startKey,
+			Limit:                     aws.Int32(int32(query.Limit - len(videos))),
+			ScanIndexForward:          aws.Bool(query.Ascending),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range result.Items {
+			var video entities.Video
+			if err := attributevalue.UnmarshalMap(item, &video); err != nil {
+				continue
+			}
+			videos = append(videos, &video)
+		}
+
+		startKey = result.LastEvaluatedKey
+		if startKey == nil || len(videos) >= query.Limit {
+			break
+		}
+	}
+
+	page := &ports.VideoPage{Videos: videos}
+	if startKey != nil {
+		page.NextCursor = encodeCursor(startKey)
+	}
+	return page, nil
+}
+
+func userIndexName(sortBy ports.VideoSortField) string {
+	if sortBy == ports.VideoSortUpdatedAt {
+		return "user_id-updated_at-index"
+	}
+	return "user_id-index"
+}
+
+// FindByContentHash queries the sparse index of content hashes, which only
+// holds videos whose upload was hashed. Index reads are eventually
+// consistent, so a video saved a moment ago may be missing.
+func (r *DynamoVideoRepository) FindByContentHash(ctx context.Context, contentHash string, limit int) ([]*entities.Video, error) {
 	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
 		TableName:              aws.String(TABLE_NAME),
-		IndexName:              aws.String("user_id-index"),
-		KeyConditionExpression: aws.String("user_id = :user_id"),
+		IndexName:              aws.String("content_hash-index"),
+		KeyConditionExpression: aws.String("content_hash = :content_hash"),
 		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":user_id": &types.AttributeValueMemberS{Value: userID},
+			":content_hash": &types.AttributeValueMemberS{Value: contentHash},
 		},
+		Limit:            aws.Int32(int32(limit)),
 		ScanIndexForward: aws.Bool(false),
 	})
-
 	if err != nil {
 		return nil, err
 	}
@@ -89,16 +152,168 @@ func (r *DynamoVideoRepository) FindByUserID(ctx context.Context, userID string)
 	return videos, nil
 }
 
+// FindProcessed scans the whole table. The status filter is applied after
+// Limit items were read, so pages can be short or empty.
+func (r *DynamoVideoRepository) FindProcessed(ctx context.Context, cursor string, limit int) (*ports.VideoPage, error) {
+	startKey, err := decodeScanCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.client.Scan(ctx, &dynamodb.ScanInput{
+		TableName:                aws.String(TABLE_NAME),
+		FilterExpression:         aws.String("#status IN (:completed, :expired)"),
+		ExpressionAttributeNames: map[string]string{"#status": "status"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":completed": &types.AttributeValueMemberS{Value: string(entities.VideoStatusCompleted)},
+			":expired":   &types.AttributeValueMemberS{Value: string(entities.VideoStatusExpired)},
+		},
+		ExclusiveStartKey: startKey,
+		Limit:             aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	videos := make([]*entities.Video, 0, len(result.Items))
+	for _, item := range result.Items {
+		var video entities.Video
+		if err := attributevalue.UnmarshalMap(item, &video); err != nil {
+			continue
+		}
+		videos = append(videos, &video)
+	}
+
+	page := &ports.VideoPage{Videos: videos}
+	if result.LastEvaluatedKey != nil {
+		page.NextCursor = encodeCursor(result.LastEvaluatedKey)
+	}
+	return page, nil
+}
+
+// encodeCursor turns the last evaluated key, which only holds string
+// attributes, into an opaque token.
+func encodeCursor(key map[string]types.AttributeValue) string {
+	values := make(map[string]string, len(key))
+	for name, value := range key {
+		if s, ok := value.(*types.AttributeValueMemberS); ok {
+			values[name] = s.Value
+		}
+	}
+
+	data, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor accepts only tokens from a listing of the user's videos in
+// the same sort order, so a token cannot be used to read another user's.
+func decodeCursor(cursor, userID, sortKey string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ports.ErrInvalidCursor
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, ports.ErrInvalidCursor
+	}
+	if len(values) != 3 || values["id"] == "" || values["user_id"] != userID || values[sortKey] == "" {
+		return nil, ports.ErrInvalidCursor
+	}
+
+	key := make(map[string]types.AttributeValue, len(values))
+	for name, value := range values {
+		key[name] = &types.AttributeValueMemberS{Value: value}
+	}
+	return key, nil
+}
+
+// decodeScanCursor accepts only tokens from a scan of the table, which are
+// keyed by id alone.
+func decodeScanCursor(cursor string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ports.ErrInvalidCursor
+	}
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil || len(values) != 1 || values["id"] == "" {
+		return nil, ports.ErrInvalidCursor
+	}
+
+	return map[string]types.AttributeValue{
+		"id": &types.AttributeValueMemberS{Value: values["id"]},
+	}, nil
+}
+
 func (r *DynamoVideoRepository) Update(ctx context.Context, video *entities.Video) error {
-	item, err := attributevalue.MarshalMap(video)
+	condition, values := versionCondition(video)
+	return r.put(ctx, video, condition, values)
+}
+
+func (r *DynamoVideoRepository) Delete(ctx context.Context, video *entities.Video) error {
+	condition, values := versionCondition(video)
+
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(TABLE_NAME),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: video.ID},
+		},
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeValues: values,
+	})
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return &ports.VersionConflictError{VideoID: video.ID, Version: video.Version}
+	}
+	return err
+}
+
+// versionCondition holds only while the stored video is at video.Version.
+func versionCondition(video *entities.Video) (string, map[string]types.AttributeValue) {
+	// Videos written before versioning have no version attribute
+	if video.Version == 0 {
+		return "attribute_exists(id) AND attribute_not_exists(version)", nil
+	}
+
+	return "version = :version", map[string]types.AttributeValue{
+		":version": &types.AttributeValueMemberN{Value: strconv.FormatInt(video.Version, 10)},
+	}
+}
+
+// put writes the next version of the video if condition holds, and bumps
+// video.Version once it is stored.
+func (r *DynamoVideoRepository) put(ctx context.Context, video *entities.Video, condition string, values map[string]types.AttributeValue) error {
+	next := *video
+	next.Version++
+
+	item, err := attributevalue.MarshalMap(next)
 	if err != nil {
 		return fmt.Errorf("failed to marshal video: %w", err)
 	}
 
 	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(TABLE_NAME),
-		Item:      item,
+		TableName:                 aws.String(TABLE_NAME),
+		Item:                      item,
+		ConditionExpression:       aws.String(condition),
+		ExpressionAttributeValues: values,
 	})
 
-	return err
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return &ports.VersionConflictError{VideoID: video.ID, Version: video.Version}
+	}
+	if err != nil {
+		return err
+	}
+
+	video.Version = next.Version
+	return nil
 }
@@ -0,0 +1,236 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	streamtypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+// DynamoDB Streams throttles a shard read by more than about two readers at
+// once, so the poll interval doubles up to streamMaxPollInterval while reads
+// are throttled.
+const (
+	streamPollInterval     = time.Second
+	streamMaxPollInterval  = 30 * time.Second
+	streamDescribeInterval = time.Minute
+)
+
+// VideoStreamReader publishes the videos written to the video table, read
+// from its DynamoDB stream. Every replica reads the whole stream, so its
+// subscribers see the writes of all workers, and each one counts as a reader
+// of every shard.
+type VideoStreamReader struct {
+	client        *dynamodb.Client
+	streamsClient *dynamodbstreams.Client
+	publisher     ports.VideoEventPublisher
+}
+
+func NewVideoStreamReader(client *dynamodb.Client, streamsClient *dynamodbstreams.Client, publisher ports.VideoEventPublisher) *VideoStreamReader {
+	return &VideoStreamReader{
+		client:        client,
+		streamsClient: streamsClient,
+		publisher:     publisher,
+	}
+}
+
+// Run publishes the writes made from the time it starts until ctx is done.
+// It only returns early when the table has no stream to read.
+func (r *VideoStreamReader) Run(ctx context.Context) error {
+	table, err := r.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(TABLE_NAME),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe video table: %w", err)
+	}
+	if table.Table.LatestStreamArn == nil {
+		return errors.New("video table has no stream enabled")
+	}
+
+	stream := &videoStream{
+		arn:       *table.Table.LatestStreamArn,
+		iterators: make(map[string]*string),
+		seen:      make(map[string]bool),
+	}
+
+	interval := streamPollInterval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var described time.Time
+	for {
+		if time.Since(described) >= streamDescribeInterval {
+			if err := r.discoverShards(ctx, stream); err != nil {
+				log.Printf("Failed to describe video stream: %v", err)
+			} else {
+				described = time.Now()
+			}
+		}
+
+		throttled := false
+		for shardID, iterator := range stream.iterators {
+			next, err := r.readShard(ctx, stream, shardID, iterator)
+			var limited *streamtypes.LimitExceededException
+			if errors.As(err, &limited) {
+				throttled = true
+				break
+			}
+			if err != nil {
+				log.Printf("Failed to read video stream shard %s: %v", shardID, err)
+				continue
+			}
+			if next == nil {
+				// The shard was split or closed; look for its children
+				delete(stream.iterators, shardID)
+				described = time.Time{}
+				continue
+			}
+			stream.iterators[shardID] = next
+		}
+
+		if next := nextPollInterval(interval, throttled); next != interval {
+			if throttled {
+				log.Printf("Video stream reads are throttled, polling every %v", next)
+			}
+			interval = next
+			ticker.Reset(interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func nextPollInterval(interval time.Duration, throttled bool) time.Duration {
+	if !throttled {
+		return streamPollInterval
+	}
+	return min(2*interval, streamMaxPollInterval)
+}
+
+type videoStream struct {
+	arn string
+	// iterators holds the position in each open shard being read
+	iterators map[string]*string
+	seen      map[string]bool
+	// discovered is set once all shards open at startup are being read
+	discovered bool
+}
+
+// discoverShards starts reading the shards it has not seen yet. Shards open
+// at startup are read from their latest record; shards that appear later are
+// children of ones already read, so they are read from the start.
+func (r *VideoStreamReader) discoverShards(ctx context.Context, stream *videoStream) error {
+	initial := !stream.discovered
+	var startShardID *string
+	for {
+		result, err := r.streamsClient.DescribeStream(ctx, &dynamodbstreams.DescribeStreamInput{
+			StreamArn:             aws.String(stream.arn),
+			ExclusiveStartShardId: startShardID,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, shard := range result.StreamDescription.Shards {
+			shardID := aws.ToString(shard.ShardId)
+			if stream.seen[shardID] {
+				continue
+			}
+			stream.seen[shardID] = true
+
+			closed := shard.SequenceNumberRange != nil && shard.SequenceNumberRange.EndingSequenceNumber != nil
+			if initial && closed {
+				continue
+			}
+
+			iteratorType := streamtypes.ShardIteratorTypeTrimHorizon
+			if initial {
+				iteratorType = streamtypes.ShardIteratorTypeLatest
+			}
+			iterator, err := r.shardIterator(ctx, stream, shardID, iteratorType)
+			if err != nil {
+				delete(stream.seen, shardID)
+				return err
+			}
+			stream.iterators[shardID] = iterator
+		}
+
+		startShardID = result.StreamDescription.LastEvaluatedShardId
+		if startShardID == nil {
+			stream.discovered = true
+			return nil
+		}
+	}
+}
+
+func (r *VideoStreamReader) shardIterator(ctx context.Context, stream *videoStream, shardID string, iteratorType streamtypes.ShardIteratorType) (*string, error) {
+	result, err := r.streamsClient.GetShardIterator(ctx, &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         aws.String(stream.arn),
+		ShardId:           aws.String(shardID),
+		ShardIteratorType: iteratorType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.ShardIterator, nil
+}
+
+// readShard publishes the records after iterator and returns the iterator
+// to continue from, or nil once the shard is closed and fully read.
+func (r *VideoStreamReader) readShard(ctx context.Context, stream *videoStream, shardID string, iterator *string) (*string, error) {
+	result, err := r.streamsClient.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{
+		ShardIterator: iterator,
+	})
+
+	var expired *streamtypes.ExpiredIteratorException
+	var trimmed *streamtypes.TrimmedDataAccessException
+	if errors.As(err, &expired) || errors.As(err, &trimmed) {
+		// Records in between are lost, which only delays a state until the
+		// next write
+		return r.shardIterator(ctx, stream, shardID, streamtypes.ShardIteratorTypeLatest)
+	}
+	if err != nil {
+		return iterator, err
+	}
+
+	for _, record := range result.Records {
+		if record.EventName == streamtypes.OperationTypeRemove || record.Dynamodb == nil || record.Dynamodb.NewImage == nil {
+			continue
+		}
+
+		video, err := unmarshalStreamVideo(record.Dynamodb.NewImage)
+		if err != nil {
+			log.Printf("Skipping video stream record: %v", err)
+			continue
+		}
+		r.publisher.Publish(video)
+	}
+
+	return result.NextShardIterator, nil
+}
+
+func unmarshalStreamVideo(image map[string]streamtypes.AttributeValue) (*entities.Video, error) {
+	item, err := attributevalue.FromDynamoDBStreamsMap(image)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert video: %w", err)
+	}
+
+	var video entities.Video
+	if err := attributevalue.UnmarshalMap(item, &video); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal video: %w", err)
+	}
+	return &video, nil
+}
@@ -0,0 +1,116 @@
+package dynamodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+// DynamoWebhookDeliveryRepository keeps the delivery log. Items expire
+// through the table's TTL on expires_at.
+type DynamoWebhookDeliveryRepository struct {
+	client *dynamodb.Client
+}
+
+const WEBHOOK_DELIVERY_TABLE_NAME = "MSVideo.WebhookDelivery"
+
+func NewDynamoWebhookDeliveryRepository(client *dynamodb.Client) ports.WebhookDeliveryRepository {
+	return &DynamoWebhookDeliveryRepository{
+		client: client,
+	}
+}
+
+func (r *DynamoWebhookDeliveryRepository) Save(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	err := r.put(ctx, delivery, "attribute_not_exists(id)")
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ports.ErrWebhookDeliveryExists
+	}
+	return err
+}
+
+func (r *DynamoWebhookDeliveryRepository) FindByID(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(WEBHOOK_DELIVERY_TABLE_NAME),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: deliveryID},
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Item == nil {
+		return nil, ports.ErrWebhookDeliveryNotFound
+	}
+
+	var delivery entities.WebhookDelivery
+	if err := attributevalue.UnmarshalMap(result.Item, &delivery); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook delivery: %w", err)
+	}
+
+	return &delivery, nil
+}
+
+// Update only writes deliveries that still exist, so a delivery whose log
+// entry has expired is not brought back.
+func (r *DynamoWebhookDeliveryRepository) Update(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	err := r.put(ctx, delivery, "attribute_exists(id)")
+
+	var conditionFailed *types.ConditionalCheckFailedException
+	if errors.As(err, &conditionFailed) {
+		return ports.ErrWebhookDeliveryNotFound
+	}
+	return err
+}
+
+func (r *DynamoWebhookDeliveryRepository) FindByWebhookID(ctx context.Context, webhookID string, limit int) ([]*entities.WebhookDelivery, error) {
+	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(WEBHOOK_DELIVERY_TABLE_NAME),
+		IndexName:              aws.String("webhook_id-created_at-index"),
+		KeyConditionExpression: aws.String("webhook_id = :webhook_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":webhook_id": &types.AttributeValueMemberS{Value: webhookID},
+		},
+		Limit:            aws.Int32(int32(limit)),
+		ScanIndexForward: aws.Bool(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*entities.WebhookDelivery, 0, len(result.Items))
+	for _, item := range result.Items {
+		var delivery entities.WebhookDelivery
+		if err := attributevalue.UnmarshalMap(item, &delivery); err != nil {
+			continue
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (r *DynamoWebhookDeliveryRepository) put(ctx context.Context, delivery *entities.WebhookDelivery, condition string) error {
+	item, err := attributevalue.MarshalMap(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook delivery: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(WEBHOOK_DELIVERY_TABLE_NAME),
+		Item:                item,
+		ConditionExpression: aws.String(condition),
+	})
+
+	return err
+}
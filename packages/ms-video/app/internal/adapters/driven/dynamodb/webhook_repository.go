@@ -0,0 +1,108 @@
+package dynamodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+type DynamoWebhookRepository struct {
+	client *dynamodb.Client
+}
+
+const WEBHOOK_TABLE_NAME = "MSVideo.Webhook"
+
+func NewDynamoWebhookRepository(client *dynamodb.Client) ports.WebhookRepository {
+	return &DynamoWebhookRepository{
+		client: client,
+	}
+}
+
+func (r *DynamoWebhookRepository) Save(ctx context.Context, webhook *entities.Webhook) error {
+	item, err := attributevalue.MarshalMap(webhook)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(WEBHOOK_TABLE_NAME),
+		Item:      item,
+	})
+
+	return err
+}
+
+func (r *DynamoWebhookRepository) FindByID(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(WEBHOOK_TABLE_NAME),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: webhookID},
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Item == nil {
+		return nil, ports.ErrWebhookNotFound
+	}
+
+	var webhook entities.Webhook
+	if err := attributevalue.UnmarshalMap(result.Item, &webhook); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook: %w", err)
+	}
+
+	return &webhook, nil
+}
+
+// FindByUserID returns every webhook of the user; there are at most a few.
+func (r *DynamoWebhookRepository) FindByUserID(ctx context.Context, userID string) ([]*entities.Webhook, error) {
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:              aws.String(WEBHOOK_TABLE_NAME),
+		IndexName:              aws.String("user_id-index"),
+		KeyConditionExpression: aws.String("user_id = :user_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":user_id": &types.AttributeValueMemberS{Value: userID},
+		},
+	})
+
+	var webhooks []*entities.Webhook
+	for paginator.HasMorePages() {
+		result, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range result.Items {
+			var webhook entities.Webhook
+			if err := attributevalue.UnmarshalMap(item, &webhook); err != nil {
+				continue
+			}
+			webhooks = append(webhooks, &webhook)
+		}
+	}
+
+	return webhooks, nil
+}
+
+func (r *DynamoWebhookRepository) Update(ctx context.Context, webhook *entities.Webhook) error {
+	return r.Save(ctx, webhook)
+}
+
+func (r *DynamoWebhookRepository) Delete(ctx context.Context, webhookID string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(WEBHOOK_TABLE_NAME),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: webhookID},
+		},
+	})
+
+	return err
+}
@@ -0,0 +1,63 @@
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+)
+
+// subscriberBuffer is how many states a subscriber may fall behind before it
+// starts missing them.
+const subscriberBuffer = 32
+
+// VideoEventBus fans saved video states out to the subscribers in this
+// process. It only sees what is published to it here, so with several
+// replicas it is fed from the video table's change stream.
+type VideoEventBus struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan *entities.Video]struct{}
+}
+
+func NewVideoEventBus() *VideoEventBus {
+	return &VideoEventBus{
+		subscribers: make(map[string]map[chan *entities.Video]struct{}),
+	}
+}
+
+func (b *VideoEventBus) Subscribe(ctx context.Context, userID string) <-chan *entities.Video {
+	updates := make(chan *entities.Video, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan *entities.Video]struct{})
+	}
+	b.subscribers[userID][updates] = struct{}{}
+	b.mu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers[userID], updates)
+		if len(b.subscribers[userID]) == 0 {
+			delete(b.subscribers, userID)
+		}
+		close(updates)
+	})
+
+	return updates
+}
+
+func (b *VideoEventBus) Publish(video *entities.Video) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for updates := range b.subscribers[video.UserID] {
+		select {
+		case updates <- video:
+		default:
+			log.Printf("Dropped update of video %s for a subscriber that fell behind", video.ID)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package ffprobe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+const PROBE_TIMEOUT = 30 * time.Second
+
+// Messages ffprobe prints when it could not reach a URL source, as opposed
+// to reaching it and finding something it cannot decode.
+var transportErrors = []string{
+	"Server returned",
+	"Connection refused",
+	"Connection timed out",
+	"Failed to resolve hostname",
+}
+
+type FFProbeVideoProber struct {
+	timeout time.Duration
+}
+
+func NewFFProbeVideoProber() ports.VideoProber {
+	return &FFProbeVideoProber{
+		timeout: PROBE_TIMEOUT,
+	}
+}
+
+type probeOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+	Streams []struct {
+		Index        int    `json:"index"`
+		CodecType    string `json:"codec_type"`
+		CodecName    string `json:"codec_name"`
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		AvgFrameRate string `json:"avg_frame_rate"`
+		RFrameRate   string `json:"r_frame_rate"`
+		BitRate      string `json:"bit_rate"`
+		Tags         struct {
+			Rotate string `json:"rotate"`
+		} `json:"tags"`
+		SideDataList []struct {
+			Rotation *float64 `json:"rotation"`
+		} `json:"side_data_list"`
+	} `json:"streams"`
+}
+
+func (p *FFProbeVideoProber) Probe(ctx context.Context, source string) (*entities.MediaInfo, error) {
+	timeout := p.timeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+
+	output, err := ffmpeg.ProbeWithTimeout(source, timeout, ffmpeg.KwArgs{"v": "error"})
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && !isTransportError(err.Error()) {
+			return nil, fmt.Errorf("%w: %v", ports.ErrInvalidVideo, err)
+		}
+		return nil, fmt.Errorf("failed to probe video: %w", err)
+	}
+
+	var probe probeOutput
+	if err := json.Unmarshal([]byte(output), &probe); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	info := &entities.MediaInfo{
+		FormatName: probe.Format.FormatName,
+		BitRate:    parseBitRate(probe.Format.BitRate),
+	}
+	if seconds, err := strconv.ParseFloat(probe.Format.Duration, 64); err == nil {
+		info.Duration = time.Duration(seconds * float64(time.Second))
+	}
+	for _, stream := range probe.Streams {
+		mediaStream := entities.MediaStream{
+			Index:     stream.Index,
+			CodecType: stream.CodecType,
+			CodecName: stream.CodecName,
+			Width:     stream.Width,
+			Height:    stream.Height,
+			BitRate:   parseBitRate(stream.BitRate),
+		}
+		if stream.CodecType == entities.StreamTypeVideo {
+			// The average is unknown (0/0) for some containers, the base
+			// rate is the closest ffprobe has then
+			mediaStream.FrameRate = parseFrameRate(stream.AvgFrameRate)
+			if mediaStream.FrameRate == 0 {
+				mediaStream.FrameRate = parseFrameRate(stream.RFrameRate)
+			}
+
+			// Older muxers tag the rotation, newer ones record a display
+			// matrix whose rotation is counterclockwise
+			if degrees, err := strconv.Atoi(stream.Tags.Rotate); err == nil {
+				mediaStream.Rotation = normalizeRotation(degrees)
+			} else {
+				for _, sideData := range stream.SideDataList {
+					if sideData.Rotation != nil {
+						mediaStream.Rotation = normalizeRotation(-int(math.Round(*sideData.Rotation)))
+						break
+					}
+				}
+			}
+		}
+		info.Streams = append(info.Streams, mediaStream)
+	}
+
+	return info, nil
+}
+
+// parseFrameRate reads a rate as ffprobe reports it, a fraction such as
+// "30000/1001", returning 0 when it is unknown.
+func parseFrameRate(rate string) float64 {
+	numerator, denominator, found := strings.Cut(rate, "/")
+	num, err := strconv.ParseFloat(numerator, 64)
+	if err != nil {
+		return 0
+	}
+	if !found {
+		return num
+	}
+	den, err := strconv.ParseFloat(denominator, 64)
+	if err != nil || den == 0 {
+		return 0
+	}
+	return num / den
+}
+
+func parseBitRate(bitRate string) int64 {
+	bps, err := strconv.ParseInt(bitRate, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return bps
+}
+
+func normalizeRotation(degrees int) int {
+	return ((degrees % 360) + 360) % 360
+}
+
+func isTransportError(message string) bool {
+	for _, transportError := range transportErrors {
+		if strings.Contains(message, transportError) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,12 +3,18 @@ package s3
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
 )
 
@@ -18,6 +24,10 @@ type S3StorageService struct {
 
 const BUCKET_NAME = "cks-hackathon-video-system"
 
+// PART_SIZE is the size of each multipart upload part. S3 requires every part
+// except the last one to be at least 5MB.
+const PART_SIZE = 8 * 1024 * 1024
+
 func NewS3StorageService(client *s3.Client) ports.StorageService {
 	return &S3StorageService{
 		client: client,
@@ -34,6 +44,111 @@ func (s *S3StorageService) Upload(ctx context.Context, key string, data []byte,
 	return err
 }
 
+// UploadStream uploads body to S3 without holding it in memory. Bodies that fit
+// in a single part are sent with PutObject; larger ones use a multipart upload
+// that is aborted if anything fails midway. It returns the number of bytes
+// written.
+func (s *S3StorageService) UploadStream(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+	buf := make([]byte, PART_SIZE)
+
+	n, done, err := readPart(body, buf)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read upload body: %w", err)
+	}
+	if done {
+		_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(BUCKET_NAME),
+			Key:         aws.String(key),
+			Body:        bytes.NewReader(buf[:n]),
+			ContentType: aws.String(contentType),
+		})
+		return int64(n), err
+	}
+
+	upload, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(BUCKET_NAME),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	var total int64
+	var parts []types.CompletedPart
+	for partNumber := int32(1); n > 0; partNumber++ {
+		part, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(BUCKET_NAME),
+			Key:        aws.String(key),
+			UploadId:   upload.UploadId,
+			PartNumber: aws.Int32(partNumber),
+			Body:       bytes.NewReader(buf[:n]),
+		})
+		if err != nil {
+			s.abortMultipartUpload(key, upload.UploadId)
+			return 0, fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+		}
+
+		parts = append(parts, types.CompletedPart{
+			ETag:       part.ETag,
+			PartNumber: aws.Int32(partNumber),
+		})
+		total += int64(n)
+		if done {
+			break
+		}
+
+		n, done, err = readPart(body, buf)
+		if err != nil {
+			s.abortMultipartUpload(key, upload.UploadId)
+			return 0, fmt.Errorf("failed to read upload body: %w", err)
+		}
+	}
+
+	_, err = s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(BUCKET_NAME),
+		Key:             aws.String(key),
+		UploadId:        upload.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		s.abortMultipartUpload(key, upload.UploadId)
+		return 0, fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	return total, nil
+}
+
+// readPart fills buf from body. done reports that body ended with io.EOF;
+// any other error, io.ErrUnexpectedEOF from a truncated request included, is
+// returned so the upload is not committed.
+func readPart(body io.Reader, buf []byte) (n int, done bool, err error) {
+	for n < len(buf) {
+		read, err := body.Read(buf[n:])
+		n += read
+		if err == io.EOF {
+			return n, true, nil
+		}
+		if err != nil {
+			return n, false, err
+		}
+	}
+	return n, false, nil
+}
+
+// abortMultipartUpload uses its own context so the parts are cleaned up even
+// when the upload failed because the request context was cancelled.
+func (s *S3StorageService) abortMultipartUpload(key string, uploadID *string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(BUCKET_NAME),
+		Key:      aws.String(key),
+		UploadId: uploadID,
+	})
+}
+
 func (s *S3StorageService) Download(ctx context.Context, key string) ([]byte, error) {
 	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(BUCKET_NAME),
@@ -47,6 +162,22 @@ func (s *S3StorageService) Download(ctx context.Context, key string) ([]byte, er
 	return io.ReadAll(result.Body)
 }
 
+func (s *S3StorageService) DownloadStream(ctx context.Context, key string) (io.ReadCloser, error) {
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(BUCKET_NAME),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ports.ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return result.Body, nil
+}
+
 func (s *S3StorageService) GetPresignedURL(ctx context.Context, key string, expirationMinutes int) (string, error) {
 	presignClient := s3.NewPresignClient(s.client)
 
@@ -64,6 +195,103 @@ func (s *S3StorageService) GetPresignedURL(ctx context.Context, key string, expi
 	return request.URL, nil
 }
 
+func (s *S3StorageService) GetPresignedUploadURL(ctx context.Context, key, contentType string, contentLength int64, expirationMinutes int) (string, error) {
+	presignClient := s3.NewPresignClient(s.client)
+
+	request, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(BUCKET_NAME),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(contentLength),
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = time.Duration(expirationMinutes) * time.Minute
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned upload URL: %w", err)
+	}
+
+	return request.URL, nil
+}
+
+func (s *S3StorageService) Stat(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+	result, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(BUCKET_NAME),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "NotFound" || apiErr.ErrorCode() == "NoSuchKey") {
+			return nil, ports.ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return &ports.ObjectInfo{
+		Size:        aws.ToInt64(result.ContentLength),
+		ContentType: aws.ToString(result.ContentType),
+	}, nil
+}
+
+func (s *S3StorageService) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	upload, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(BUCKET_NAME),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+
+	return aws.ToString(upload.UploadId), nil
+}
+
+func (s *S3StorageService) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, data []byte) (string, error) {
+	part, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(BUCKET_NAME),
+		Key:        aws.String(key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int32(partNumber),
+		Body:       bytes.NewReader(data),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+	}
+
+	return aws.ToString(part.ETag), nil
+}
+
+func (s *S3StorageService) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []entities.UploadPart) error {
+	completed := make([]types.CompletedPart, len(parts))
+	for i, part := range parts {
+		completed[i] = types.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int32(part.PartNumber),
+		}
+	}
+
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(BUCKET_NAME),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	return nil
+}
+
+func (s *S3StorageService) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(BUCKET_NAME),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}
+
 func (s *S3StorageService) Delete(ctx context.Context, key string) error {
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
 		Bucket: aws.String(BUCKET_NAME),
@@ -71,3 +299,80 @@ func (s *S3StorageService) Delete(ctx context.Context, key string) error {
 	})
 	return err
 }
+
+func (s *S3StorageService) DeletePrefix(ctx context.Context, prefix string) error {
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(BUCKET_NAME),
+		Prefix: aws.String(prefix),
+	})
+
+	// A listing page holds up to 1000 keys, as many as DeleteObjects takes
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+
+		objects := make([]types.ObjectIdentifier, len(page.Contents))
+		for i, object := range page.Contents {
+			objects[i] = types.ObjectIdentifier{Key: object.Key}
+		}
+
+		result, err := s.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(BUCKET_NAME),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return err
+		}
+		if len(result.Errors) > 0 {
+			return fmt.Errorf("failed to delete %s: %s", aws.ToString(result.Errors[0].Key), aws.ToString(result.Errors[0].Message))
+		}
+	}
+
+	return nil
+}
+
+// Copy copies an object within the bucket without passing it through the
+// service. A single CopyObject handles objects up to 5GB.
+func (s *S3StorageService) Copy(ctx context.Context, sourceKey, key string) error {
+	_, err := s.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(BUCKET_NAME),
+		Key:        aws.String(key),
+		CopySource: aws.String(copySource(sourceKey)),
+	})
+	return err
+}
+
+// CopyPrefix copies every object under sourcePrefix to the same relative key
+// under prefix.
+func (s *S3StorageService) CopyPrefix(ctx context.Context, sourcePrefix, prefix string) error {
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(BUCKET_NAME),
+		Prefix: aws.String(sourcePrefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, object := range page.Contents {
+			sourceKey := aws.ToString(object.Key)
+			if err := s.Copy(ctx, sourceKey, prefix+strings.TrimPrefix(sourceKey, sourcePrefix)); err != nil {
+				return fmt.Errorf("failed to copy %s: %w", sourceKey, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// copySource is the URL encoded bucket and key CopyObject expects.
+func copySource(key string) string {
+	return (&url.URL{Path: BUCKET_NAME + "/" + key}).EscapedPath()
+}
@@ -0,0 +1,140 @@
+package s3
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+// newTestStorageService returns a storage service backed by a fake S3 that
+// records the operations it receives.
+func newTestStorageService(t *testing.T) (*S3StorageService, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var operations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		query := r.URL.Query()
+
+		var operation string
+		switch {
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			operation = "CreateMultipartUpload"
+			w.Write([]byte(`<InitiateMultipartUploadResult><Bucket>b</Bucket><Key>k</Key><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`))
+		case r.Method == http.MethodPut && query.Has("partNumber"):
+			operation = "UploadPart"
+			w.Header().Set("ETag", `"etag"`)
+		case r.Method == http.MethodPut:
+			operation = "PutObject"
+		case r.Method == http.MethodPost && query.Has("uploadId"):
+			operation = "CompleteMultipartUpload"
+			w.Write([]byte(`<CompleteMultipartUploadResult><Bucket>b</Bucket><Key>k</Key></CompleteMultipartUploadResult>`))
+		case r.Method == http.MethodDelete && query.Has("uploadId"):
+			operation = "AbortMultipartUpload"
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			operation = r.Method
+		}
+
+		mu.Lock()
+		operations = append(operations, operation)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  aws.AnonymousCredentials{},
+	})
+
+	return &S3StorageService{client: client}, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return operations
+	}
+}
+
+func TestS3StorageService_UploadStream(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               func() io.Reader
+		wantErr            bool
+		expectedSize       int64
+		expectedOperations []string
+	}{
+		{
+			name:               "should put a body that fits in one part",
+			body:               func() io.Reader { return strings.NewReader("video") },
+			expectedSize:       5,
+			expectedOperations: []string{"PutObject"},
+		},
+		{
+			name:               "should complete a body spanning several parts",
+			body:               func() io.Reader { return io.LimitReader(zeroReader{}, PART_SIZE+10) },
+			expectedSize:       PART_SIZE + 10,
+			expectedOperations: []string{"CreateMultipartUpload", "UploadPart", "UploadPart", "CompleteMultipartUpload"},
+		},
+		{
+			name: "should not put a body truncated within the first part",
+			body: func() io.Reader {
+				return io.MultiReader(strings.NewReader("vid"), failingReader{err: io.ErrUnexpectedEOF})
+			},
+			wantErr: true,
+		},
+		{
+			name: "should abort a body truncated within a later part",
+			body: func() io.Reader {
+				return io.MultiReader(io.LimitReader(zeroReader{}, PART_SIZE+10), failingReader{err: io.ErrUnexpectedEOF})
+			},
+			wantErr:            true,
+			expectedOperations: []string{"CreateMultipartUpload", "UploadPart", "AbortMultipartUpload"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, operations := newTestStorageService(t)
+
+			size, err := service.UploadStream(context.Background(), "raw/user-123/video-123/video.mp4", tt.body(), "video/mp4")
+
+			if tt.wantErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if size != tt.expectedSize {
+				t.Errorf("expected %d bytes written, got %d", tt.expectedSize, size)
+			}
+			if !reflect.DeepEqual(operations(), tt.expectedOperations) {
+				t.Errorf("expected operations %v, got %v", tt.expectedOperations, operations())
+			}
+		})
+	}
+}
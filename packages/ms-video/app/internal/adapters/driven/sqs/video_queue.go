@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
@@ -18,6 +19,7 @@ type SQSVideoQueue struct {
 }
 
 const QUEUE_NAME = "MSVideo-Queue"
+const DLQ_NAME = "MSVideo-DLQueue"
 
 func NewSQSVideoQueue(client *sqs.Client) ports.VideoQueue {
 	return &SQSVideoQueue{
@@ -26,12 +28,20 @@ func NewSQSVideoQueue(client *sqs.Client) ports.VideoQueue {
 }
 
 func (i *SQSVideoQueue) getQueueUrl() (*string, error) {
-	if url := os.Getenv("SQS_QUEUE_URL"); url != "" {
+	return i.lookupQueueUrl("SQS_QUEUE_URL", QUEUE_NAME)
+}
+
+func (i *SQSVideoQueue) getDeadLetterQueueUrl() (*string, error) {
+	return i.lookupQueueUrl("SQS_DLQ_URL", DLQ_NAME)
+}
+
+func (i *SQSVideoQueue) lookupQueueUrl(envKey, queueName string) (*string, error) {
+	if url := os.Getenv(envKey); url != "" {
 		return &url, nil
 	}
 
 	queueUrl, err := i.client.GetQueueUrl(context.TODO(), &sqs.GetQueueUrlInput{
-		QueueName: aws.String(QUEUE_NAME),
+		QueueName: aws.String(queueName),
 	})
 	if err != nil {
 		return aws.String(""), err
@@ -59,7 +69,7 @@ func (q *SQSVideoQueue) Send(ctx context.Context, message dto.VideoProcessMessag
 	return err
 }
 
-func (q *SQSVideoQueue) Get(ctx context.Context) ([]sqstypes.Message, error) {
+func (q *SQSVideoQueue) Get(ctx context.Context, maxMessages int, visibilityTimeout time.Duration) ([]sqstypes.Message, error) {
 	url, err := q.getQueueUrl()
 	if err != nil {
 		return nil, err
@@ -67,9 +77,12 @@ func (q *SQSVideoQueue) Get(ctx context.Context) ([]sqstypes.Message, error) {
 
 	result, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
 		QueueUrl:            url,
-		MaxNumberOfMessages: 10,
+		MaxNumberOfMessages: int32(maxMessages),
 		WaitTimeSeconds:     20,
-		VisibilityTimeout:   300,
+		VisibilityTimeout:   int32(visibilityTimeout.Seconds()),
+		MessageSystemAttributeNames: []sqstypes.MessageSystemAttributeName{
+			sqstypes.MessageSystemAttributeNameApproximateReceiveCount,
+		},
 	})
 
 	if err != nil {
@@ -79,6 +92,21 @@ func (q *SQSVideoQueue) Get(ctx context.Context) ([]sqstypes.Message, error) {
 	return result.Messages, nil
 }
 
+func (q *SQSVideoQueue) ChangeVisibility(ctx context.Context, message sqstypes.Message, timeout time.Duration) error {
+	url, err := q.getQueueUrl()
+	if err != nil {
+		return err
+	}
+
+	_, err = q.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          url,
+		ReceiptHandle:     message.ReceiptHandle,
+		VisibilityTimeout: int32(timeout.Seconds()),
+	})
+
+	return err
+}
+
 func (q *SQSVideoQueue) Delete(ctx context.Context, message sqstypes.Message) error {
 	url, err := q.getQueueUrl()
 	if err != nil {
@@ -92,3 +120,28 @@ func (q *SQSVideoQueue) Delete(ctx context.Context, message sqstypes.Message) er
 
 	return err
 }
+
+// DeadLetter copies the message to the dead-letter queue with the reason as a
+// message attribute, then deletes it from the main queue.
+func (q *SQSVideoQueue) DeadLetter(ctx context.Context, message sqstypes.Message, reason string) error {
+	url, err := q.getDeadLetterQueueUrl()
+	if err != nil {
+		return err
+	}
+
+	_, err = q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    url,
+		MessageBody: message.Body,
+		MessageAttributes: map[string]sqstypes.MessageAttributeValue{
+			"failure_reason": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(reason),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send message to dead-letter queue: %w", err)
+	}
+
+	return q.Delete(ctx, message)
+}
@@ -0,0 +1,113 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+type SQSWebhookQueue struct {
+	client *sqs.Client
+}
+
+const WEBHOOK_QUEUE_NAME = "MSVideo-WebhookQueue"
+
+func NewSQSWebhookQueue(client *sqs.Client) ports.WebhookQueue {
+	return &SQSWebhookQueue{
+		client: client,
+	}
+}
+
+func (q *SQSWebhookQueue) getQueueUrl(ctx context.Context) (*string, error) {
+	if url := os.Getenv("SQS_WEBHOOK_QUEUE_URL"); url != "" {
+		return &url, nil
+	}
+
+	queueUrl, err := q.client.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{
+		QueueName: aws.String(WEBHOOK_QUEUE_NAME),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return queueUrl.QueueUrl, nil
+}
+
+func (q *SQSWebhookQueue) Send(ctx context.Context, message dto.WebhookDeliveryMessage) error {
+	url, err := q.getQueueUrl(ctx)
+	if err != nil {
+		return err
+	}
+
+	messageBody, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	_, err = q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    url,
+		MessageBody: aws.String(string(messageBody)),
+	})
+
+	return err
+}
+
+func (q *SQSWebhookQueue) Get(ctx context.Context, maxMessages int, visibilityTimeout time.Duration) ([]sqstypes.Message, error) {
+	url, err := q.getQueueUrl(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:            url,
+		MaxNumberOfMessages: int32(maxMessages),
+		WaitTimeSeconds:     20,
+		VisibilityTimeout:   int32(visibilityTimeout.Seconds()),
+		MessageSystemAttributeNames: []sqstypes.MessageSystemAttributeName{
+			sqstypes.MessageSystemAttributeNameApproximateReceiveCount,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Messages, nil
+}
+
+func (q *SQSWebhookQueue) ChangeVisibility(ctx context.Context, message sqstypes.Message, timeout time.Duration) error {
+	url, err := q.getQueueUrl(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          url,
+		ReceiptHandle:     message.ReceiptHandle,
+		VisibilityTimeout: int32(timeout.Seconds()),
+	})
+
+	return err
+}
+
+func (q *SQSWebhookQueue) Delete(ctx context.Context, message sqstypes.Message) error {
+	url, err := q.getQueueUrl(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      url,
+		ReceiptHandle: message.ReceiptHandle,
+	})
+
+	return err
+}
@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+// HTTPWebhookClient posts deliveries to user-supplied URLs. Unless
+// WEBHOOK_ALLOW_PRIVATE_TARGETS is set, it refuses to connect to private,
+// loopback and link-local addresses, so a webhook cannot be pointed at the
+// cluster or the cloud metadata endpoint.
+type HTTPWebhookClient struct {
+	httpClient *http.Client
+}
+
+var errPrivateTarget = errors.New("webhook target resolves to a private address")
+
+func NewHTTPWebhookClient() ports.WebhookClient {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if os.Getenv("WEBHOOK_ALLOW_PRIVATE_TARGETS") != "true" {
+		// Checked on the resolved address of every connection, redirects
+		// included, so DNS cannot be used to get around it
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+				ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+				return errPrivateTarget
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &HTTPWebhookClient{
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: transport,
+		},
+	}
+}
+
+func (c *HTTPWebhookClient) Post(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "ms-video-webhooks/1.0")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return resp.StatusCode, nil
+}
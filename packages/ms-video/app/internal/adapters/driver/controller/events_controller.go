@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/middleware"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// sseHeartbeatInterval keeps idle streams from being closed by proxies and
+// load balancers.
+const sseHeartbeatInterval = 15 * time.Second
+
+// EventsController streams status and progress changes of videos as
+// Server-Sent Events, one `video` event per change.
+type EventsController struct {
+	watchUsecase *usecases.WatchVideosUsecase
+}
+
+func NewEventsController(watchUsecase *usecases.WatchVideosUsecase) *EventsController {
+	return &EventsController{
+		watchUsecase: watchUsecase,
+	}
+}
+
+// VideoEvents streams the changes of one video, starting with its current
+// state.
+func (c *EventsController) VideoEvents(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	videoID := r.PathValue("id")
+	if videoID == "" {
+		return utils.NewBadRequestError("missing video id parameter")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, err := c.watchUsecase.WatchVideo(ctx, videoID, userID)
+	if err != nil {
+		return err
+	}
+
+	return writeEvents(ctx, w, events)
+}
+
+// UserEvents streams the changes of all of the user's videos.
+func (c *EventsController) UserEvents(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	return writeEvents(ctx, w, c.watchUsecase.WatchUser(ctx, userID))
+}
+
+// writeEvents streams events until ctx is done or events is closed. Once the
+// stream has started, errors can no longer be reported and end it instead.
+func writeEvents(ctx context.Context, w http.ResponseWriter, events <-chan dto.VideoOutput) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return utils.NewInternalServerError("streaming not supported")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Stops nginx ingress from buffering the stream
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return nil
+			}
+			if _, err := fmt.Fprintf(w, "event: video\ndata: %s\n\n", data); err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		}
+		flusher.Flush()
+	}
+}
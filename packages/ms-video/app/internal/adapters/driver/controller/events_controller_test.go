@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/middleware"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// newTestEventsController delivers updates, then ends the subscription.
+func newTestEventsController(videoRepo *mocks.MockVideoRepository, updates ...*entities.Video) *EventsController {
+	subscriber := &mocks.MockVideoEventSubscriber{
+		SubscribeFunc: func(ctx context.Context, userID string) <-chan *entities.Video {
+			ch := make(chan *entities.Video, len(updates))
+			for _, update := range updates {
+				ch <- update
+			}
+			close(ch)
+			return ch
+		},
+	}
+	return NewEventsController(usecases.NewWatchVideosUsecase(videoRepo, subscriber))
+}
+
+func TestEventsController_VideoEvents_Success(t *testing.T) {
+	userID := "user-123"
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return &entities.Video{ID: id, UserID: userID, Status: entities.VideoStatusProcessing, ProgressPercent: 30, Version: 1}, nil
+		},
+	}
+	controller := newTestEventsController(videoRepo,
+		&entities.Video{ID: "video-123", UserID: userID, Status: entities.VideoStatusCompleted, ProgressPercent: 100, Version: 2},
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/video/video-123/events", nil)
+	req.SetPathValue("id", "video-123")
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
+
+	w := httptest.NewRecorder()
+
+	err := controller.VideoEvents(ctx, w, req)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("expected event stream content type, got %s", contentType)
+	}
+
+	body := w.Body.String()
+	if strings.Count(body, "event: video\n") != 2 {
+		t.Errorf("expected current state and one update, got %q", body)
+	}
+	if !strings.Contains(body, `"status":"completed"`) {
+		t.Errorf("expected completed update in stream, got %q", body)
+	}
+}
+
+func TestEventsController_VideoEvents_NotOwner(t *testing.T) {
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return &entities.Video{ID: id, UserID: "other-user"}, nil
+		},
+	}
+	controller := newTestEventsController(videoRepo)
+
+	req := httptest.NewRequest(http.MethodGet, "/video/video-123/events", nil)
+	req.SetPathValue("id", "video-123")
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+
+	w := httptest.NewRecorder()
+
+	err := controller.VideoEvents(ctx, w, req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok || httpErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected unauthorized, got %v", err)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected stream not to start, got %q", w.Body.String())
+	}
+}
+
+func TestEventsController_UserEvents_Success(t *testing.T) {
+	userID := "user-123"
+	controller := newTestEventsController(&mocks.MockVideoRepository{},
+		&entities.Video{ID: "video-1", UserID: userID, Status: entities.VideoStatusProcessing},
+		&entities.Video{ID: "video-2", UserID: userID, Status: entities.VideoStatusPending},
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/video/events", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
+
+	w := httptest.NewRecorder()
+
+	err := controller.UserEvents(ctx, w, req)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, `"id":"video-1"`) || !strings.Contains(body, `"id":"video-2"`) {
+		t.Errorf("expected updates of both videos, got %q", body)
+	}
+}
+
+func TestEventsController_MethodNotAllowed(t *testing.T) {
+	controller := newTestEventsController(&mocks.MockVideoRepository{})
+
+	req := httptest.NewRequest(http.MethodPost, "/video/events", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+
+	err := controller.UserEvents(ctx, httptest.NewRecorder(), req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok || httpErr.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected method not allowed, got %v", err)
+	}
+}
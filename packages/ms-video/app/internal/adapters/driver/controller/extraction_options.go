@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// parseExtractionOptions reads extraction options from string fields, as
+// sent in multipart forms and tus metadata. Field names match the JSON ones.
+func parseExtractionOptions(fields map[string]string) (entities.ExtractionOptions, error) {
+	var options entities.ExtractionOptions
+
+	floats := map[string]*float64{
+		"fps":              &options.FPS,
+		"interval_seconds": &options.IntervalSeconds,
+		"start_seconds":    &options.StartSeconds,
+		"end_seconds":      &options.EndSeconds,
+		"scene_threshold":  &options.SceneThreshold,
+	}
+	for name, target := range floats {
+		if fields[name] == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[name], 64)
+		if err != nil {
+			return options, utils.NewValidationError(name)
+		}
+		*target = value
+	}
+
+	ints := map[string]*int{
+		"max_width":  &options.MaxWidth,
+		"max_height": &options.MaxHeight,
+		"quality":    &options.Quality,
+	}
+	for name, target := range ints {
+		if fields[name] == "" {
+			continue
+		}
+		value, err := strconv.Atoi(fields[name])
+		if err != nil {
+			return options, utils.NewValidationError(name)
+		}
+		*target = value
+	}
+
+	if fields["hls"] != "" {
+		hls, err := strconv.ParseBool(fields["hls"])
+		if err != nil {
+			return options, utils.NewValidationError("hls")
+		}
+		options.HLS = hls
+	}
+
+	options.Mode = entities.ExtractionMode(fields["mode"])
+	options.Format = entities.FrameFormat(fields["format"])
+
+	return options, nil
+}
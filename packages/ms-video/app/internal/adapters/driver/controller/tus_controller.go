@@ -0,0 +1,201 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/middleware"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+const (
+	TusVersion    = "1.0.0"
+	TusExtensions = "creation,termination,expiration"
+	TusBasePath   = "/video/tus/uploads/"
+)
+
+// TusController implements the tus 1.0 resumable upload protocol
+// (https://tus.io/protocols/resumable-upload) on top of ResumableUploadUsecase.
+type TusController struct {
+	resumableUploadUsecase *usecases.ResumableUploadUsecase
+}
+
+func NewTusController(resumableUploadUsecase *usecases.ResumableUploadUsecase) *TusController {
+	return &TusController{
+		resumableUploadUsecase: resumableUploadUsecase,
+	}
+}
+
+// Options answers tus capability discovery. It needs no authentication.
+func (c *TusController) Options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", TusVersion)
+	w.Header().Set("Tus-Version", TusVersion)
+	w.Header().Set("Tus-Extension", TusExtensions)
+	w.Header().Set("Tus-Max-Size", strconv.FormatInt(usecases.MaxVideoSize, 10))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *TusController) Create(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Tus-Resumable", TusVersion)
+
+	if r.Method != http.MethodPost {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	if err := checkTusVersion(w, r); err != nil {
+		return err
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	userEmail, err := middleware.GetEmailFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil {
+		return utils.NewValidationError("Upload-Length")
+	}
+	if length > usecases.MaxVideoSize {
+		return utils.NewHttpError(http.StatusRequestEntityTooLarge, "upload exceeds maximum allowed size")
+	}
+
+	metadata, err := parseTusMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		return utils.NewValidationError("Upload-Metadata")
+	}
+
+	options, err := parseExtractionOptions(metadata)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.resumableUploadUsecase.Create(ctx, dto.CreateResumableUploadInput{
+		FileName:    metadata["filename"],
+		ContentType: metadata["filetype"],
+		Length:      length,
+		Options:     options,
+		UserID:      userID,
+		UserEmail:   userEmail,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Location", TusBasePath+result.UploadID)
+	writeTusUploadHeaders(w, result)
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// Upload serves HEAD, PATCH and DELETE on a single upload resource.
+func (c *TusController) Upload(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Tus-Resumable", TusVersion)
+
+	if err := checkTusVersion(w, r); err != nil {
+		return err
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	uploadID := r.PathValue("id")
+	if uploadID == "" {
+		return utils.NewBadRequestError("missing upload id parameter")
+	}
+
+	switch r.Method {
+	case http.MethodHead:
+		result, err := c.resumableUploadUsecase.GetOffset(ctx, uploadID, userID)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		writeTusUploadHeaders(w, result)
+		w.WriteHeader(http.StatusOK)
+		return nil
+
+	case http.MethodPatch:
+		if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+			return utils.NewHttpError(http.StatusUnsupportedMediaType, "content type must be application/offset+octet-stream")
+		}
+
+		offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+		if err != nil {
+			return utils.NewValidationError("Upload-Offset")
+		}
+
+		result, err := c.resumableUploadUsecase.AppendChunk(ctx, uploadID, userID, offset, r.Body)
+		if err != nil {
+			return err
+		}
+
+		writeTusUploadHeaders(w, result)
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+
+	case http.MethodDelete:
+		if err := c.resumableUploadUsecase.Terminate(ctx, uploadID, userID); err != nil {
+			return err
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+}
+
+func checkTusVersion(w http.ResponseWriter, r *http.Request) error {
+	if r.Header.Get("Tus-Resumable") != TusVersion {
+		w.Header().Set("Tus-Version", TusVersion)
+		return utils.NewHttpError(http.StatusPreconditionFailed, "unsupported tus version")
+	}
+	return nil
+}
+
+func writeTusUploadHeaders(w http.ResponseWriter, result *dto.ResumableUploadOutput) {
+	w.Header().Set("Upload-Offset", strconv.FormatInt(result.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(result.Length, 10))
+	w.Header().Set("Upload-Expires", result.ExpiresAt.Format(http.TimeFormat))
+	w.Header().Set("X-Video-Id", result.VideoID)
+}
+
+// parseTusMetadata decodes an Upload-Metadata header: comma separated pairs
+// of a key and an optional base64 encoded value.
+func parseTusMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	if strings.TrimSpace(header) == "" {
+		return metadata, nil
+	}
+
+	for _, pair := range strings.Split(header, ",") {
+		fields := strings.Fields(pair)
+		switch len(fields) {
+		case 1:
+			metadata[fields[0]] = ""
+		case 2:
+			value, err := base64.StdEncoding.DecodeString(fields[1])
+			if err != nil {
+				return nil, err
+			}
+			metadata[fields[0]] = string(value)
+		default:
+			return nil, utils.NewValidationError("Upload-Metadata")
+		}
+	}
+
+	return metadata, nil
+}
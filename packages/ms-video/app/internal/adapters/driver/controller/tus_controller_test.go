@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/middleware"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func newTestTusController(sessionRepo *mocks.MockUploadSessionRepository) *TusController {
+	storageService := &mocks.MockStorageService{}
+	usecase := usecases.NewResumableUploadUsecase(
+		&mocks.MockVideoRepository{},
+		sessionRepo,
+		storageService,
+		&mocks.MockVideoQueue{},
+		usecases.NewVideoValidator(&mocks.MockVideoProber{}, storageService, usecases.DefaultVideoLimits()),
+		&mocks.MockUsageRepository{},
+		usecases.DefaultUsageLimits(),
+		usecases.NewVideoDeduplicator(&mocks.MockVideoRepository{}, false),
+	)
+	return NewTusController(usecase)
+}
+
+func TestTusController_Create_Success(t *testing.T) {
+	controller := newTestTusController(&mocks.MockUploadSessionRepository{})
+
+	req := httptest.NewRequest(http.MethodPost, "/video/tus/uploads", nil)
+	req.Header.Set("Tus-Resumable", TusVersion)
+	req.Header.Set("Upload-Length", "1024")
+	// "test.mp4" and "video/mp4" base64 encoded
+	req.Header.Set("Upload-Metadata", "filename dGVzdC5tcDQ=,filetype dmlkZW8vbXA0")
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+	ctx = context.WithValue(ctx, middleware.EmailContextKey, "user@example.com")
+
+	w := httptest.NewRecorder()
+
+	err := controller.Create(ctx, w, req)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status code %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	if !strings.HasPrefix(w.Header().Get("Location"), TusBasePath) {
+		t.Errorf("expected Location under %s, got '%s'", TusBasePath, w.Header().Get("Location"))
+	}
+
+	if w.Header().Get("Upload-Offset") != "0" {
+		t.Errorf("expected Upload-Offset 0, got '%s'", w.Header().Get("Upload-Offset"))
+	}
+}
+
+func TestTusController_Create_UnsupportedVersion(t *testing.T) {
+	controller := newTestTusController(&mocks.MockUploadSessionRepository{})
+
+	req := httptest.NewRequest(http.MethodPost, "/video/tus/uploads", nil)
+	req.Header.Set("Upload-Length", "1024")
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+
+	w := httptest.NewRecorder()
+
+	err := controller.Create(ctx, w, req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("expected status code %d, got %d", http.StatusPreconditionFailed, httpErr.StatusCode)
+	}
+}
+
+func TestTusController_Upload_Head(t *testing.T) {
+	session := entities.NewUploadSession("video-123", "user-123", "raw/key", "multipart-1", 1024)
+	session.SetTail(512)
+
+	controller := newTestTusController(&mocks.MockUploadSessionRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.UploadSession, error) {
+			return session, nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodHead, TusBasePath+session.ID, nil)
+	req.SetPathValue("id", session.ID)
+	req.Header.Set("Tus-Resumable", TusVersion)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+
+	w := httptest.NewRecorder()
+
+	err := controller.Upload(ctx, w, req)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if w.Header().Get("Upload-Offset") != "512" || w.Header().Get("Upload-Length") != "1024" {
+		t.Errorf("expected offset 512 of 1024, got %s of %s", w.Header().Get("Upload-Offset"), w.Header().Get("Upload-Length"))
+	}
+
+	if w.Header().Get("Cache-Control") != "no-store" {
+		t.Error("expected Cache-Control no-store")
+	}
+}
+
+func TestTusController_Upload_PatchRequiresOffsetContentType(t *testing.T) {
+	controller := newTestTusController(&mocks.MockUploadSessionRepository{})
+
+	req := httptest.NewRequest(http.MethodPatch, TusBasePath+"upload-1", strings.NewReader("data"))
+	req.SetPathValue("id", "upload-1")
+	req.Header.Set("Tus-Resumable", TusVersion)
+	req.Header.Set("Upload-Offset", "0")
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+
+	w := httptest.NewRecorder()
+
+	err := controller.Upload(ctx, w, req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status code %d, got %d", http.StatusUnsupportedMediaType, httpErr.StatusCode)
+	}
+}
+
+func TestParseTusMetadata(t *testing.T) {
+	metadata, err := parseTusMetadata("filename dGVzdC5tcDQ=, is_confidential")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if metadata["filename"] != "test.mp4" {
+		t.Errorf("expected filename 'test.mp4', got '%s'", metadata["filename"])
+	}
+
+	if _, ok := metadata["is_confidential"]; !ok {
+		t.Error("expected key without value to be present")
+	}
+
+	if _, err := parseTusMetadata("filename not-base64!"); err == nil {
+		t.Error("expected error for invalid base64 value")
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/middleware"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+type UsageController struct {
+	getUsageUsecase *usecases.GetUsageUsecase
+}
+
+func NewUsageController(getUsageUsecase *usecases.GetUsageUsecase) *UsageController {
+	return &UsageController{
+		getUsageUsecase: getUsageUsecase,
+	}
+}
+
+// Usage reports the user's usage against their quotas.
+func (c *UsageController) Usage(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.getUsageUsecase.Execute(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/middleware"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func TestUsageController_Usage(t *testing.T) {
+	usageRepo := &mocks.MockUsageRepository{
+		GetFunc: func(ctx context.Context, userID string) (*entities.Usage, error) {
+			return &entities.Usage{UserID: userID, StoredBytes: 2048, VideoCount: 3}, nil
+		},
+	}
+	limits := entities.UsageLimits{MaxStoredBytes: 4096, MaxVideos: 10, MaxUploadsPerHour: 5}
+	controller := NewUsageController(usecases.NewGetUsageUsecase(usageRepo, limits))
+
+	req := httptest.NewRequest(http.MethodGet, "/video/usage", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+	w := httptest.NewRecorder()
+
+	if err := controller.Usage(ctx, w, req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var output dto.UsageOutput
+	if err := json.NewDecoder(w.Body).Decode(&output); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if output.StoredBytes != 2048 || output.MaxStoredBytes != 4096 || output.VideoCount != 3 || output.MaxUploadsPerHour != 5 {
+		t.Errorf("expected usage with its limits, got %+v", output)
+	}
+}
+
+func TestUsageController_Usage_MethodNotAllowed(t *testing.T) {
+	controller := NewUsageController(usecases.NewGetUsageUsecase(&mocks.MockUsageRepository{}, usecases.DefaultUsageLimits()))
+
+	req := httptest.NewRequest(http.MethodPost, "/video/usage", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+
+	err := controller.Usage(ctx, httptest.NewRecorder(), req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+	if httpErr.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected status code %d, got %d", http.StatusMethodNotAllowed, httpErr.StatusCode)
+	}
+}
@@ -3,7 +3,10 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"strconv"
 
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/middleware"
@@ -12,20 +15,47 @@ import (
 )
 
 type VideoController struct {
-	uploadUsecase   *usecases.UploadVideoUsecase
-	listUsecase     *usecases.ListVideosUsecase
-	downloadUsecase *usecases.DownloadVideoUsecase
+	uploadUsecase           *usecases.UploadVideoUsecase
+	listUsecase             *usecases.ListVideosUsecase
+	downloadUsecase         *usecases.DownloadVideoUsecase
+	requestUploadURLUsecase *usecases.RequestUploadURLUsecase
+	confirmUploadUsecase    *usecases.ConfirmUploadUsecase
+	previewsUsecase         *usecases.GetPreviewsUsecase
+	streamUsecase           *usecases.GetStreamUsecase
+	deleteUsecase           *usecases.DeleteVideoUsecase
+	reprocessUsecase        *usecases.ReprocessVideoUsecase
+	cancelUsecase           *usecases.CancelVideoUsecase
+	pinUsecase              *usecases.PinVideoUsecase
+	framesUsecase           *usecases.GetFramesUsecase
 }
 
 func NewVideoController(
 	uploadUsecase *usecases.UploadVideoUsecase,
 	listUsecase *usecases.ListVideosUsecase,
 	downloadUsecase *usecases.DownloadVideoUsecase,
+	requestUploadURLUsecase *usecases.RequestUploadURLUsecase,
+	confirmUploadUsecase *usecases.ConfirmUploadUsecase,
+	previewsUsecase *usecases.GetPreviewsUsecase,
+	streamUsecase *usecases.GetStreamUsecase,
+	deleteUsecase *usecases.DeleteVideoUsecase,
+	reprocessUsecase *usecases.ReprocessVideoUsecase,
+	cancelUsecase *usecases.CancelVideoUsecase,
+	pinUsecase *usecases.PinVideoUsecase,
+	framesUsecase *usecases.GetFramesUsecase,
 ) *VideoController {
 	return &VideoController{
-		uploadUsecase:   uploadUsecase,
-		listUsecase:     listUsecase,
-		downloadUsecase: downloadUsecase,
+		uploadUsecase:           uploadUsecase,
+		listUsecase:             listUsecase,
+		downloadUsecase:         downloadUsecase,
+		requestUploadURLUsecase: requestUploadURLUsecase,
+		confirmUploadUsecase:    confirmUploadUsecase,
+		previewsUsecase:         previewsUsecase,
+		streamUsecase:           streamUsecase,
+		deleteUsecase:           deleteUsecase,
+		reprocessUsecase:        reprocessUsecase,
+		cancelUsecase:           cancelUsecase,
+		pinUsecase:              pinUsecase,
+		framesUsecase:           framesUsecase,
 	}
 }
 
@@ -44,20 +74,29 @@ func (c *VideoController) Upload(ctx context.Context, w http.ResponseWriter, r *
 		return err
 	}
 
-	if err := r.ParseMultipartForm(500 << 20); err != nil {
+	reader, err := r.MultipartReader()
+	if err != nil {
 		return utils.NewBadRequestError("failed to parse multipart form")
 	}
 
-	file, fileHeader, err := r.FormFile("video")
+	part, fields, err := nextFilePart(reader, "video")
 	if err != nil {
-		return utils.NewBadRequestError("missing video file")
+		return err
+	}
+	defer part.Close()
+
+	options, err := parseExtractionOptions(fields)
+	if err != nil {
+		return err
 	}
-	defer file.Close()
 
 	input := dto.UploadVideoInput{
-		File:      fileHeader,
-		UserID:    userID,
-		UserEmail: userEmail,
+		File:        part,
+		FileName:    part.FileName(),
+		ContentType: part.Header.Get("Content-Type"),
+		Options:     options,
+		UserID:      userID,
+		UserEmail:   userEmail,
 	}
 
 	result, err := c.uploadUsecase.Execute(ctx, input)
@@ -69,6 +108,61 @@ func (c *VideoController) Upload(ctx context.Context, w http.ResponseWriter, r *
 	return json.NewEncoder(w).Encode(result)
 }
 
+func (c *VideoController) RequestUploadURL(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	userEmail, err := middleware.GetEmailFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var input dto.RequestUploadURLInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.NewBadRequestError("invalid request body")
+	}
+	input.UserID = userID
+	input.UserEmail = userEmail
+
+	result, err := c.requestUploadURLUsecase.Execute(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(result)
+}
+
+func (c *VideoController) ConfirmUpload(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	videoID := r.PathValue("id")
+	if videoID == "" {
+		return utils.NewBadRequestError("missing video id parameter")
+	}
+
+	result, err := c.confirmUploadUsecase.Execute(ctx, videoID, userID)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	return json.NewEncoder(w).Encode(result)
+}
+
 func (c *VideoController) List(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	if r.Method != http.MethodGet {
 		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
@@ -79,7 +173,23 @@ func (c *VideoController) List(ctx context.Context, w http.ResponseWriter, r *ht
 		return err
 	}
 
-	result, err := c.listUsecase.Execute(ctx, userID)
+	query := r.URL.Query()
+	input := dto.ListVideosInput{
+		UserID:    userID,
+		NextToken: query.Get("next_token"),
+		Status:    query.Get("status"),
+		Sort:      query.Get("sort"),
+		Order:     query.Get("order"),
+	}
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return utils.NewBadRequestError("limit must be a number")
+		}
+		input.Limit = limit
+	}
+
+	result, err := c.listUsecase.Execute(ctx, input)
 	if err != nil {
 		return err
 	}
@@ -111,3 +221,251 @@ func (c *VideoController) Download(ctx context.Context, w http.ResponseWriter, r
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(result)
 }
+
+func (c *VideoController) Previews(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	videoID := r.PathValue("id")
+	if videoID == "" {
+		return utils.NewBadRequestError("missing video id parameter")
+	}
+
+	result, err := c.previewsUsecase.Execute(ctx, videoID, userID)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(result)
+}
+
+func (c *VideoController) Delete(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodDelete {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	videoID := r.PathValue("id")
+	if videoID == "" {
+		return utils.NewBadRequestError("missing video id parameter")
+	}
+
+	if err := c.deleteUsecase.Execute(ctx, videoID, userID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Reprocess queues a finished video again. The body is optional and may
+// carry new extraction options.
+func (c *VideoController) Reprocess(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	videoID := r.PathValue("id")
+	if videoID == "" {
+		return utils.NewBadRequestError("missing video id parameter")
+	}
+
+	var input dto.ReprocessVideoInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
+		return utils.NewBadRequestError("invalid request body")
+	}
+	input.VideoID = videoID
+	input.UserID = userID
+
+	result, err := c.reprocessUsecase.Execute(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	return json.NewEncoder(w).Encode(result)
+}
+
+func (c *VideoController) Cancel(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	videoID := r.PathValue("id")
+	if videoID == "" {
+		return utils.NewBadRequestError("missing video id parameter")
+	}
+
+	result, err := c.cancelUsecase.Execute(ctx, videoID, userID)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(result)
+}
+
+// Pin keeps the processed outputs of the video past the retention period
+// with POST, and lets them expire again with DELETE.
+func (c *VideoController) Pin(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	videoID := r.PathValue("id")
+	if videoID == "" {
+		return utils.NewBadRequestError("missing video id parameter")
+	}
+
+	result, err := c.pinUsecase.Execute(ctx, videoID, userID, r.Method == http.MethodPost)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(result)
+}
+
+// Frames lists a page of the frames of the video, or returns a presigned
+// URL for one frame when the request names its number.
+func (c *VideoController) Frames(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	videoID := r.PathValue("id")
+	if videoID == "" {
+		return utils.NewBadRequestError("missing video id parameter")
+	}
+
+	var result any
+	if value := r.PathValue("n"); value != "" {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return utils.NewBadRequestError("frame number must be a number")
+		}
+		result, err = c.framesUsecase.Get(ctx, videoID, userID, number)
+		if err != nil {
+			return err
+		}
+	} else {
+		query := r.URL.Query()
+		input := dto.ListFramesInput{
+			VideoID:   videoID,
+			UserID:    userID,
+			NextToken: query.Get("next_token"),
+		}
+		if value := query.Get("limit"); value != "" {
+			limit, err := strconv.Atoi(value)
+			if err != nil {
+				return utils.NewBadRequestError("limit must be a number")
+			}
+			input.Limit = limit
+		}
+		result, err = c.framesUsecase.List(ctx, input)
+		if err != nil {
+			return err
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(result)
+}
+
+// Stream serves the HLS master playlist, or a rendition playlist when the
+// request names one.
+func (c *VideoController) Stream(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	videoID := r.PathValue("id")
+	if videoID == "" {
+		return utils.NewBadRequestError("missing video id parameter")
+	}
+
+	var playlist string
+	if name := r.PathValue("playlist"); name != "" {
+		playlist, err = c.streamUsecase.RenditionPlaylist(ctx, videoID, userID, name)
+	} else {
+		playlist, err = c.streamUsecase.MasterPlaylist(ctx, videoID, userID)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.apple.mpegurl")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, err = io.WriteString(w, playlist)
+	return err
+}
+
+// maxFormFieldSize bounds the plain form fields read before the file part.
+const maxFormFieldSize = 1024
+
+// nextFilePart advances the multipart reader to the file part named field so
+// the file can be streamed without buffering the whole form. Plain fields
+// sent before the file are returned alongside it.
+func nextFilePart(reader *multipart.Reader, field string) (*multipart.Part, map[string]string, error) {
+	fields := map[string]string{}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, nil, utils.NewBadRequestError("missing " + field + " file")
+		}
+		if err != nil {
+			return nil, nil, utils.NewBadRequestError("failed to parse multipart form")
+		}
+
+		if part.FormName() == field && part.FileName() != "" {
+			return part, fields, nil
+		}
+
+		if part.FileName() == "" {
+			value, err := io.ReadAll(io.LimitReader(part, maxFormFieldSize))
+			if err != nil {
+				part.Close()
+				return nil, nil, utils.NewBadRequestError("failed to parse multipart form")
+			}
+			fields[part.FormName()] = string(value)
+		}
+		part.Close()
+	}
+}
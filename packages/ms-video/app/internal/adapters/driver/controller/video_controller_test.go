@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
@@ -14,10 +15,36 @@ import (
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/middleware"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
 	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
 )
 
+func newTestVideoController(videoRepo *mocks.MockVideoRepository, storageService *mocks.MockStorageService, videoQueue *mocks.MockVideoQueue) *VideoController {
+	videoValidator := usecases.NewVideoValidator(&mocks.MockVideoProber{}, storageService, usecases.DefaultVideoLimits())
+	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue, videoValidator, &mocks.MockUsageRepository{}, usecases.DefaultUsageLimits(), usecases.NewVideoDeduplicator(videoRepo, false))
+	listUsecase := usecases.NewListVideosUsecase(videoRepo)
+	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
+	requestUploadURLUsecase := usecases.NewRequestUploadURLUsecase(videoRepo, storageService, videoValidator, &mocks.MockUsageRepository{}, usecases.DefaultUsageLimits())
+	confirmUploadUsecase := usecases.NewConfirmUploadUsecase(videoRepo, storageService, videoQueue, videoValidator, &mocks.MockUsageRepository{})
+
+	previewsUsecase := usecases.NewGetPreviewsUsecase(videoRepo, storageService)
+
+	streamUsecase := usecases.NewGetStreamUsecase(videoRepo, storageService)
+
+	deleteUsecase := usecases.NewDeleteVideoUsecase(videoRepo, &mocks.MockUploadSessionRepository{}, storageService, &mocks.MockUsageRepository{})
+
+	reprocessUsecase := usecases.NewReprocessVideoUsecase(videoRepo, storageService, videoQueue, videoValidator, &mocks.MockUsageRepository{}, usecases.DefaultMaxProcessingAttempts)
+
+	cancelUsecase := usecases.NewCancelVideoUsecase(videoRepo)
+
+	pinUsecase := usecases.NewPinVideoUsecase(videoRepo)
+
+	framesUsecase := usecases.NewGetFramesUsecase(videoRepo, storageService)
+
+	return NewVideoController(uploadUsecase, listUsecase, downloadUsecase, requestUploadURLUsecase, confirmUploadUsecase, previewsUsecase, streamUsecase, deleteUsecase, reprocessUsecase, cancelUsecase, pinUsecase, framesUsecase)
+}
+
 func TestVideoController_Upload_Success(t *testing.T) {
 	// Create mocks
 	videoRepo := &mocks.MockVideoRepository{
@@ -26,9 +53,12 @@ func TestVideoController_Upload_Success(t *testing.T) {
 		},
 	}
 
+	var uploadedContent []byte
 	storageService := &mocks.MockStorageService{
-		UploadFunc: func(ctx context.Context, key string, data []byte, contentType string) error {
-			return nil
+		UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+			data, err := io.ReadAll(body)
+			uploadedContent = data
+			return int64(len(data)), err
 		},
 	}
 
@@ -38,11 +68,7 @@ func TestVideoController_Upload_Success(t *testing.T) {
 		},
 	}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
-
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	// Create multipart form
 	body := &bytes.Buffer{}
@@ -86,18 +112,121 @@ func TestVideoController_Upload_Success(t *testing.T) {
 	if response.OriginalName != "test-video.mp4" {
 		t.Errorf("expected OriginalName 'test-video.mp4', got '%s'", response.OriginalName)
 	}
+
+	if string(uploadedContent) != "fake video content" {
+		t.Errorf("expected uploaded content 'fake video content', got '%s'", uploadedContent)
+	}
 }
 
-func TestVideoController_Upload_MethodNotAllowed(t *testing.T) {
+func TestVideoController_Upload_WithExtractionOptions(t *testing.T) {
+	var queuedMessage dto.VideoProcessMessage
+	videoQueue := &mocks.MockVideoQueue{
+		SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+			queuedMessage = message
+			return nil
+		},
+	}
+
+	controller := newTestVideoController(&mocks.MockVideoRepository{}, &mocks.MockStorageService{}, videoQueue)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("interval_seconds", "5")
+	writer.WriteField("format", "png")
+	writer.WriteField("max_width", "640")
+	part, _ := writer.CreateFormFile("video", "test-video.mp4")
+	part.Write([]byte("fake video content"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/videos/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+	ctx = context.WithValue(ctx, middleware.EmailContextKey, "user@example.com")
+
+	w := httptest.NewRecorder()
+
+	err := controller.Upload(ctx, w, req)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := entities.ExtractionOptions{IntervalSeconds: 5, Format: entities.FrameFormatPNG, MaxWidth: 640}
+	if queuedMessage.Options != expected {
+		t.Errorf("expected queued options %+v, got %+v", expected, queuedMessage.Options)
+	}
+}
+
+func TestVideoController_Upload_InvalidExtractionOptions(t *testing.T) {
+	controller := newTestVideoController(&mocks.MockVideoRepository{}, &mocks.MockStorageService{}, &mocks.MockVideoQueue{})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("fps", "fast")
+	part, _ := writer.CreateFormFile("video", "test-video.mp4")
+	part.Write([]byte("fake video content"))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/videos/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+	ctx = context.WithValue(ctx, middleware.EmailContextKey, "user@example.com")
+
+	w := httptest.NewRecorder()
+
+	err := controller.Upload(ctx, w, req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, httpErr.StatusCode)
+	}
+}
+
+func TestVideoController_Upload_MissingVideoFile(t *testing.T) {
 	videoRepo := &mocks.MockVideoRepository{}
 	storageService := &mocks.MockStorageService{}
 	videoQueue := &mocks.MockVideoQueue{}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("description", "no file here")
+	writer.Close()
 
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	req := httptest.NewRequest(http.MethodPost, "/videos/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+	ctx = context.WithValue(ctx, middleware.EmailContextKey, "user@example.com")
+
+	w := httptest.NewRecorder()
+
+	err := controller.Upload(ctx, w, req)
+
+	if err == nil {
+		t.Fatal("expected error for missing video file, got nil")
+	}
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, httpErr.StatusCode)
+	}
+}
+
+func TestVideoController_Upload_MethodNotAllowed(t *testing.T) {
+	videoRepo := &mocks.MockVideoRepository{}
+	storageService := &mocks.MockStorageService{}
+	videoQueue := &mocks.MockVideoQueue{}
+
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	req := httptest.NewRequest(http.MethodGet, "/videos/upload", nil)
 	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
@@ -126,11 +255,7 @@ func TestVideoController_Upload_MissingUserID(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	videoQueue := &mocks.MockVideoQueue{}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
-
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
@@ -176,19 +301,15 @@ func TestVideoController_List_Success(t *testing.T) {
 	}
 
 	videoRepo := &mocks.MockVideoRepository{
-		FindByUserIDFunc: func(ctx context.Context, uid string) ([]*entities.Video, error) {
-			return videos, nil
+		FindByUserIDFunc: func(ctx context.Context, uid string, query ports.VideoListQuery) (*ports.VideoPage, error) {
+			return &ports.VideoPage{Videos: videos}, nil
 		},
 	}
 
 	storageService := &mocks.MockStorageService{}
 	videoQueue := &mocks.MockVideoQueue{}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
-
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	req := httptest.NewRequest(http.MethodGet, "/videos", nil)
 	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
@@ -220,11 +341,7 @@ func TestVideoController_List_MethodNotAllowed(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	videoQueue := &mocks.MockVideoQueue{}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
-
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	req := httptest.NewRequest(http.MethodPost, "/videos", nil)
 	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
@@ -252,11 +369,7 @@ func TestVideoController_List_MissingUserID(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	videoQueue := &mocks.MockVideoQueue{}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
-
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	req := httptest.NewRequest(http.MethodGet, "/videos", nil)
 	ctx := req.Context()
@@ -279,6 +392,56 @@ func TestVideoController_List_MissingUserID(t *testing.T) {
 	}
 }
 
+func TestVideoController_List_QueryParameters(t *testing.T) {
+	userID := "user-123"
+
+	var received ports.VideoListQuery
+	videoRepo := &mocks.MockVideoRepository{
+		FindByUserIDFunc: func(ctx context.Context, uid string, query ports.VideoListQuery) (*ports.VideoPage, error) {
+			received = query
+			return &ports.VideoPage{NextCursor: "next-page"}, nil
+		},
+	}
+
+	controller := newTestVideoController(videoRepo, &mocks.MockStorageService{}, &mocks.MockVideoQueue{})
+
+	req := httptest.NewRequest(http.MethodGet, "/video/list?limit=10&next_token=abc&status=completed&sort=updated_at&order=asc", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
+	w := httptest.NewRecorder()
+
+	if err := controller.List(ctx, w, req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := ports.VideoListQuery{Status: entities.VideoStatusCompleted, SortBy: ports.VideoSortUpdatedAt, Ascending: true, Limit: 10, Cursor: "abc"}
+	if received != expected {
+		t.Errorf("expected query %+v, got %+v", expected, received)
+	}
+
+	var response dto.ListVideosOutput
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.NextToken != "next-page" {
+		t.Errorf("expected next_token 'next-page', got '%s'", response.NextToken)
+	}
+}
+
+func TestVideoController_List_InvalidLimit(t *testing.T) {
+	controller := newTestVideoController(&mocks.MockVideoRepository{}, &mocks.MockStorageService{}, &mocks.MockVideoQueue{})
+
+	req := httptest.NewRequest(http.MethodGet, "/video/list?limit=ten", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+	w := httptest.NewRecorder()
+
+	err := controller.List(ctx, w, req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok || httpErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected bad request, got %v", err)
+	}
+}
+
 func TestVideoController_Download_Success(t *testing.T) {
 	userID := "user-123"
 	videoID := "video-123"
@@ -306,11 +469,7 @@ func TestVideoController_Download_Success(t *testing.T) {
 
 	videoQueue := &mocks.MockVideoQueue{}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
-
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	req := httptest.NewRequest(http.MethodGet, "/videos/download?id="+videoID, nil)
 	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
@@ -346,11 +505,7 @@ func TestVideoController_Download_MethodNotAllowed(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	videoQueue := &mocks.MockVideoQueue{}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
-
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	req := httptest.NewRequest(http.MethodPost, "/videos/download", nil)
 	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
@@ -378,11 +533,7 @@ func TestVideoController_Download_MissingVideoID(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	videoQueue := &mocks.MockVideoQueue{}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
-
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	req := httptest.NewRequest(http.MethodGet, "/videos/download", nil)
 	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
@@ -410,11 +561,7 @@ func TestVideoController_Download_MissingUserID(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	videoQueue := &mocks.MockVideoQueue{}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
-
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	req := httptest.NewRequest(http.MethodGet, "/videos/download?id=video-123", nil)
 	ctx := req.Context()
@@ -450,11 +597,7 @@ func TestVideoController_Download_VideoNotFound(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	videoQueue := &mocks.MockVideoQueue{}
 
-	uploadUsecase := usecases.NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-	listUsecase := usecases.NewListVideosUsecase(videoRepo)
-	downloadUsecase := usecases.NewDownloadVideoUsecase(videoRepo, storageService)
-
-	controller := NewVideoController(uploadUsecase, listUsecase, downloadUsecase)
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
 
 	req := httptest.NewRequest(http.MethodGet, "/videos/download?id="+videoID, nil)
 	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
@@ -476,3 +619,434 @@ func TestVideoController_Download_VideoNotFound(t *testing.T) {
 		t.Errorf("expected status code %d, got %d", http.StatusNotFound, httpErr.StatusCode)
 	}
 }
+
+func TestVideoController_RequestUploadURL_Success(t *testing.T) {
+	var savedVideo *entities.Video
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			savedVideo = video
+			return nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		GetPresignedUploadURLFunc: func(ctx context.Context, key, contentType string, contentLength int64, expirationMinutes int) (string, error) {
+			return "https://s3.amazonaws.com/upload?signature=xyz", nil
+		},
+	}
+	videoQueue := &mocks.MockVideoQueue{}
+
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
+
+	body := bytes.NewBufferString(`{"file_name":"test-video.mp4","content_type":"video/mp4","file_size":1024}`)
+	req := httptest.NewRequest(http.MethodPost, "/video/upload-url", body)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+	ctx = context.WithValue(ctx, middleware.EmailContextKey, "user@example.com")
+
+	w := httptest.NewRecorder()
+
+	err := controller.RequestUploadURL(ctx, w, req)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status code %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	var response dto.RequestUploadURLOutput
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if response.UploadURL == "" {
+		t.Error("expected UploadURL to be set")
+	}
+
+	if savedVideo == nil || !savedVideo.AwaitingUpload {
+		t.Error("expected video to be saved awaiting upload")
+	}
+}
+
+func TestVideoController_RequestUploadURL_InvalidBody(t *testing.T) {
+	videoRepo := &mocks.MockVideoRepository{}
+	storageService := &mocks.MockStorageService{}
+	videoQueue := &mocks.MockVideoQueue{}
+
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
+
+	req := httptest.NewRequest(http.MethodPost, "/video/upload-url", bytes.NewBufferString("not json"))
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+	ctx = context.WithValue(ctx, middleware.EmailContextKey, "user@example.com")
+
+	w := httptest.NewRecorder()
+
+	err := controller.RequestUploadURL(ctx, w, req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, httpErr.StatusCode)
+	}
+}
+
+func TestVideoController_ConfirmUpload_Success(t *testing.T) {
+	userID := "user-123"
+	video := &entities.Video{
+		ID:             "video-123",
+		UserID:         userID,
+		RawS3Key:       "raw/user-123/video-123/test.mp4",
+		Status:         entities.VideoStatusPending,
+		FileSize:       1024,
+		AwaitingUpload: true,
+	}
+
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return video, nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		StatFunc: func(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+			return &ports.ObjectInfo{Size: 1024}, nil
+		},
+	}
+	queued := false
+	videoQueue := &mocks.MockVideoQueue{
+		SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+			queued = true
+			return nil
+		},
+	}
+
+	controller := newTestVideoController(videoRepo, storageService, videoQueue)
+
+	req := httptest.NewRequest(http.MethodPost, "/video/video-123/confirm", nil)
+	req.SetPathValue("id", "video-123")
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
+
+	w := httptest.NewRecorder()
+
+	err := controller.ConfirmUpload(ctx, w, req)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected status code %d, got %d", http.StatusAccepted, w.Code)
+	}
+
+	if !queued {
+		t.Error("expected video to be queued for processing")
+	}
+}
+
+func TestVideoController_Delete_Success(t *testing.T) {
+	userID := "user-123"
+
+	deleted := false
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return &entities.Video{ID: id, UserID: userID, Status: entities.VideoStatusCompleted}, nil
+		},
+		DeleteFunc: func(ctx context.Context, video *entities.Video) error {
+			deleted = true
+			return nil
+		},
+	}
+
+	controller := newTestVideoController(videoRepo, &mocks.MockStorageService{}, &mocks.MockVideoQueue{})
+
+	req := httptest.NewRequest(http.MethodDelete, "/video/video-123", nil)
+	req.SetPathValue("id", "video-123")
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
+
+	w := httptest.NewRecorder()
+
+	err := controller.Delete(ctx, w, req)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status code %d, got %d", http.StatusNoContent, w.Code)
+	}
+
+	if !deleted {
+		t.Error("expected video to be deleted")
+	}
+}
+
+func TestVideoController_Delete_MethodNotAllowed(t *testing.T) {
+	controller := newTestVideoController(&mocks.MockVideoRepository{}, &mocks.MockStorageService{}, &mocks.MockVideoQueue{})
+
+	req := httptest.NewRequest(http.MethodGet, "/video/video-123", nil)
+	req.SetPathValue("id", "video-123")
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+
+	err := controller.Delete(ctx, httptest.NewRecorder(), req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok || httpErr.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected method not allowed, got %v", err)
+	}
+}
+
+func TestVideoController_Reprocess(t *testing.T) {
+	userID := "user-123"
+
+	tests := []struct {
+		name            string
+		body            string
+		expectedStatus  int
+		expectedOptions entities.ExtractionOptions
+	}{
+		{
+			name:            "should reprocess with the previous options without a body",
+			expectedStatus:  http.StatusAccepted,
+			expectedOptions: entities.ExtractionOptions{FPS: 2},
+		},
+		{
+			name:            "should reprocess with the options from the body",
+			body:            `{"options":{"mode":"keyframes"}}`,
+			expectedStatus:  http.StatusAccepted,
+			expectedOptions: entities.ExtractionOptions{Mode: entities.ExtractionModeKeyframes},
+		},
+		{
+			name:           "should return 400 for a malformed body",
+			body:           `{"options":`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return &entities.Video{
+						ID:                 id,
+						UserID:             userID,
+						RawS3Key:           "raw/user-123/video-123/test.mp4",
+						Status:             entities.VideoStatusFailed,
+						ExtractionOptions:  entities.ExtractionOptions{FPS: 2},
+						ProcessingAttempts: 1,
+					}, nil
+				},
+			}
+			var queuedMessage dto.VideoProcessMessage
+			videoQueue := &mocks.MockVideoQueue{
+				SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+					queuedMessage = message
+					return nil
+				},
+			}
+
+			controller := newTestVideoController(videoRepo, &mocks.MockStorageService{}, videoQueue)
+
+			req := httptest.NewRequest(http.MethodPost, "/video/video-123/reprocess", bytes.NewBufferString(tt.body))
+			req.SetPathValue("id", "video-123")
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
+
+			w := httptest.NewRecorder()
+
+			err := controller.Reprocess(ctx, w, req)
+
+			if tt.expectedStatus != http.StatusAccepted {
+				httpErr, ok := err.(*utils.HttpError)
+				if !ok || httpErr.StatusCode != tt.expectedStatus {
+					t.Errorf("expected status code %d, got %v", tt.expectedStatus, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if w.Code != http.StatusAccepted {
+				t.Errorf("expected status code %d, got %d", http.StatusAccepted, w.Code)
+			}
+			if queuedMessage.Options != tt.expectedOptions {
+				t.Errorf("expected queued options %+v, got %+v", tt.expectedOptions, queuedMessage.Options)
+			}
+		})
+	}
+}
+
+func TestVideoController_Cancel_Success(t *testing.T) {
+	userID := "user-123"
+
+	var savedStatus entities.VideoStatus
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return &entities.Video{ID: id, UserID: userID, Status: entities.VideoStatusProcessing}, nil
+		},
+		UpdateFunc: func(ctx context.Context, video *entities.Video) error {
+			savedStatus = video.Status
+			return nil
+		},
+	}
+
+	controller := newTestVideoController(videoRepo, &mocks.MockStorageService{}, &mocks.MockVideoQueue{})
+
+	req := httptest.NewRequest(http.MethodPost, "/video/video-123/cancel", nil)
+	req.SetPathValue("id", "video-123")
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
+
+	w := httptest.NewRecorder()
+
+	err := controller.Cancel(ctx, w, req)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if savedStatus != entities.VideoStatusCancelled {
+		t.Errorf("expected video to be cancelled, got %s", savedStatus)
+	}
+}
+
+func TestVideoController_Pin(t *testing.T) {
+	userID := "user-123"
+
+	for _, method := range []string{http.MethodPost, http.MethodDelete} {
+		t.Run(method, func(t *testing.T) {
+			var saved *entities.Video
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return &entities.Video{ID: id, UserID: userID, Status: entities.VideoStatusCompleted, Pinned: method == http.MethodDelete}, nil
+				},
+				UpdateFunc: func(ctx context.Context, video *entities.Video) error {
+					saved = video
+					return nil
+				},
+			}
+
+			controller := newTestVideoController(videoRepo, &mocks.MockStorageService{}, &mocks.MockVideoQueue{})
+
+			req := httptest.NewRequest(method, "/video/video-123/pin", nil)
+			req.SetPathValue("id", "video-123")
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
+			w := httptest.NewRecorder()
+
+			if err := controller.Pin(ctx, w, req); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if w.Code != http.StatusOK {
+				t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+			}
+
+			if saved == nil || saved.Pinned != (method == http.MethodPost) {
+				t.Errorf("expected video saved with pinned %v, got %+v", method == http.MethodPost, saved)
+			}
+		})
+	}
+}
+
+func TestVideoController_Frames(t *testing.T) {
+	userID := "user-123"
+	manifest := `{"format":"jpg","frames":[{"number":1,"name":"frame_0001.jpg","timestamp_seconds":0,"size_bytes":10},{"number":2,"name":"frame_0002.jpg","timestamp_seconds":1,"size_bytes":10}]}`
+
+	tests := []struct {
+		name         string
+		path         string
+		number       string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "should list a page of frames",
+			path:         "/video/video-123/frames?limit=1",
+			expectedCode: http.StatusOK,
+			expectedBody: `"next_token":"2"`,
+		},
+		{
+			name:         "should return a presigned URL for one frame",
+			path:         "/video/video-123/frames/2",
+			number:       "2",
+			expectedCode: http.StatusOK,
+			expectedBody: `"presigned_url":"https://s3.example.com/processed/user-123/video-123/frames/frame_0002.jpg"`,
+		},
+		{
+			name:         "should reject a frame number that is not a number",
+			path:         "/video/video-123/frames/first",
+			number:       "first",
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "should reject a limit that is not a number",
+			path:         "/video/video-123/frames?limit=all",
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return &entities.Video{ID: id, UserID: userID, Status: entities.VideoStatusCompleted, FramesS3Prefix: "processed/user-123/video-123/frames/"}, nil
+				},
+			}
+			storageService := &mocks.MockStorageService{
+				DownloadFunc: func(ctx context.Context, key string) ([]byte, error) {
+					return []byte(manifest), nil
+				},
+				GetPresignedURLFunc: func(ctx context.Context, key string, expirationMinutes int) (string, error) {
+					return "https://s3.example.com/" + key, nil
+				},
+			}
+
+			controller := newTestVideoController(videoRepo, storageService, &mocks.MockVideoQueue{})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.SetPathValue("id", "video-123")
+			if tt.number != "" {
+				req.SetPathValue("n", tt.number)
+			}
+			ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, userID)
+			w := httptest.NewRecorder()
+
+			err := controller.Frames(ctx, w, req)
+
+			if tt.expectedCode != http.StatusOK {
+				httpErr, ok := err.(*utils.HttpError)
+				if !ok || httpErr.StatusCode != tt.expectedCode {
+					t.Errorf("expected status code %d, got %v", tt.expectedCode, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if !bytes.Contains(w.Body.Bytes(), []byte(tt.expectedBody)) {
+				t.Errorf("expected body to contain %s, got %s", tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestVideoController_Cancel_MethodNotAllowed(t *testing.T) {
+	controller := newTestVideoController(&mocks.MockVideoRepository{}, &mocks.MockStorageService{}, &mocks.MockVideoQueue{})
+
+	req := httptest.NewRequest(http.MethodGet, "/video/video-123/cancel", nil)
+	req.SetPathValue("id", "video-123")
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+
+	err := controller.Cancel(ctx, httptest.NewRecorder(), req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok || httpErr.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected method not allowed, got %v", err)
+	}
+}
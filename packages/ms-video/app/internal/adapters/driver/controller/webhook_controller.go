@@ -0,0 +1,140 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/middleware"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// WebhookController manages the webhooks of the user. A single webhook is
+// addressed with the id query parameter.
+type WebhookController struct {
+	webhooksUsecase *usecases.WebhooksUsecase
+}
+
+func NewWebhookController(webhooksUsecase *usecases.WebhooksUsecase) *WebhookController {
+	return &WebhookController{
+		webhooksUsecase: webhooksUsecase,
+	}
+}
+
+// Webhooks lists or creates webhooks, or reads, updates or deletes the one
+// given by id.
+func (c *WebhookController) Webhooks(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	webhookID := r.URL.Query().Get("id")
+	if webhookID == "" {
+		switch r.Method {
+		case http.MethodGet:
+			return c.list(ctx, w, userID)
+		case http.MethodPost:
+			return c.create(ctx, w, r, userID)
+		}
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		result, err := c.webhooksUsecase.Get(ctx, webhookID, userID)
+		if err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusOK)
+		return json.NewEncoder(w).Encode(result)
+	case http.MethodPatch:
+		return c.update(ctx, w, r, webhookID, userID)
+	case http.MethodDelete:
+		if err := c.webhooksUsecase.Delete(ctx, webhookID, userID); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+}
+
+// Deliveries lists the latest deliveries to the webhook given by id.
+func (c *WebhookController) Deliveries(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet {
+		return utils.NewHttpError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := r.URL.Query()
+	webhookID := query.Get("id")
+	if webhookID == "" {
+		return utils.NewBadRequestError("missing webhook id parameter")
+	}
+
+	limit := 0
+	if value := query.Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil {
+			return utils.NewBadRequestError("limit must be a number")
+		}
+	}
+
+	result, err := c.webhooksUsecase.Deliveries(ctx, webhookID, userID, limit)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(result)
+}
+
+func (c *WebhookController) list(ctx context.Context, w http.ResponseWriter, userID string) error {
+	result, err := c.webhooksUsecase.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(result)
+}
+
+func (c *WebhookController) create(ctx context.Context, w http.ResponseWriter, r *http.Request, userID string) error {
+	var input dto.CreateWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.NewBadRequestError("invalid request body")
+	}
+	input.UserID = userID
+
+	result, err := c.webhooksUsecase.Create(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(result)
+}
+
+func (c *WebhookController) update(ctx context.Context, w http.ResponseWriter, r *http.Request, webhookID, userID string) error {
+	var input dto.UpdateWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		return utils.NewBadRequestError("invalid request body")
+	}
+	input.ID = webhookID
+	input.UserID = userID
+
+	result, err := c.webhooksUsecase.Update(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(result)
+}
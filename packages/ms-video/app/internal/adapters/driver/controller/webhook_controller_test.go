@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/middleware"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/usecases"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func newTestWebhookController(webhookRepo *mocks.MockWebhookRepository) *WebhookController {
+	return NewWebhookController(usecases.NewWebhooksUsecase(webhookRepo, &mocks.MockWebhookDeliveryRepository{}))
+}
+
+func TestWebhookController_Webhooks_Create(t *testing.T) {
+	var saved *entities.Webhook
+	controller := newTestWebhookController(&mocks.MockWebhookRepository{
+		SaveFunc: func(ctx context.Context, webhook *entities.Webhook) error {
+			saved = webhook
+			return nil
+		},
+	})
+
+	body := `{"url":"https://example.com/hook","events":["video.completed","video.failed"]}`
+	req := httptest.NewRequest(http.MethodPost, "/video/webhooks", strings.NewReader(body))
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+	w := httptest.NewRecorder()
+
+	if err := controller.Webhooks(ctx, w, req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status code %d, got %d", http.StatusCreated, w.Code)
+	}
+	if saved == nil || saved.UserID != "user-123" || len(saved.Events) != 2 {
+		t.Fatalf("expected the user's webhook to be saved, got %+v", saved)
+	}
+
+	var output dto.WebhookOutput
+	if err := json.NewDecoder(w.Body).Decode(&output); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if output.ID != saved.ID || output.Secret != saved.Secret {
+		t.Errorf("expected the webhook and its secret in the response, got %+v", output)
+	}
+}
+
+func TestWebhookController_Webhooks_Delete(t *testing.T) {
+	deleted := ""
+	controller := newTestWebhookController(&mocks.MockWebhookRepository{
+		FindByIDFunc: func(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+			return &entities.Webhook{ID: webhookID, UserID: "user-123"}, nil
+		},
+		DeleteFunc: func(ctx context.Context, webhookID string) error {
+			deleted = webhookID
+			return nil
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/video/webhooks?id=hook-1", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+	w := httptest.NewRecorder()
+
+	if err := controller.Webhooks(ctx, w, req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status code %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if deleted != "hook-1" {
+		t.Errorf("expected hook-1 to be deleted, got %q", deleted)
+	}
+}
+
+func TestWebhookController_Webhooks_MethodNotAllowed(t *testing.T) {
+	controller := newTestWebhookController(&mocks.MockWebhookRepository{})
+
+	for _, target := range []string{"/video/webhooks", "/video/webhooks?id=hook-1"} {
+		req := httptest.NewRequest(http.MethodPut, target, nil)
+		ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+
+		err := controller.Webhooks(ctx, httptest.NewRecorder(), req)
+
+		httpErr, ok := err.(*utils.HttpError)
+		if !ok {
+			t.Fatalf("expected HttpError for %s, got %T", target, err)
+		}
+		if httpErr.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("expected status code %d for %s, got %d", http.StatusMethodNotAllowed, target, httpErr.StatusCode)
+		}
+	}
+}
+
+func TestWebhookController_Deliveries_MissingWebhookID(t *testing.T) {
+	controller := newTestWebhookController(&mocks.MockWebhookRepository{})
+
+	req := httptest.NewRequest(http.MethodGet, "/video/webhooks/deliveries", nil)
+	ctx := context.WithValue(req.Context(), middleware.UserIDContextKey, "user-123")
+
+	err := controller.Deliveries(ctx, httptest.NewRecorder(), req)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+	if httpErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, httpErr.StatusCode)
+	}
+}
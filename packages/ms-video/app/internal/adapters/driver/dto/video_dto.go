@@ -1,11 +1,19 @@
 package dto
 
-import "mime/multipart"
+import (
+	"io"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+)
 
 type UploadVideoInput struct {
-	File      *multipart.FileHeader
-	UserID    string
-	UserEmail string
+	File        io.Reader
+	FileName    string
+	ContentType string
+	Options     entities.ExtractionOptions
+	UserID      string
+	UserEmail   string
 }
 
 type UploadVideoOutput struct {
@@ -13,10 +21,68 @@ type UploadVideoOutput struct {
 	OriginalName string `json:"original_name"`
 	Status       string `json:"status"`
 	Message      string `json:"message"`
+	ContentHash  string `json:"content_hash,omitempty"`
+	// Deduplicated is set when identical content was already processed
+	// with the same options, so its outputs are reused. DuplicateOf names
+	// that video only when it belongs to the same user.
+	Deduplicated bool   `json:"deduplicated"`
+	DuplicateOf  string `json:"duplicate_of,omitempty"`
+}
+
+type RequestUploadURLInput struct {
+	FileName    string                     `json:"file_name"`
+	ContentType string                     `json:"content_type"`
+	FileSize    int64                      `json:"file_size"`
+	Options     entities.ExtractionOptions `json:"options"`
+	UserID      string                     `json:"-"`
+	UserEmail   string                     `json:"-"`
+}
+
+type RequestUploadURLOutput struct {
+	VideoID   string            `json:"video_id"`
+	UploadURL string            `json:"upload_url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresIn int               `json:"expires_in"`
+}
+
+// ReprocessVideoInput replaces the extraction options of the video when
+// Options is set; otherwise it is processed with the options it had.
+type ReprocessVideoInput struct {
+	VideoID string                      `json:"-"`
+	UserID  string                      `json:"-"`
+	Options *entities.ExtractionOptions `json:"options"`
+}
+
+type CreateResumableUploadInput struct {
+	FileName    string
+	ContentType string
+	Length      int64
+	Options     entities.ExtractionOptions
+	UserID      string
+	UserEmail   string
+}
+
+type ResumableUploadOutput struct {
+	UploadID  string
+	VideoID   string
+	Offset    int64
+	Length    int64
+	ExpiresAt time.Time
+}
+
+type ListVideosInput struct {
+	UserID    string
+	Limit     int // zero for the default page size
+	NextToken string
+	Status    string
+	Sort      string // created_at or updated_at
+	Order     string // asc or desc
 }
 
 type ListVideosOutput struct {
-	Videos []VideoOutput `json:"videos"`
+	Videos    []VideoOutput `json:"videos"`
+	NextToken string        `json:"next_token,omitempty"`
 }
 
 type VideoOutput struct {
@@ -28,6 +94,13 @@ type VideoOutput struct {
 	ErrorMessage    string `json:"error_message,omitempty"`
 	CreatedAt       string `json:"created_at"`
 	UpdatedAt       string `json:"updated_at"`
+	// Pinned videos keep their outputs past the retention period. Once
+	// they expire, ExpirationReason says why.
+	Pinned           bool   `json:"pinned"`
+	ExpirationReason string `json:"expiration_reason,omitempty"`
+	RawDeletedAt     string `json:"raw_deleted_at,omitempty"`
+	// Metadata is left out until the video has been probed
+	Metadata *entities.VideoMetadata `json:"metadata,omitempty"`
 }
 
 type DownloadVideoOutput struct {
@@ -37,9 +110,76 @@ type DownloadVideoOutput struct {
 	ExpiresIn    int    `json:"expires_in"`
 }
 
+type ListFramesInput struct {
+	VideoID   string
+	UserID    string
+	Limit     int // zero for the default page size
+	NextToken string
+}
+
+type ListFramesOutput struct {
+	VideoID     string        `json:"video_id"`
+	Format      string        `json:"format"`
+	TotalFrames int           `json:"total_frames"`
+	Frames      []FrameOutput `json:"frames"`
+	NextToken   string        `json:"next_token,omitempty"`
+}
+
+type FrameOutput struct {
+	Number           int     `json:"number"`
+	TimestampSeconds float64 `json:"timestamp_seconds"`
+	SizeBytes        int64   `json:"size_bytes"`
+}
+
+type FrameURLOutput struct {
+	VideoID          string  `json:"video_id"`
+	Number           int     `json:"number"`
+	TimestampSeconds float64 `json:"timestamp_seconds"`
+	PresignedURL     string  `json:"presigned_url"`
+	ExpiresIn        int     `json:"expires_in"`
+}
+
+type PreviewsOutput struct {
+	VideoID         string   `json:"video_id"`
+	VTT             string   `json:"vtt"`
+	Sprites         []string `json:"sprites"`
+	Columns         int      `json:"columns"`
+	Rows            int      `json:"rows"`
+	ThumbnailWidth  int      `json:"thumbnail_width"`
+	ThumbnailHeight int      `json:"thumbnail_height"`
+	ExpiresIn       int      `json:"expires_in"`
+}
+
+// UsageOutput is where the user stands against their quotas. A max of 0 is
+// not enforced.
+type UsageOutput struct {
+	StoredBytes       int64  `json:"stored_bytes"`
+	MaxStoredBytes    int64  `json:"max_stored_bytes"`
+	VideoCount        int    `json:"video_count"`
+	MaxVideos         int    `json:"max_videos"`
+	UploadsThisHour   int    `json:"uploads_this_hour"`
+	MaxUploadsPerHour int    `json:"max_uploads_per_hour"`
+	WindowResetsAt    string `json:"window_resets_at"`
+}
+
+// RetentionSweepOutput counts what a retention sweep did, or would have done
+// in a dry run.
+type RetentionSweepOutput struct {
+	DryRun     bool  `json:"dry_run"`
+	Scanned    int   `json:"scanned"`
+	RawDeleted int   `json:"raw_deleted"`
+	Expired    int   `json:"expired"`
+	FreedBytes int64 `json:"freed_bytes"`
+	// Skipped videos changed since they were scanned and are looked at
+	// again by the next sweep
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
+
 type VideoProcessMessage struct {
-	VideoID   string `json:"video_id"`
-	UserID    string `json:"user_id"`
-	UserEmail string `json:"user_email"`
-	RawS3Key  string `json:"raw_s3_key"`
+	VideoID   string                     `json:"video_id"`
+	UserID    string                     `json:"user_id"`
+	UserEmail string                     `json:"user_email"`
+	RawS3Key  string                     `json:"raw_s3_key"`
+	Options   entities.ExtractionOptions `json:"options"`
 }
@@ -0,0 +1,60 @@
+package dto
+
+type CreateWebhookInput struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	UserID string   `json:"-"`
+}
+
+// UpdateWebhookInput changes the fields that are set.
+type UpdateWebhookInput struct {
+	ID     string    `json:"-"`
+	UserID string    `json:"-"`
+	URL    *string   `json:"url"`
+	Secret *string   `json:"secret"`
+	Events *[]string `json:"events"`
+}
+
+// WebhookOutput only carries the secret when it was just created or
+// changed; it cannot be read back afterwards.
+type WebhookOutput struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+type ListWebhooksOutput struct {
+	Webhooks []WebhookOutput `json:"webhooks"`
+}
+
+type WebhookDeliveryOutput struct {
+	ID             string `json:"id"`
+	Event          string `json:"event"`
+	VideoID        string `json:"video_id"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	ResponseStatus int    `json:"response_status,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+type ListWebhookDeliveriesOutput struct {
+	Deliveries []WebhookDeliveryOutput `json:"deliveries"`
+}
+
+// WebhookPayload is the body posted to webhook receivers.
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt string      `json:"created_at"`
+	Video     VideoOutput `json:"video"`
+}
+
+type WebhookDeliveryMessage struct {
+	DeliveryID string `json:"delivery_id"`
+}
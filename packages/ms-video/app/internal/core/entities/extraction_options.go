@@ -0,0 +1,164 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+)
+
+type FrameFormat string
+
+const (
+	FrameFormatJPG  FrameFormat = "jpg"
+	FrameFormatPNG  FrameFormat = "png"
+	FrameFormatWEBP FrameFormat = "webp"
+)
+
+// ContentType is the MIME type of frames in the format.
+func (f FrameFormat) ContentType() string {
+	switch f {
+	case FrameFormatPNG:
+		return "image/png"
+	case FrameFormatWEBP:
+		return "image/webp"
+	}
+	return "image/jpeg"
+}
+
+// ExtractionMode selects which frames are extracted.
+type ExtractionMode string
+
+const (
+	// ExtractionModeFixedRate samples frames at a fixed rate.
+	ExtractionModeFixedRate ExtractionMode = "fixed"
+	// ExtractionModeScene keeps frames that differ from the previous one by
+	// more than the scene threshold.
+	ExtractionModeScene ExtractionMode = "scene"
+	// ExtractionModeKeyframes keeps only the I-frames of the video.
+	ExtractionModeKeyframes ExtractionMode = "keyframes"
+)
+
+const (
+	DefaultFrameRate      = 1.0
+	DefaultFrameFormat    = FrameFormatJPG
+	DefaultFrameQuality   = 100
+	DefaultSceneThreshold = 0.3
+)
+
+// ExtractionOptions controls how frames are sampled from a video. Zero values
+// fall back to the defaults: one JPEG per second at full quality and
+// resolution, covering the whole video.
+type ExtractionOptions struct {
+	Mode            ExtractionMode `json:"mode,omitempty" dynamodbav:"mode,omitempty"`
+	SceneThreshold  float64        `json:"scene_threshold,omitempty" dynamodbav:"scene_threshold,omitempty"`
+	FPS             float64        `json:"fps,omitempty" dynamodbav:"fps,omitempty"`
+	IntervalSeconds float64        `json:"interval_seconds,omitempty" dynamodbav:"interval_seconds,omitempty"`
+	Format          FrameFormat    `json:"format,omitempty" dynamodbav:"format,omitempty"`
+	MaxWidth        int            `json:"max_width,omitempty" dynamodbav:"max_width,omitempty"`
+	MaxHeight       int            `json:"max_height,omitempty" dynamodbav:"max_height,omitempty"`
+	Quality         int            `json:"quality,omitempty" dynamodbav:"quality,omitempty"`
+	StartSeconds    float64        `json:"start_seconds,omitempty" dynamodbav:"start_seconds,omitempty"`
+	EndSeconds      float64        `json:"end_seconds,omitempty" dynamodbav:"end_seconds,omitempty"`
+	// HLS also transcodes the video into an HLS bitrate ladder for streaming.
+	HLS bool `json:"hls,omitempty" dynamodbav:"hls,omitempty"`
+}
+
+func DefaultExtractionOptions() ExtractionOptions {
+	return ExtractionOptions{}.WithDefaults()
+}
+
+// WithDefaults returns a copy with every unset field filled in.
+func (o ExtractionOptions) WithDefaults() ExtractionOptions {
+	if o.Mode == "" {
+		o.Mode = ExtractionModeFixedRate
+	}
+	if o.Mode == ExtractionModeFixedRate && o.FPS == 0 && o.IntervalSeconds == 0 {
+		o.FPS = DefaultFrameRate
+	}
+	if o.Mode == ExtractionModeScene && o.SceneThreshold == 0 {
+		o.SceneThreshold = DefaultSceneThreshold
+	}
+	if o.Format == "" {
+		o.Format = DefaultFrameFormat
+	}
+	if o.Quality == 0 {
+		o.Quality = DefaultFrameQuality
+	}
+	return o
+}
+
+// IsFixedRate reports whether frames are sampled at a known rate, which is
+// what makes the number of extracted frames predictable.
+func (o ExtractionOptions) IsFixedRate() bool {
+	return o.Mode == "" || o.Mode == ExtractionModeFixedRate
+}
+
+// FrameRate is the number of frames sampled per second of video in fixed
+// rate mode.
+func (o ExtractionOptions) FrameRate() float64 {
+	if o.IntervalSeconds > 0 {
+		return 1 / o.IntervalSeconds
+	}
+	if o.FPS > 0 {
+		return o.FPS
+	}
+	return DefaultFrameRate
+}
+
+// Validate checks the options for internal consistency. Server-side limits
+// are enforced by the caller.
+func (o ExtractionOptions) Validate() error {
+	for _, v := range []float64{o.FPS, o.IntervalSeconds, o.SceneThreshold, o.StartSeconds, o.EndSeconds} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("fps, interval_seconds, scene_threshold, start_seconds and end_seconds must be finite numbers")
+		}
+	}
+
+	switch o.Mode {
+	case "", ExtractionModeFixedRate:
+		if o.SceneThreshold != 0 {
+			return fmt.Errorf("scene_threshold can only be used in scene mode")
+		}
+	case ExtractionModeScene, ExtractionModeKeyframes:
+		if o.FPS != 0 || o.IntervalSeconds != 0 {
+			return fmt.Errorf("fps and interval_seconds can only be used in fixed mode")
+		}
+		if o.Mode == ExtractionModeKeyframes && o.SceneThreshold != 0 {
+			return fmt.Errorf("scene_threshold can only be used in scene mode")
+		}
+	default:
+		return fmt.Errorf("invalid mode %q. Allowed modes: fixed, scene, keyframes", o.Mode)
+	}
+
+	if o.SceneThreshold < 0 || o.SceneThreshold > 1 {
+		return fmt.Errorf("scene_threshold must be between 0 and 1")
+	}
+
+	if o.FPS < 0 || o.IntervalSeconds < 0 {
+		return fmt.Errorf("fps and interval_seconds must be positive")
+	}
+	if o.FPS > 0 && o.IntervalSeconds > 0 {
+		return fmt.Errorf("fps and interval_seconds cannot be used together")
+	}
+
+	switch o.Format {
+	case "", FrameFormatJPG, FrameFormatPNG, FrameFormatWEBP:
+	default:
+		return fmt.Errorf("invalid frame format %q. Allowed formats: jpg, png, webp", o.Format)
+	}
+
+	if o.MaxWidth < 0 || o.MaxHeight < 0 {
+		return fmt.Errorf("max_width and max_height must be positive")
+	}
+	if o.Quality < 0 || o.Quality > 100 {
+		return fmt.Errorf("quality must be between 1 and 100")
+	}
+
+	if o.StartSeconds < 0 || o.EndSeconds < 0 {
+		return fmt.Errorf("start_seconds and end_seconds must be positive")
+	}
+	if o.EndSeconds > 0 && o.EndSeconds <= o.StartSeconds {
+		return fmt.Errorf("end_seconds must be after start_seconds")
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+package entities
+
+import (
+	"math"
+	"testing"
+)
+
+func TestExtractionOptions_WithDefaults(t *testing.T) {
+	options := ExtractionOptions{IntervalSeconds: 10, Format: FrameFormatPNG}.WithDefaults()
+
+	if options.FPS != 0 {
+		t.Errorf("expected fps to stay unset when an interval is given, got %v", options.FPS)
+	}
+
+	if options.Format != FrameFormatPNG {
+		t.Errorf("expected format '%s', got '%s'", FrameFormatPNG, options.Format)
+	}
+
+	if options.Quality != DefaultFrameQuality {
+		t.Errorf("expected quality %d, got %d", DefaultFrameQuality, options.Quality)
+	}
+
+	defaults := DefaultExtractionOptions()
+	if defaults.FPS != DefaultFrameRate || defaults.Format != DefaultFrameFormat {
+		t.Errorf("expected default fps %v and format '%s', got %v and '%s'", DefaultFrameRate, DefaultFrameFormat, defaults.FPS, defaults.Format)
+	}
+}
+
+func TestExtractionOptions_WithDefaults_Modes(t *testing.T) {
+	scene := ExtractionOptions{Mode: ExtractionModeScene}.WithDefaults()
+
+	if scene.SceneThreshold != DefaultSceneThreshold {
+		t.Errorf("expected scene threshold %v, got %v", DefaultSceneThreshold, scene.SceneThreshold)
+	}
+
+	if scene.FPS != 0 || scene.IsFixedRate() {
+		t.Error("expected scene mode not to get a fixed frame rate")
+	}
+
+	fixed := ExtractionOptions{}.WithDefaults()
+	if fixed.Mode != ExtractionModeFixedRate || !fixed.IsFixedRate() {
+		t.Errorf("expected fixed mode by default, got '%s'", fixed.Mode)
+	}
+}
+
+func TestExtractionOptions_FrameRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  ExtractionOptions
+		expected float64
+	}{
+		{
+			name:     "should use fps",
+			options:  ExtractionOptions{FPS: 5},
+			expected: 5,
+		},
+		{
+			name:     "should convert interval to rate",
+			options:  ExtractionOptions{IntervalSeconds: 4},
+			expected: 0.25,
+		},
+		{
+			name:     "should fall back to default rate",
+			options:  ExtractionOptions{},
+			expected: DefaultFrameRate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rate := tt.options.FrameRate(); rate != tt.expected {
+				t.Errorf("expected rate %v, got %v", tt.expected, rate)
+			}
+		})
+	}
+}
+
+func TestExtractionOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ExtractionOptions
+		wantErr bool
+	}{
+		{
+			name:    "should accept empty options",
+			options: ExtractionOptions{},
+		},
+		{
+			name:    "should accept full options",
+			options: ExtractionOptions{FPS: 2, Format: FrameFormatWEBP, MaxWidth: 640, Quality: 80, StartSeconds: 5, EndSeconds: 65},
+		},
+		{
+			name:    "should reject fps together with interval",
+			options: ExtractionOptions{FPS: 2, IntervalSeconds: 10},
+			wantErr: true,
+		},
+		{
+			name:    "should reject negative fps",
+			options: ExtractionOptions{FPS: -1},
+			wantErr: true,
+		},
+		{
+			name:    "should reject unknown format",
+			options: ExtractionOptions{Format: "gif"},
+			wantErr: true,
+		},
+		{
+			name:    "should reject quality above 100",
+			options: ExtractionOptions{Quality: 101},
+			wantErr: true,
+		},
+		{
+			name:    "should accept scene mode with threshold",
+			options: ExtractionOptions{Mode: ExtractionModeScene, SceneThreshold: 0.4},
+		},
+		{
+			name:    "should accept keyframes mode",
+			options: ExtractionOptions{Mode: ExtractionModeKeyframes, Format: FrameFormatPNG},
+		},
+		{
+			name:    "should reject unknown mode",
+			options: ExtractionOptions{Mode: "random"},
+			wantErr: true,
+		},
+		{
+			name:    "should reject fps in scene mode",
+			options: ExtractionOptions{Mode: ExtractionModeScene, FPS: 2},
+			wantErr: true,
+		},
+		{
+			name:    "should reject scene threshold in fixed mode",
+			options: ExtractionOptions{SceneThreshold: 0.3},
+			wantErr: true,
+		},
+		{
+			name:    "should reject scene threshold above 1",
+			options: ExtractionOptions{Mode: ExtractionModeScene, SceneThreshold: 1.5},
+			wantErr: true,
+		},
+		{
+			name:    "should reject end before start",
+			options: ExtractionOptions{StartSeconds: 30, EndSeconds: 10},
+			wantErr: true,
+		},
+		{
+			name:    "should reject NaN fps",
+			options: ExtractionOptions{FPS: math.NaN()},
+			wantErr: true,
+		},
+		{
+			name:    "should reject infinite interval",
+			options: ExtractionOptions{IntervalSeconds: math.Inf(1)},
+			wantErr: true,
+		},
+		{
+			name:    "should reject NaN scene threshold",
+			options: ExtractionOptions{Mode: ExtractionModeScene, SceneThreshold: math.NaN()},
+			wantErr: true,
+		},
+		{
+			name:    "should reject NaN start",
+			options: ExtractionOptions{StartSeconds: math.NaN()},
+			wantErr: true,
+		},
+		{
+			name:    "should reject infinite end",
+			options: ExtractionOptions{EndSeconds: math.Inf(1)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+
+			if tt.wantErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+
+			if !tt.wantErr && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package entities
+
+import "fmt"
+
+// FramesManifestName is the name of the manifest stored with the frames of
+// a video.
+const FramesManifestName = "manifest.json"
+
+// FrameManifest lists the frames of a video that are stored one object
+// each, in the order they were extracted.
+type FrameManifest struct {
+	Format FrameFormat          `json:"format"`
+	Frames []FrameManifestEntry `json:"frames"`
+}
+
+type FrameManifestEntry struct {
+	// Number counts frames from 1, as they are numbered in the ZIP
+	Number int `json:"number"`
+	// Name is the object of the frame, relative to the manifest
+	Name             string  `json:"name"`
+	TimestampSeconds float64 `json:"timestamp_seconds"`
+	SizeBytes        int64   `json:"size_bytes"`
+}
+
+// FrameName is the file name of frame number n, counting from 1.
+func FrameName(number int, format FrameFormat) string {
+	return fmt.Sprintf("frame_%04d.%s", number, format)
+}
+
+// Frame returns frame number n, or nil when the video has no such frame.
+func (m *FrameManifest) Frame(number int) *FrameManifestEntry {
+	if number < 1 || number > len(m.Frames) {
+		return nil
+	}
+	// Frames are numbered without gaps, so the number is also the position
+	if frame := &m.Frames[number-1]; frame.Number == number {
+		return frame
+	}
+	for i := range m.Frames {
+		if m.Frames[i].Number == number {
+			return &m.Frames[i]
+		}
+	}
+	return nil
+}
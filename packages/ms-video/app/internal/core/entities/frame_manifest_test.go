@@ -0,0 +1,50 @@
+package entities
+
+import "testing"
+
+func TestFrameName(t *testing.T) {
+	if name := FrameName(7, FrameFormatPNG); name != "frame_0007.png" {
+		t.Errorf("expected frame_0007.png, got %s", name)
+	}
+	if name := FrameName(12345, FrameFormatJPG); name != "frame_12345.jpg" {
+		t.Errorf("expected frame_12345.jpg, got %s", name)
+	}
+}
+
+func TestFrameManifest_Frame(t *testing.T) {
+	manifest := &FrameManifest{
+		Format: FrameFormatJPG,
+		Frames: []FrameManifestEntry{
+			{Number: 1, Name: "frame_0001.jpg", TimestampSeconds: 0},
+			{Number: 2, Name: "frame_0002.jpg", TimestampSeconds: 1.5},
+			{Number: 3, Name: "frame_0003.jpg", TimestampSeconds: 4},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		number   int
+		expected string
+	}{
+		{name: "should find the first frame", number: 1, expected: "frame_0001.jpg"},
+		{name: "should find the last frame", number: 3, expected: "frame_0003.jpg"},
+		{name: "should not find frame 0", number: 0},
+		{name: "should not find a frame past the end", number: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := manifest.Frame(tt.number)
+
+			if tt.expected == "" {
+				if frame != nil {
+					t.Errorf("expected no frame, got %+v", frame)
+				}
+				return
+			}
+			if frame == nil || frame.Name != tt.expected {
+				t.Errorf("expected %s, got %+v", tt.expected, frame)
+			}
+		})
+	}
+}
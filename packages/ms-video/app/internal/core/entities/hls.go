@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HLSRendition is one rung of the HLS bitrate ladder.
+type HLSRendition struct {
+	Height           int
+	VideoBitrateKbps int
+	AudioBitrateKbps int
+}
+
+// Name is used for the rendition's playlist and segment files.
+func (r HLSRendition) Name() string {
+	return fmt.Sprintf("%dp", r.Height)
+}
+
+func DefaultHLSLadder() []HLSRendition {
+	return []HLSRendition{
+		{Height: 1080, VideoBitrateKbps: 5000, AudioBitrateKbps: 128},
+		{Height: 720, VideoBitrateKbps: 2800, AudioBitrateKbps: 128},
+		{Height: 360, VideoBitrateKbps: 800, AudioBitrateKbps: 96},
+	}
+}
+
+// ParseHLSLadder reads a ladder written as comma separated height:kbps pairs,
+// for example "1080:5000,720:2800,360:800".
+func ParseHLSLadder(value string) ([]HLSRendition, error) {
+	var ladder []HLSRendition
+	for _, rung := range strings.Split(value, ",") {
+		height, bitrate, found := strings.Cut(strings.TrimSpace(rung), ":")
+		if !found {
+			return nil, fmt.Errorf("invalid rendition %q, expected height:kbps", rung)
+		}
+
+		h, err := strconv.Atoi(height)
+		if err != nil || h <= 0 {
+			return nil, fmt.Errorf("invalid rendition height %q", height)
+		}
+		b, err := strconv.Atoi(bitrate)
+		if err != nil || b <= 0 {
+			return nil, fmt.Errorf("invalid rendition bitrate %q", bitrate)
+		}
+
+		audio := 128
+		if h < 480 {
+			audio = 96
+		}
+		ladder = append(ladder, HLSRendition{Height: h, VideoBitrateKbps: b, AudioBitrateKbps: audio})
+	}
+
+	return ladder, nil
+}
+
+// HLSRenditionsFor picks the renditions that fit a source of the given
+// height, so nothing is upscaled. A source smaller than every rung still
+// gets the smallest one, at its own height.
+func HLSRenditionsFor(ladder []HLSRendition, sourceHeight int) []HLSRendition {
+	var renditions []HLSRendition
+	smallest := -1
+	for i, rendition := range ladder {
+		if rendition.Height <= sourceHeight {
+			renditions = append(renditions, rendition)
+		}
+		if smallest < 0 || rendition.Height < ladder[smallest].Height {
+			smallest = i
+		}
+	}
+
+	if len(renditions) == 0 && smallest >= 0 {
+		rendition := ladder[smallest]
+		if sourceHeight > 0 {
+			rendition.Height = sourceHeight - sourceHeight%2
+		}
+		renditions = append(renditions, rendition)
+	}
+
+	return renditions
+}
@@ -0,0 +1,71 @@
+package entities
+
+import "testing"
+
+func TestParseHLSLadder(t *testing.T) {
+	ladder, err := ParseHLSLadder("1080:5000, 720:2800,360:800")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(ladder) != 3 {
+		t.Fatalf("expected 3 renditions, got %d", len(ladder))
+	}
+
+	if ladder[1].Height != 720 || ladder[1].VideoBitrateKbps != 2800 {
+		t.Errorf("expected 720p at 2800kbps, got %+v", ladder[1])
+	}
+
+	if ladder[2].AudioBitrateKbps != 96 {
+		t.Errorf("expected lower audio bitrate for 360p, got %d", ladder[2].AudioBitrateKbps)
+	}
+
+	for _, invalid := range []string{"", "1080", "1080:fast", "-720:2800"} {
+		if _, err := ParseHLSLadder(invalid); err == nil {
+			t.Errorf("expected error for ladder %q", invalid)
+		}
+	}
+}
+
+func TestHLSRenditionsFor(t *testing.T) {
+	ladder := DefaultHLSLadder()
+
+	tests := []struct {
+		name         string
+		sourceHeight int
+		expected     []int
+	}{
+		{
+			name:         "should keep every rendition for 1080p source",
+			sourceHeight: 1080,
+			expected:     []int{1080, 720, 360},
+		},
+		{
+			name:         "should not upscale 720p source",
+			sourceHeight: 720,
+			expected:     []int{720, 360},
+		},
+		{
+			name:         "should use source height when smaller than every rendition",
+			sourceHeight: 241,
+			expected:     []int{240},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renditions := HLSRenditionsFor(ladder, tt.sourceHeight)
+
+			if len(renditions) != len(tt.expected) {
+				t.Fatalf("expected %d renditions, got %d", len(tt.expected), len(renditions))
+			}
+
+			for i, height := range tt.expected {
+				if renditions[i].Height != height {
+					t.Errorf("expected rendition %d to be %dp, got %dp", i, height, renditions[i].Height)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package entities
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	StreamTypeVideo = "video"
+	StreamTypeAudio = "audio"
+)
+
+// MediaInfo is what a prober found inside an uploaded file.
+type MediaInfo struct {
+	FormatName string        `json:"format_name"`
+	Duration   time.Duration `json:"duration"`
+	// BitRate is the overall bitrate of the file in bits per second
+	BitRate int64         `json:"bit_rate,omitempty"`
+	Streams []MediaStream `json:"streams"`
+}
+
+type MediaStream struct {
+	Index     int    `json:"index"`
+	CodecType string `json:"codec_type"`
+	CodecName string `json:"codec_name"`
+	Width     int    `json:"width,omitempty"`
+	Height    int    `json:"height,omitempty"`
+	// FrameRate is the average frames per second of a video stream
+	FrameRate float64 `json:"frame_rate,omitempty"`
+	BitRate   int64   `json:"bit_rate,omitempty"`
+	// Rotation is how many degrees clockwise a player turns the frames to
+	// show them upright, between 0 and 359
+	Rotation int `json:"rotation,omitempty"`
+}
+
+// VideoStream returns the first video stream, or nil when the file has none.
+func (m *MediaInfo) VideoStream() *MediaStream {
+	return m.stream(StreamTypeVideo)
+}
+
+// AudioStream returns the first audio stream, or nil when the file has none.
+func (m *MediaInfo) AudioStream() *MediaStream {
+	return m.stream(StreamTypeAudio)
+}
+
+func (m *MediaInfo) stream(codecType string) *MediaStream {
+	for i := range m.Streams {
+		if m.Streams[i].CodecType == codecType {
+			return &m.Streams[i]
+		}
+	}
+	return nil
+}
+
+// VideoMetadata is the technical description of a video kept on the video
+// once it has been probed. Width and Height are those of the encoded frames,
+// before Rotation is applied.
+type VideoMetadata struct {
+	DurationSeconds float64 `json:"duration_seconds" dynamodbav:"duration_seconds"`
+	// Container lists the names of the formats the file can be read as,
+	// separated by commas, such as "mov,mp4,m4a,3gp,3g2,mj2"
+	Container  string  `json:"container" dynamodbav:"container"`
+	VideoCodec string  `json:"video_codec,omitempty" dynamodbav:"video_codec,omitempty"`
+	AudioCodec string  `json:"audio_codec,omitempty" dynamodbav:"audio_codec,omitempty"`
+	Width      int     `json:"width,omitempty" dynamodbav:"width,omitempty"`
+	Height     int     `json:"height,omitempty" dynamodbav:"height,omitempty"`
+	FrameRate  float64 `json:"frame_rate,omitempty" dynamodbav:"frame_rate,omitempty"`
+	// BitRate is in bits per second
+	BitRate  int64 `json:"bit_rate,omitempty" dynamodbav:"bit_rate,omitempty"`
+	Rotation int   `json:"rotation" dynamodbav:"rotation"`
+}
+
+// NewVideoMetadata describes the video from what the prober found in it.
+// The bitrate of the video stream stands in when the container reports
+// none.
+func NewVideoMetadata(info *MediaInfo) *VideoMetadata {
+	metadata := &VideoMetadata{
+		DurationSeconds: math.Round(info.Duration.Seconds()*1000) / 1000,
+		Container:       strings.TrimSpace(info.FormatName),
+		BitRate:         info.BitRate,
+	}
+
+	if stream := info.VideoStream(); stream != nil {
+		metadata.VideoCodec = stream.CodecName
+		metadata.Width = stream.Width
+		metadata.Height = stream.Height
+		metadata.FrameRate = math.Round(stream.FrameRate*1000) / 1000
+		metadata.Rotation = stream.Rotation
+		if metadata.BitRate == 0 {
+			metadata.BitRate = stream.BitRate
+		}
+	}
+	if stream := info.AudioStream(); stream != nil {
+		metadata.AudioCodec = stream.CodecName
+	}
+
+	return metadata
+}
@@ -0,0 +1,106 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMediaInfo_VideoStream(t *testing.T) {
+	info := &MediaInfo{
+		Streams: []MediaStream{
+			{Index: 0, CodecType: StreamTypeAudio, CodecName: "aac"},
+			{Index: 1, CodecType: StreamTypeVideo, CodecName: "h264", Width: 1920, Height: 1080},
+		},
+	}
+
+	stream := info.VideoStream()
+
+	if stream == nil {
+		t.Fatal("expected video stream, got nil")
+	}
+
+	if stream.Index != 1 || stream.CodecName != "h264" {
+		t.Errorf("expected h264 stream at index 1, got %+v", stream)
+	}
+
+	audioOnly := &MediaInfo{Streams: []MediaStream{{CodecType: StreamTypeAudio}}}
+	if audioOnly.VideoStream() != nil {
+		t.Error("expected nil video stream for audio-only file")
+	}
+}
+
+func TestNewVideoMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     *MediaInfo
+		expected VideoMetadata
+	}{
+		{
+			name: "should describe the video and audio streams",
+			info: &MediaInfo{
+				FormatName: "mov,mp4,m4a,3gp,3g2,mj2",
+				Duration:   90500 * time.Millisecond,
+				BitRate:    2500000,
+				Streams: []MediaStream{
+					{Index: 0, CodecType: StreamTypeVideo, CodecName: "h264", Width: 1920, Height: 1080, FrameRate: 30000.0 / 1001, BitRate: 2300000, Rotation: 90},
+					{Index: 1, CodecType: StreamTypeAudio, CodecName: "aac", BitRate: 128000},
+				},
+			},
+			expected: VideoMetadata{
+				DurationSeconds: 90.5,
+				Container:       "mov,mp4,m4a,3gp,3g2,mj2",
+				VideoCodec:      "h264",
+				AudioCodec:      "aac",
+				Width:           1920,
+				Height:          1080,
+				FrameRate:       29.97,
+				BitRate:         2500000,
+				Rotation:        90,
+			},
+		},
+		{
+			name: "should fall back to the bitrate of the video stream",
+			info: &MediaInfo{
+				FormatName: "matroska,webm",
+				Duration:   time.Second,
+				Streams: []MediaStream{
+					{CodecType: StreamTypeVideo, CodecName: "vp9", Width: 640, Height: 360, FrameRate: 25, BitRate: 800000},
+				},
+			},
+			expected: VideoMetadata{
+				DurationSeconds: 1,
+				Container:       "matroska,webm",
+				VideoCodec:      "vp9",
+				Width:           640,
+				Height:          360,
+				FrameRate:       25,
+				BitRate:         800000,
+			},
+		},
+		{
+			name: "should leave out the video fields for an audio-only file",
+			info: &MediaInfo{
+				FormatName: "mp3",
+				Duration:   time.Minute,
+				BitRate:    192000,
+				Streams:    []MediaStream{{CodecType: StreamTypeAudio, CodecName: "mp3"}},
+			},
+			expected: VideoMetadata{
+				DurationSeconds: 60,
+				Container:       "mp3",
+				AudioCodec:      "mp3",
+				BitRate:         192000,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata := NewVideoMetadata(tt.info)
+
+			if *metadata != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, *metadata)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package entities
+
+import "time"
+
+// RetentionPolicy is how long storage is kept once a video has been
+// processed. A zero period keeps it forever.
+type RetentionPolicy struct {
+	// RawDays deletes the raw upload of a completed or expired video
+	RawDays int
+	// ProcessedDays expires the ZIP, previews and HLS output of a completed
+	// video that is not pinned
+	ProcessedDays int
+}
+
+// RawExpired reports whether the raw upload of the video is past its
+// retention period at now.
+func (p RetentionPolicy) RawExpired(video *Video, now time.Time) bool {
+	if p.RawDays <= 0 || video.RawDeletedAt != nil || video.AwaitingUpload {
+		return false
+	}
+	if video.Status != VideoStatusCompleted && video.Status != VideoStatusExpired {
+		return false
+	}
+	return !now.Before(video.RetentionStart().Add(days(p.RawDays)))
+}
+
+// ProcessedExpired reports whether the outputs of the video are past their
+// retention period at now.
+func (p RetentionPolicy) ProcessedExpired(video *Video, now time.Time) bool {
+	if p.ProcessedDays <= 0 || video.Pinned || video.Status != VideoStatusCompleted {
+		return false
+	}
+	return !now.Before(video.RetentionStart().Add(days(p.ProcessedDays)))
+}
+
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
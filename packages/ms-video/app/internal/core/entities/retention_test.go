@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionPolicy(t *testing.T) {
+	completedAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := RetentionPolicy{RawDays: 7, ProcessedDays: 30}
+
+	tests := []struct {
+		name              string
+		video             Video
+		policy            RetentionPolicy
+		now               time.Time
+		expectedRaw       bool
+		expectedProcessed bool
+	}{
+		{
+			name:   "should keep everything within both periods",
+			video:  Video{Status: VideoStatusCompleted, CompletedAt: &completedAt},
+			policy: policy,
+			now:    completedAt.Add(6 * 24 * time.Hour),
+		},
+		{
+			name:        "should delete the raw upload after its period",
+			video:       Video{Status: VideoStatusCompleted, CompletedAt: &completedAt},
+			policy:      policy,
+			now:         completedAt.Add(7 * 24 * time.Hour),
+			expectedRaw: true,
+		},
+		{
+			name:              "should expire outputs after their period",
+			video:             Video{Status: VideoStatusCompleted, CompletedAt: &completedAt, RawDeletedAt: &completedAt},
+			policy:            policy,
+			now:               completedAt.Add(30 * 24 * time.Hour),
+			expectedProcessed: true,
+		},
+		{
+			name:        "should keep the outputs of pinned videos",
+			video:       Video{Status: VideoStatusCompleted, CompletedAt: &completedAt, Pinned: true},
+			policy:      policy,
+			now:         completedAt.Add(365 * 24 * time.Hour),
+			expectedRaw: true,
+		},
+		{
+			name:        "should delete the raw upload of expired videos",
+			video:       Video{Status: VideoStatusExpired, CompletedAt: &completedAt},
+			policy:      RetentionPolicy{RawDays: 60, ProcessedDays: 30},
+			now:         completedAt.Add(60 * 24 * time.Hour),
+			expectedRaw: true,
+		},
+		{
+			name:   "should not touch videos that did not complete",
+			video:  Video{Status: VideoStatusFailed, UpdatedAt: completedAt},
+			policy: policy,
+			now:    completedAt.Add(365 * 24 * time.Hour),
+		},
+		{
+			name:              "should start from the last update of videos without a completion time",
+			video:             Video{Status: VideoStatusCompleted, UpdatedAt: completedAt},
+			policy:            policy,
+			now:               completedAt.Add(30 * 24 * time.Hour),
+			expectedRaw:       true,
+			expectedProcessed: true,
+		},
+		{
+			name:   "should keep everything with zero periods",
+			video:  Video{Status: VideoStatusCompleted, CompletedAt: &completedAt},
+			policy: RetentionPolicy{},
+			now:    completedAt.Add(365 * 24 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.RawExpired(&tt.video, tt.now); got != tt.expectedRaw {
+				t.Errorf("expected RawExpired %v, got %v", tt.expectedRaw, got)
+			}
+			if got := tt.policy.ProcessedExpired(&tt.video, tt.now); got != tt.expectedProcessed {
+				t.Errorf("expected ProcessedExpired %v, got %v", tt.expectedProcessed, got)
+			}
+		})
+	}
+}
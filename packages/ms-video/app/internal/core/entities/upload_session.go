@@ -0,0 +1,130 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UploadSessionTTL is how long an unfinished resumable upload is kept before
+// it expires.
+const UploadSessionTTL = 24 * time.Hour
+
+type UploadPart struct {
+	PartNumber int32  `json:"part_number" dynamodbav:"part_number"`
+	ETag       string `json:"etag" dynamodbav:"etag"`
+	Size       int64  `json:"size" dynamodbav:"size"`
+}
+
+// UploadSession tracks a resumable upload. Received bytes are flushed to the
+// S3 multipart upload in full parts; whatever does not fill a part yet is
+// kept in a tail object until the next chunk arrives.
+type UploadSession struct {
+	ID        string       `json:"id" dynamodbav:"id"`
+	VideoID   string       `json:"video_id" dynamodbav:"video_id"`
+	UserID    string       `json:"user_id" dynamodbav:"user_id"`
+	RawS3Key  string       `json:"raw_s3_key" dynamodbav:"raw_s3_key"`
+	UploadID  string       `json:"upload_id" dynamodbav:"upload_id"`
+	Length    int64        `json:"length" dynamodbav:"length"`
+	Offset    int64        `json:"offset" dynamodbav:"offset"`
+	TailSize  int64        `json:"tail_size" dynamodbav:"tail_size"`
+	Parts     []UploadPart `json:"parts" dynamodbav:"parts"`
+	CreatedAt time.Time    `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" dynamodbav:"updated_at"`
+	ExpiresAt int64        `json:"expires_at" dynamodbav:"expires_at"`
+	// HashState is the SHA-256 state over the flushed parts, so the content
+	// hash carries over from one chunk request to the next
+	HashState []byte `json:"-" dynamodbav:"hash_state,omitempty"`
+	Version   int64  `json:"-" dynamodbav:"version"`
+}
+
+func NewUploadSession(videoID, userID, rawS3Key, uploadID string, length int64) *UploadSession {
+	now := time.Now()
+	return &UploadSession{
+		ID:        uuid.NewString(),
+		VideoID:   videoID,
+		UserID:    userID,
+		RawS3Key:  rawS3Key,
+		UploadID:  uploadID,
+		Length:    length,
+		Parts:     []UploadPart{},
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(UploadSessionTTL).Unix(),
+	}
+}
+
+func (s *UploadSession) TailKey() string {
+	return "uploads/" + s.ID + "/tail"
+}
+
+// AddPart records a flushed part. The tail it was built from is consumed.
+func (s *UploadSession) AddPart(part UploadPart) {
+	s.Parts = append(s.Parts, part)
+	s.Offset = s.FlushedBytes()
+	s.TailSize = 0
+	s.UpdatedAt = time.Now()
+}
+
+// SetTail records the bytes kept in the tail object after the flushed parts.
+func (s *UploadSession) SetTail(size int64) {
+	s.TailSize = size
+	s.Offset = s.FlushedBytes() + size
+	s.UpdatedAt = time.Now()
+}
+
+func (s *UploadSession) IsComplete() bool {
+	return s.Offset == s.Length
+}
+
+func (s *UploadSession) NextPartNumber() int32 {
+	return int32(len(s.Parts) + 1)
+}
+
+// FlushedBytes is the number of bytes already stored as multipart parts.
+func (s *UploadSession) FlushedBytes() int64 {
+	var total int64
+	for _, part := range s.Parts {
+		total += part.Size
+	}
+	return total
+}
+
+// HashPart adds a part to the content hash before it is flushed. A session
+// that already flushed parts without a hash state is not hashed, so its
+// ContentHash stays empty.
+func (s *UploadSession) HashPart(data []byte) error {
+	hash := sha256.New()
+	if s.HashState != nil {
+		if err := hash.(encoding.BinaryUnmarshaler).UnmarshalBinary(s.HashState); err != nil {
+			return err
+		}
+	} else if len(s.Parts) > 0 {
+		return nil
+	}
+
+	hash.Write(data)
+	state, err := hash.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return err
+	}
+	s.HashState = state
+	return nil
+}
+
+// ContentHash is the hex SHA-256 of the flushed parts, or empty if the
+// session has none.
+func (s *UploadSession) ContentHash() string {
+	if s.HashState == nil {
+		return ""
+	}
+
+	hash := sha256.New()
+	if err := hash.(encoding.BinaryUnmarshaler).UnmarshalBinary(s.HashState); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,72 @@
+package entities
+
+import "testing"
+
+func TestNewUploadSession(t *testing.T) {
+	session := NewUploadSession("video-123", "user-123", "raw/user-123/video-123/test.mp4", "upload-1", 1000)
+
+	if session.ID == "" {
+		t.Error("expected session ID to be generated")
+	}
+
+	if session.Offset != 0 {
+		t.Errorf("expected Offset 0, got %d", session.Offset)
+	}
+
+	if session.ExpiresAt <= session.CreatedAt.Unix() {
+		t.Error("expected ExpiresAt to be after CreatedAt")
+	}
+
+	if session.NextPartNumber() != 1 {
+		t.Errorf("expected first part number 1, got %d", session.NextPartNumber())
+	}
+}
+
+func TestUploadSession_Offsets(t *testing.T) {
+	session := NewUploadSession("video-123", "user-123", "raw/key", "upload-1", 1000)
+
+	session.SetTail(300)
+	if session.Offset != 300 {
+		t.Errorf("expected Offset 300 after tail, got %d", session.Offset)
+	}
+
+	session.AddPart(UploadPart{PartNumber: 1, ETag: "etag-1", Size: 600})
+	if session.Offset != 600 || session.TailSize != 0 {
+		t.Errorf("expected Offset 600 and empty tail after part, got %d and %d", session.Offset, session.TailSize)
+	}
+
+	session.SetTail(400)
+	if !session.IsComplete() {
+		t.Errorf("expected session to be complete at offset %d", session.Offset)
+	}
+
+	if session.NextPartNumber() != 2 {
+		t.Errorf("expected next part number 2, got %d", session.NextPartNumber())
+	}
+}
+
+func TestUploadSession_ContentHash(t *testing.T) {
+	session := NewUploadSession("video-123", "user-123", "raw/key", "upload-1", 11)
+
+	for _, part := range []string{"hello", " world"} {
+		if err := session.HashPart([]byte(part)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		session.AddPart(UploadPart{PartNumber: session.NextPartNumber(), Size: int64(len(part))})
+	}
+
+	// sha256("hello world")
+	expected := "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9"
+	if hash := session.ContentHash(); hash != expected {
+		t.Errorf("expected hash %s, got %s", expected, hash)
+	}
+
+	legacy := NewUploadSession("video-123", "user-123", "raw/key", "upload-1", 11)
+	legacy.AddPart(UploadPart{PartNumber: 1, Size: 5})
+	if err := legacy.HashPart([]byte(" world")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if hash := legacy.ContentHash(); hash != "" {
+		t.Errorf("expected no hash for parts flushed before hashing, got %s", hash)
+	}
+}
@@ -0,0 +1,68 @@
+package entities
+
+import "time"
+
+type Quota string
+
+const (
+	QuotaStorage        Quota = "storage"
+	QuotaVideos         Quota = "videos"
+	QuotaUploadsPerHour Quota = "uploads_per_hour"
+)
+
+// UsageLimits are the per-user quotas. A zero limit is not enforced.
+type UsageLimits struct {
+	MaxStoredBytes    int64
+	MaxVideos         int
+	MaxUploadsPerHour int
+}
+
+// Usage is what a user has stored: the raw uploads and processed outputs of
+// every video they have, whatever its status, plus their uploads in the
+// current hour.
+type Usage struct {
+	UserID      string `json:"user_id" dynamodbav:"user_id"`
+	StoredBytes int64  `json:"stored_bytes" dynamodbav:"stored_bytes"`
+	VideoCount  int    `json:"video_count" dynamodbav:"video_count"`
+	// UploadWindow is the start of the hour WindowUploads were counted in,
+	// in Unix seconds
+	UploadWindow  int64     `json:"upload_window" dynamodbav:"upload_window"`
+	WindowUploads int       `json:"window_uploads" dynamodbav:"window_uploads"`
+	UpdatedAt     time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// UploadWindowStart is the start of the hour uploads at now are counted in.
+func UploadWindowStart(now time.Time) int64 {
+	return now.Truncate(time.Hour).Unix()
+}
+
+// UploadsInWindow is how many uploads count against the hourly limit at now.
+func (u *Usage) UploadsInWindow(now time.Time) int {
+	if u.UploadWindow != UploadWindowStart(now) {
+		return 0
+	}
+	return u.WindowUploads
+}
+
+// Exceeded returns the quota that one more video of size bytes would
+// exceed, or an empty Quota if it fits.
+func (u *Usage) Exceeded(limits UsageLimits, bytes int64, now time.Time) Quota {
+	switch {
+	case limits.MaxUploadsPerHour > 0 && u.UploadsInWindow(now) >= limits.MaxUploadsPerHour:
+		return QuotaUploadsPerHour
+	case limits.MaxVideos > 0 && u.VideoCount >= limits.MaxVideos:
+		return QuotaVideos
+	case limits.MaxStoredBytes > 0 && u.StoredBytes+bytes > limits.MaxStoredBytes:
+		return QuotaStorage
+	}
+	return ""
+}
+
+// RemainingBytes is how much more the user may store, or -1 without a
+// storage limit.
+func (u *Usage) RemainingBytes(limits UsageLimits) int64 {
+	if limits.MaxStoredBytes <= 0 {
+		return -1
+	}
+	return max(limits.MaxStoredBytes-u.StoredBytes, 0)
+}
@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsage_Exceeded(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC)
+	limits := UsageLimits{MaxStoredBytes: 1000, MaxVideos: 3, MaxUploadsPerHour: 2}
+
+	tests := []struct {
+		name     string
+		usage    Usage
+		limits   UsageLimits
+		bytes    int64
+		expected Quota
+	}{
+		{
+			name:   "should fit within every quota",
+			usage:  Usage{StoredBytes: 500, VideoCount: 2, UploadWindow: UploadWindowStart(now), WindowUploads: 1},
+			limits: limits,
+			bytes:  500,
+		},
+		{
+			name:     "should exceed storage",
+			usage:    Usage{StoredBytes: 600},
+			limits:   limits,
+			bytes:    500,
+			expected: QuotaStorage,
+		},
+		{
+			name:     "should exceed the number of videos",
+			usage:    Usage{VideoCount: 3},
+			limits:   limits,
+			bytes:    1,
+			expected: QuotaVideos,
+		},
+		{
+			name:     "should exceed uploads in the current hour",
+			usage:    Usage{UploadWindow: UploadWindowStart(now), WindowUploads: 2},
+			limits:   limits,
+			bytes:    1,
+			expected: QuotaUploadsPerHour,
+		},
+		{
+			name:   "should not count uploads of a previous hour",
+			usage:  Usage{UploadWindow: UploadWindowStart(now.Add(-time.Hour)), WindowUploads: 2},
+			limits: limits,
+			bytes:  1,
+		},
+		{
+			name:   "should not enforce zero limits",
+			usage:  Usage{StoredBytes: 1 << 40, VideoCount: 1000, UploadWindow: UploadWindowStart(now), WindowUploads: 1000},
+			limits: UsageLimits{},
+			bytes:  1 << 30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.usage.Exceeded(tt.limits, tt.bytes, now); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUsage_RemainingBytes(t *testing.T) {
+	usage := Usage{StoredBytes: 800}
+
+	if got := usage.RemainingBytes(UsageLimits{MaxStoredBytes: 1000}); got != 200 {
+		t.Errorf("expected 200 bytes remaining, got %d", got)
+	}
+	if got := usage.RemainingBytes(UsageLimits{MaxStoredBytes: 500}); got != 0 {
+		t.Errorf("expected nothing remaining over the limit, got %d", got)
+	}
+	if got := usage.RemainingBytes(UsageLimits{}); got != -1 {
+		t.Errorf("expected -1 without a limit, got %d", got)
+	}
+}
@@ -13,21 +13,48 @@ const (
 	VideoStatusProcessing VideoStatus = "processing"
 	VideoStatusCompleted  VideoStatus = "completed"
 	VideoStatusFailed     VideoStatus = "failed"
+	VideoStatusCancelled  VideoStatus = "cancelled"
+	VideoStatusExpired    VideoStatus = "expired"
 )
 
+// IsValid reports whether s is a known status.
+func (s VideoStatus) IsValid() bool {
+	switch s {
+	case VideoStatusPending, VideoStatusProcessing, VideoStatusCompleted, VideoStatusFailed, VideoStatusCancelled, VideoStatusExpired:
+		return true
+	}
+	return false
+}
+
 type Video struct {
-	ID              string      `json:"id" dynamodbav:"id"`
-	UserID          string      `json:"user_id" dynamodbav:"user_id"`
-	UserEmail       string      `json:"user_email" dynamodbav:"user_email"`
-	OriginalName    string      `json:"original_name" dynamodbav:"original_name"`
-	RawS3Key        string      `json:"raw_s3_key" dynamodbav:"raw_s3_key"`
-	ProcessedS3Key  string      `json:"processed_s3_key,omitempty" dynamodbav:"processed_s3_key"`
-	Status          VideoStatus `json:"status" dynamodbav:"status"`
-	ProgressPercent int         `json:"progress_percent" dynamodbav:"progress_percent"`
-	ErrorMessage    string      `json:"error_message,omitempty" dynamodbav:"error_message"`
-	FileSize        int64       `json:"file_size" dynamodbav:"file_size"`
-	CreatedAt       time.Time   `json:"created_at" dynamodbav:"created_at"`
-	UpdatedAt       time.Time   `json:"updated_at" dynamodbav:"updated_at"`
+	ID                 string            `json:"id" dynamodbav:"id"`
+	UserID             string            `json:"user_id" dynamodbav:"user_id"`
+	UserEmail          string            `json:"user_email" dynamodbav:"user_email"`
+	OriginalName       string            `json:"original_name" dynamodbav:"original_name"`
+	RawS3Key           string            `json:"raw_s3_key" dynamodbav:"raw_s3_key"`
+	ProcessedS3Key     string            `json:"processed_s3_key,omitempty" dynamodbav:"processed_s3_key"`
+	PreviewsS3Prefix   string            `json:"previews_s3_prefix,omitempty" dynamodbav:"previews_s3_prefix"`
+	HLSS3Prefix        string            `json:"hls_s3_prefix,omitempty" dynamodbav:"hls_s3_prefix"`
+	FramesS3Prefix     string            `json:"frames_s3_prefix,omitempty" dynamodbav:"frames_s3_prefix,omitempty"`
+	Status             VideoStatus       `json:"status" dynamodbav:"status"`
+	ProgressPercent    int               `json:"progress_percent" dynamodbav:"progress_percent"`
+	ErrorMessage       string            `json:"error_message,omitempty" dynamodbav:"error_message"`
+	FileSize           int64             `json:"file_size" dynamodbav:"file_size"`
+	OutputBytes        int64             `json:"output_bytes" dynamodbav:"output_bytes"`
+	AwaitingUpload     bool              `json:"awaiting_upload" dynamodbav:"awaiting_upload"`
+	UploadSessionID    string            `json:"upload_session_id,omitempty" dynamodbav:"upload_session_id,omitempty"`
+	ContentHash        string            `json:"content_hash,omitempty" dynamodbav:"content_hash,omitempty"`
+	DuplicateOf        string            `json:"duplicate_of,omitempty" dynamodbav:"duplicate_of,omitempty"`
+	ExtractionOptions  ExtractionOptions `json:"extraction_options" dynamodbav:"extraction_options"`
+	Metadata           *VideoMetadata    `json:"metadata,omitempty" dynamodbav:"metadata,omitempty"`
+	CompletedAt        *time.Time        `json:"completed_at,omitempty" dynamodbav:"completed_at,omitempty"`
+	Pinned             bool              `json:"pinned" dynamodbav:"pinned"`
+	RawDeletedAt       *time.Time        `json:"raw_deleted_at,omitempty" dynamodbav:"raw_deleted_at,omitempty"`
+	ExpirationReason   string            `json:"expiration_reason,omitempty" dynamodbav:"expiration_reason,omitempty"`
+	ProcessingAttempts int               `json:"processing_attempts" dynamodbav:"processing_attempts"`
+	CreatedAt          time.Time         `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at" dynamodbav:"updated_at"`
+	Version            int64             `json:"version" dynamodbav:"version"`
 }
 
 func NewVideo(userID, userEmail, originalName, rawS3Key string, fileSize int64) *Video {
@@ -43,19 +70,70 @@ func NewVideo(userID, userEmail, originalName, rawS3Key string, fileSize int64)
 		FileSize:        fileSize,
 		CreatedAt:       now,
 		UpdatedAt:       now,
+
+		ProcessingAttempts: 1,
 	}
 }
 
+// ConfirmUpload records that the raw object was uploaded directly to storage.
+func (v *Video) ConfirmUpload(fileSize int64) {
+	v.AwaitingUpload = false
+	v.FileSize = fileSize
+	v.UpdatedAt = time.Now()
+}
+
+// IsTerminal reports whether processing of the video is over.
+func (v *Video) IsTerminal() bool {
+	return v.Status == VideoStatusCompleted || v.Status == VideoStatusFailed || v.Status == VideoStatusCancelled || v.Status == VideoStatusExpired
+}
+
 func (v *Video) UpdateProgress(percent int, status VideoStatus) {
 	v.ProgressPercent = percent
 	v.Status = status
 	v.UpdatedAt = time.Now()
 }
 
-func (v *Video) MarkAsCompleted(processedS3Key string) {
+func (v *Video) MarkAsCompleted(processedS3Key string, outputBytes int64) {
+	now := time.Now()
 	v.ProcessedS3Key = processedS3Key
+	v.OutputBytes = outputBytes
+	v.ErrorMessage = ""
 	v.Status = VideoStatusCompleted
 	v.ProgressPercent = 100
+	v.CompletedAt = &now
+	v.UpdatedAt = now
+}
+
+// MarkForRetry puts the video back in line after a failed attempt.
+func (v *Video) MarkForRetry(errorMessage string) {
+	v.Status = VideoStatusPending
+	v.ProgressPercent = 0
+	v.ErrorMessage = errorMessage
+	v.UpdatedAt = time.Now()
+}
+
+// Cancel stops the video at the user's request.
+func (v *Video) Cancel() {
+	v.Status = VideoStatusCancelled
+	v.ErrorMessage = ""
+	v.UpdatedAt = time.Now()
+}
+
+// Reprocess queues a finished video again from its raw upload.
+func (v *Video) Reprocess(options ExtractionOptions) {
+	v.ExtractionOptions = options
+	v.Status = VideoStatusPending
+	v.ProgressPercent = 0
+	v.ErrorMessage = ""
+	v.ProcessedS3Key = ""
+	v.PreviewsS3Prefix = ""
+	v.HLSS3Prefix = ""
+	v.FramesS3Prefix = ""
+	v.OutputBytes = 0
+	v.CompletedAt = nil
+	v.ExpirationReason = ""
+	v.DuplicateOf = ""
+	v.ProcessingAttempts = max(v.ProcessingAttempts, 1) + 1
 	v.UpdatedAt = time.Now()
 }
 
@@ -64,3 +142,64 @@ func (v *Video) MarkAsFailed(errorMessage string) {
 	v.ErrorMessage = errorMessage
 	v.UpdatedAt = time.Now()
 }
+
+// CanReuseOutputsOf reports whether source has the same content and options
+// and was already processed.
+func (v *Video) CanReuseOutputsOf(source *Video) bool {
+	return source.ID != v.ID &&
+		source.Status == VideoStatusCompleted &&
+		source.ProcessedS3Key != "" &&
+		v.ContentHash != "" &&
+		source.ContentHash == v.ContentHash &&
+		source.ExtractionOptions.WithDefaults() == v.ExtractionOptions.WithDefaults()
+}
+
+// RetentionStart is when the retention periods of the video began.
+func (v *Video) RetentionStart() time.Time {
+	if v.CompletedAt != nil {
+		return *v.CompletedAt
+	}
+	return v.UpdatedAt
+}
+
+// StoredBytes is what the video counts against its owner's storage quota.
+func (v *Video) StoredBytes() int64 {
+	if v.RawDeletedAt != nil {
+		return v.OutputBytes
+	}
+	return v.FileSize + v.OutputBytes
+}
+
+// SetPinned pins or unpins the video's processed outputs.
+func (v *Video) SetPinned(pinned bool) {
+	v.recordRetentionStart()
+	v.Pinned = pinned
+	v.UpdatedAt = time.Now()
+}
+
+// DeleteRaw records that retention deleted the raw upload.
+func (v *Video) DeleteRaw(now time.Time) {
+	v.recordRetentionStart()
+	v.RawDeletedAt = &now
+	v.UpdatedAt = now
+}
+
+// Expire records that retention deleted the processed outputs.
+func (v *Video) Expire(reason string, now time.Time) {
+	v.recordRetentionStart()
+	v.Status = VideoStatusExpired
+	v.ExpirationReason = reason
+	v.ProcessedS3Key = ""
+	v.PreviewsS3Prefix = ""
+	v.HLSS3Prefix = ""
+	v.FramesS3Prefix = ""
+	v.OutputBytes = 0
+	v.UpdatedAt = now
+}
+
+func (v *Video) recordRetentionStart() {
+	if v.CompletedAt == nil && v.Status == VideoStatusCompleted {
+		completedAt := v.UpdatedAt
+		v.CompletedAt = &completedAt
+	}
+}
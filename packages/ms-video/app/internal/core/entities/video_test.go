@@ -38,6 +38,10 @@ func TestNewVideo(t *testing.T) {
 		t.Errorf("expected FileSize %d, got %d", fileSize, video.FileSize)
 	}
 
+	if video.ProcessingAttempts != 1 {
+		t.Errorf("expected ProcessingAttempts 1, got %d", video.ProcessingAttempts)
+	}
+
 	if video.Status != VideoStatusPending {
 		t.Errorf("expected Status '%s', got '%s'", VideoStatusPending, video.Status)
 	}
@@ -123,7 +127,7 @@ func TestVideo_MarkAsCompleted(t *testing.T) {
 	time.Sleep(10 * time.Millisecond)
 
 	processedS3Key := "processed/user-123/test.zip"
-	video.MarkAsCompleted(processedS3Key)
+	video.MarkAsCompleted(processedS3Key, 2048)
 
 	if video.ProcessedS3Key != processedS3Key {
 		t.Errorf("expected ProcessedS3Key '%s', got '%s'", processedS3Key, video.ProcessedS3Key)
@@ -140,6 +144,10 @@ func TestVideo_MarkAsCompleted(t *testing.T) {
 	if !video.UpdatedAt.After(initialUpdatedAt) {
 		t.Error("expected UpdatedAt to be updated")
 	}
+
+	if video.CompletedAt == nil || !video.CompletedAt.Equal(video.UpdatedAt) {
+		t.Errorf("expected CompletedAt to be recorded, got %v", video.CompletedAt)
+	}
 }
 
 func TestVideo_MarkAsFailed(t *testing.T) {
@@ -165,6 +173,120 @@ func TestVideo_MarkAsFailed(t *testing.T) {
 	}
 }
 
+func TestVideo_MarkForRetry(t *testing.T) {
+	video := NewVideo("user-123", "user@example.com", "test.mp4", "raw/test.mp4", 1024)
+	video.UpdateProgress(60, VideoStatusProcessing)
+
+	video.MarkForRetry("attempt 1 failed to download raw video: timeout")
+
+	if video.Status != VideoStatusPending {
+		t.Errorf("expected Status '%s', got '%s'", VideoStatusPending, video.Status)
+	}
+
+	if video.ProgressPercent != 0 {
+		t.Errorf("expected ProgressPercent to be reset, got %d", video.ProgressPercent)
+	}
+
+	if video.IsTerminal() {
+		t.Error("expected video waiting for a retry not to be terminal")
+	}
+
+	video.MarkAsCompleted("processed/test.zip", 0)
+
+	if video.ErrorMessage != "" {
+		t.Errorf("expected completion to clear the retry message, got '%s'", video.ErrorMessage)
+	}
+}
+
+func TestVideo_Cancel(t *testing.T) {
+	video := NewVideo("user-123", "user@example.com", "test.mp4", "raw/test.mp4", 1024)
+	video.MarkForRetry("attempt 1 failed to download raw video: timeout")
+
+	video.Cancel()
+
+	if video.Status != VideoStatusCancelled {
+		t.Errorf("expected Status '%s', got '%s'", VideoStatusCancelled, video.Status)
+	}
+
+	if video.ErrorMessage != "" {
+		t.Errorf("expected ErrorMessage to be cleared, got '%s'", video.ErrorMessage)
+	}
+
+	if !video.IsTerminal() {
+		t.Error("expected cancelled video to be terminal")
+	}
+}
+
+func TestVideo_Reprocess(t *testing.T) {
+	video := NewVideo("user-123", "user@example.com", "test.mp4", "raw/test.mp4", 1024)
+	video.MarkAsCompleted("processed/test.zip", 0)
+	video.HLSS3Prefix = "hls/user-123/video-123/"
+	video.FramesS3Prefix = "processed/user-123/video-123/frames/"
+	video.DuplicateOf = "video-456"
+
+	options := ExtractionOptions{Mode: ExtractionModeKeyframes}
+	video.Reprocess(options)
+
+	if video.Status != VideoStatusPending {
+		t.Errorf("expected Status '%s', got '%s'", VideoStatusPending, video.Status)
+	}
+
+	if video.ProgressPercent != 0 {
+		t.Errorf("expected ProgressPercent to be reset, got %d", video.ProgressPercent)
+	}
+
+	if video.ProcessedS3Key != "" || video.HLSS3Prefix != "" || video.FramesS3Prefix != "" {
+		t.Errorf("expected previous outputs to be cleared, got '%s', '%s' and '%s'", video.ProcessedS3Key, video.HLSS3Prefix, video.FramesS3Prefix)
+	}
+
+	if video.DuplicateOf != "" {
+		t.Errorf("expected a reprocessed duplicate to be processed itself, got '%s'", video.DuplicateOf)
+	}
+
+	if video.ExtractionOptions != options {
+		t.Errorf("expected ExtractionOptions %+v, got %+v", options, video.ExtractionOptions)
+	}
+
+	if video.RawS3Key != "raw/test.mp4" {
+		t.Errorf("expected RawS3Key to be kept, got '%s'", video.RawS3Key)
+	}
+
+	if video.ProcessingAttempts != 2 {
+		t.Errorf("expected ProcessingAttempts 2, got %d", video.ProcessingAttempts)
+	}
+
+	// Videos saved before attempts were counted start from one
+	legacy := &Video{Status: VideoStatusFailed}
+	legacy.Reprocess(ExtractionOptions{})
+
+	if legacy.ProcessingAttempts != 2 {
+		t.Errorf("expected ProcessingAttempts 2 for a video without a count, got %d", legacy.ProcessingAttempts)
+	}
+}
+
+func TestVideo_IsTerminal(t *testing.T) {
+	tests := []struct {
+		status   VideoStatus
+		expected bool
+	}{
+		{VideoStatusPending, false},
+		{VideoStatusProcessing, false},
+		{VideoStatusCompleted, true},
+		{VideoStatusFailed, true},
+		{VideoStatusCancelled, true},
+		{VideoStatusExpired, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			video := &Video{Status: tt.status}
+			if video.IsTerminal() != tt.expected {
+				t.Errorf("expected IsTerminal %v for status '%s'", tt.expected, tt.status)
+			}
+		})
+	}
+}
+
 func TestVideoStatus_Constants(t *testing.T) {
 	if VideoStatusPending != "pending" {
 		t.Errorf("expected VideoStatusPending to be 'pending', got '%s'", VideoStatusPending)
@@ -181,4 +303,132 @@ func TestVideoStatus_Constants(t *testing.T) {
 	if VideoStatusFailed != "failed" {
 		t.Errorf("expected VideoStatusFailed to be 'failed', got '%s'", VideoStatusFailed)
 	}
+
+	if VideoStatusCancelled != "cancelled" {
+		t.Errorf("expected VideoStatusCancelled to be 'cancelled', got '%s'", VideoStatusCancelled)
+	}
+}
+
+func TestVideoStatus_IsValid(t *testing.T) {
+	tests := []struct {
+		status   VideoStatus
+		expected bool
+	}{
+		{VideoStatusPending, true},
+		{VideoStatusProcessing, true},
+		{VideoStatusCompleted, true},
+		{VideoStatusFailed, true},
+		{VideoStatusCancelled, true},
+		{VideoStatusExpired, true},
+		{"", false},
+		{"done", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if tt.status.IsValid() != tt.expected {
+				t.Errorf("expected IsValid %v for status '%s'", tt.expected, tt.status)
+			}
+		})
+	}
+}
+
+func TestVideo_CanReuseOutputsOf(t *testing.T) {
+	newSource := func() *Video {
+		return &Video{
+			ID:                "source",
+			Status:            VideoStatusCompleted,
+			ProcessedS3Key:    "processed/user-123/source.zip",
+			ContentHash:       "abc",
+			ExtractionOptions: DefaultExtractionOptions(),
+		}
+	}
+	video := &Video{ID: "video", ContentHash: "abc"}
+
+	tests := []struct {
+		name     string
+		modify   func(source *Video)
+		expected bool
+	}{
+		{
+			name:     "should reuse a completed video with the same content and options",
+			modify:   func(source *Video) {},
+			expected: true,
+		},
+		{
+			name:   "should not reuse a video that is not completed",
+			modify: func(source *Video) { source.Status = VideoStatusProcessing },
+		},
+		{
+			name:   "should not reuse different content",
+			modify: func(source *Video) { source.ContentHash = "def" },
+		},
+		{
+			name:   "should not reuse other extraction options",
+			modify: func(source *Video) { source.ExtractionOptions.FPS = 5 },
+		},
+		{
+			name:   "should not reuse itself",
+			modify: func(source *Video) { source.ID = "video" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := newSource()
+			tt.modify(source)
+			if got := video.CanReuseOutputsOf(source); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if (&Video{ID: "video"}).CanReuseOutputsOf(&Video{ID: "source", Status: VideoStatusCompleted, ProcessedS3Key: "x"}) {
+		t.Error("expected videos without a hash not to match")
+	}
+}
+
+func TestVideo_Expire(t *testing.T) {
+	video := NewVideo("user-123", "user@example.com", "test.mp4", "raw/test.mp4", 1024)
+	video.MarkAsCompleted("processed/test.zip", 2048)
+	video.PreviewsS3Prefix = "previews/user-123/video-123/"
+	completedAt := *video.CompletedAt
+
+	video.Expire("outputs expired", completedAt.Add(time.Hour))
+
+	if video.Status != VideoStatusExpired || video.ExpirationReason != "outputs expired" {
+		t.Errorf("expected video expired with its reason, got '%s' and '%s'", video.Status, video.ExpirationReason)
+	}
+
+	if video.ProcessedS3Key != "" || video.PreviewsS3Prefix != "" || video.OutputBytes != 0 {
+		t.Errorf("expected outputs to be cleared, got %+v", video)
+	}
+
+	if video.StoredBytes() != 1024 {
+		t.Errorf("expected only the raw upload to be stored, got %d", video.StoredBytes())
+	}
+
+	if !video.RetentionStart().Equal(completedAt) {
+		t.Errorf("expected retention to still start at %v, got %v", completedAt, video.RetentionStart())
+	}
+}
+
+func TestVideo_DeleteRaw(t *testing.T) {
+	// Completed before CompletedAt was recorded
+	updatedAt := time.Now().Add(-48 * time.Hour)
+	video := &Video{Status: VideoStatusCompleted, FileSize: 1024, OutputBytes: 2048, UpdatedAt: updatedAt}
+
+	video.DeleteRaw(time.Now())
+
+	if video.RawDeletedAt == nil {
+		t.Fatal("expected RawDeletedAt to be recorded")
+	}
+
+	if video.StoredBytes() != 2048 {
+		t.Errorf("expected only the outputs to be stored, got %d", video.StoredBytes())
+	}
+
+	if !video.RetentionStart().Equal(updatedAt) {
+		t.Errorf("expected retention to keep starting at %v, got %v", updatedAt, video.RetentionStart())
+	}
 }
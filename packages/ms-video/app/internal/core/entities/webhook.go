@@ -0,0 +1,153 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventVideoCompleted WebhookEvent = "video.completed"
+	WebhookEventVideoFailed    WebhookEvent = "video.failed"
+)
+
+func (e WebhookEvent) IsValid() bool {
+	return e == WebhookEventVideoCompleted || e == WebhookEventVideoFailed
+}
+
+// Webhook subscribes a user's endpoint to events of their videos. Payloads
+// are signed with Secret so the receiver can tell they come from us.
+type Webhook struct {
+	ID        string         `json:"id" dynamodbav:"id"`
+	UserID    string         `json:"user_id" dynamodbav:"user_id"`
+	URL       string         `json:"url" dynamodbav:"url"`
+	Secret    string         `json:"secret" dynamodbav:"secret"`
+	Events    []WebhookEvent `json:"events" dynamodbav:"events"`
+	CreatedAt time.Time      `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+func NewWebhook(userID, targetURL, secret string, events []WebhookEvent) *Webhook {
+	now := time.Now()
+	return &Webhook{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		URL:       targetURL,
+		Secret:    secret,
+		Events:    events,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate checks the target and the events of the webhook.
+func (w *Webhook) Validate() error {
+	target, err := url.Parse(w.URL)
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	if target.User != nil {
+		return errors.New("url must not contain credentials")
+	}
+
+	if len(w.Events) == 0 {
+		return errors.New("at least one event is required")
+	}
+	for _, event := range w.Events {
+		if !event.IsValid() {
+			return fmt.Errorf("unknown event %q", event)
+		}
+	}
+
+	return nil
+}
+
+// Subscribes reports whether the webhook wants event.
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDeliveryTTL is how long the delivery log is kept.
+const WebhookDeliveryTTL = 30 * 24 * time.Hour
+
+// WebhookDelivery is one event sent to one webhook, with the outcome of its
+// latest attempt. The payload is fixed when the event happens, so retries
+// send the same body.
+type WebhookDelivery struct {
+	ID             string                `json:"id" dynamodbav:"id"`
+	WebhookID      string                `json:"webhook_id" dynamodbav:"webhook_id"`
+	UserID         string                `json:"user_id" dynamodbav:"user_id"`
+	VideoID        string                `json:"video_id" dynamodbav:"video_id"`
+	Event          WebhookEvent          `json:"event" dynamodbav:"event"`
+	Payload        string                `json:"payload" dynamodbav:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" dynamodbav:"status"`
+	Attempts       int                   `json:"attempts" dynamodbav:"attempts"`
+	ResponseStatus int                   `json:"response_status" dynamodbav:"response_status"`
+	LastError      string                `json:"last_error" dynamodbav:"last_error"`
+	CreatedAt      time.Time             `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" dynamodbav:"updated_at"`
+	ExpiresAt      int64                 `json:"expires_at" dynamodbav:"expires_at"`
+}
+
+// NewWebhookDelivery derives the ID from the webhook, the event and the
+// saved state of the video it is about, so recording the same event twice
+// yields the same delivery.
+func NewWebhookDelivery(webhook *Webhook, video *Video, event WebhookEvent, payload string) *WebhookDelivery {
+	now := time.Now()
+	name := fmt.Sprintf("%s/%s/%s/%d", webhook.ID, video.ID, event, video.Version)
+	return &WebhookDelivery{
+		ID:        uuid.NewSHA1(uuid.NameSpaceURL, []byte(name)).String(),
+		WebhookID: webhook.ID,
+		UserID:    webhook.UserID,
+		VideoID:   video.ID,
+		Event:     event,
+		Payload:   payload,
+		Status:    WebhookDeliveryPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(WebhookDeliveryTTL).Unix(),
+	}
+}
+
+// IsFinished reports whether the delivery needs no more attempts.
+func (d *WebhookDelivery) IsFinished() bool {
+	return d.Status != WebhookDeliveryPending
+}
+
+// RecordAttempt records the outcome of an attempt. responseStatus is 0 when
+// no response was received. A failed attempt leaves the delivery pending
+// unless final is set.
+func (d *WebhookDelivery) RecordAttempt(responseStatus int, attemptErr error, final bool) {
+	d.Attempts++
+	d.ResponseStatus = responseStatus
+	d.LastError = ""
+	d.UpdatedAt = time.Now()
+
+	switch {
+	case attemptErr == nil:
+		d.Status = WebhookDeliverySucceeded
+	case final:
+		d.Status = WebhookDeliveryFailed
+		d.LastError = attemptErr.Error()
+	default:
+		d.LastError = attemptErr.Error()
+	}
+}
@@ -2,18 +2,25 @@ package mocks
 
 import (
 	"context"
+	"io"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
 )
 
 // MockVideoRepository is a mock implementation of VideoRepository interface
 type MockVideoRepository struct {
-	SaveFunc       func(ctx context.Context, video *entities.Video) error
-	FindByIDFunc   func(ctx context.Context, videoID string) (*entities.Video, error)
-	FindByUserIDFunc func(ctx context.Context, userID string) ([]*entities.Video, error)
-	UpdateFunc     func(ctx context.Context, video *entities.Video) error
+	SaveFunc              func(ctx context.Context, video *entities.Video) error
+	FindByIDFunc          func(ctx context.Context, videoID string) (*entities.Video, error)
+	FindByUserIDFunc      func(ctx context.Context, userID string, query ports.VideoListQuery) (*ports.VideoPage, error)
+	FindByContentHashFunc func(ctx context.Context, contentHash string, limit int) ([]*entities.Video, error)
+	FindProcessedFunc     func(ctx context.Context, cursor string, limit int) (*ports.VideoPage, error)
+	UpdateFunc            func(ctx context.Context, video *entities.Video) error
+	DeleteFunc            func(ctx context.Context, video *entities.Video) error
 }
 
 func (m *MockVideoRepository) Save(ctx context.Context, video *entities.Video) error {
@@ -30,13 +37,27 @@ func (m *MockVideoRepository) FindByID(ctx context.Context, videoID string) (*en
 	return nil, nil
 }
 
-func (m *MockVideoRepository) FindByUserID(ctx context.Context, userID string) ([]*entities.Video, error) {
+func (m *MockVideoRepository) FindByUserID(ctx context.Context, userID string, query ports.VideoListQuery) (*ports.VideoPage, error) {
 	if m.FindByUserIDFunc != nil {
-		return m.FindByUserIDFunc(ctx, userID)
+		return m.FindByUserIDFunc(ctx, userID, query)
+	}
+	return &ports.VideoPage{}, nil
+}
+
+func (m *MockVideoRepository) FindByContentHash(ctx context.Context, contentHash string, limit int) ([]*entities.Video, error) {
+	if m.FindByContentHashFunc != nil {
+		return m.FindByContentHashFunc(ctx, contentHash, limit)
 	}
 	return nil, nil
 }
 
+func (m *MockVideoRepository) FindProcessed(ctx context.Context, cursor string, limit int) (*ports.VideoPage, error) {
+	if m.FindProcessedFunc != nil {
+		return m.FindProcessedFunc(ctx, cursor, limit)
+	}
+	return &ports.VideoPage{}, nil
+}
+
 func (m *MockVideoRepository) Update(ctx context.Context, video *entities.Video) error {
 	if m.UpdateFunc != nil {
 		return m.UpdateFunc(ctx, video)
@@ -44,12 +65,30 @@ func (m *MockVideoRepository) Update(ctx context.Context, video *entities.Video)
 	return nil
 }
 
+func (m *MockVideoRepository) Delete(ctx context.Context, video *entities.Video) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, video)
+	}
+	return nil
+}
+
 // MockStorageService is a mock implementation of StorageService interface
 type MockStorageService struct {
-	UploadFunc          func(ctx context.Context, key string, data []byte, contentType string) error
-	DownloadFunc        func(ctx context.Context, key string) ([]byte, error)
-	GetPresignedURLFunc func(ctx context.Context, key string, expirationMinutes int) (string, error)
-	DeleteFunc          func(ctx context.Context, key string) error
+	UploadFunc                  func(ctx context.Context, key string, data []byte, contentType string) error
+	UploadStreamFunc            func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error)
+	DownloadFunc                func(ctx context.Context, key string) ([]byte, error)
+	DownloadStreamFunc          func(ctx context.Context, key string) (io.ReadCloser, error)
+	GetPresignedURLFunc         func(ctx context.Context, key string, expirationMinutes int) (string, error)
+	GetPresignedUploadURLFunc   func(ctx context.Context, key, contentType string, contentLength int64, expirationMinutes int) (string, error)
+	StatFunc                    func(ctx context.Context, key string) (*ports.ObjectInfo, error)
+	CreateMultipartUploadFunc   func(ctx context.Context, key, contentType string) (string, error)
+	UploadPartFunc              func(ctx context.Context, key, uploadID string, partNumber int32, data []byte) (string, error)
+	CompleteMultipartUploadFunc func(ctx context.Context, key, uploadID string, parts []entities.UploadPart) error
+	AbortMultipartUploadFunc    func(ctx context.Context, key, uploadID string) error
+	DeleteFunc                  func(ctx context.Context, key string) error
+	DeletePrefixFunc            func(ctx context.Context, prefix string) error
+	CopyFunc                    func(ctx context.Context, sourceKey, key string) error
+	CopyPrefixFunc              func(ctx context.Context, sourcePrefix, prefix string) error
 }
 
 func (m *MockStorageService) Upload(ctx context.Context, key string, data []byte, contentType string) error {
@@ -59,6 +98,13 @@ func (m *MockStorageService) Upload(ctx context.Context, key string, data []byte
 	return nil
 }
 
+func (m *MockStorageService) UploadStream(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+	if m.UploadStreamFunc != nil {
+		return m.UploadStreamFunc(ctx, key, body, contentType)
+	}
+	return io.Copy(io.Discard, body)
+}
+
 func (m *MockStorageService) Download(ctx context.Context, key string) ([]byte, error) {
 	if m.DownloadFunc != nil {
 		return m.DownloadFunc(ctx, key)
@@ -66,6 +112,13 @@ func (m *MockStorageService) Download(ctx context.Context, key string) ([]byte,
 	return nil, nil
 }
 
+func (m *MockStorageService) DownloadStream(ctx context.Context, key string) (io.ReadCloser, error) {
+	if m.DownloadStreamFunc != nil {
+		return m.DownloadStreamFunc(ctx, key)
+	}
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
 func (m *MockStorageService) GetPresignedURL(ctx context.Context, key string, expirationMinutes int) (string, error) {
 	if m.GetPresignedURLFunc != nil {
 		return m.GetPresignedURLFunc(ctx, key, expirationMinutes)
@@ -73,6 +126,48 @@ func (m *MockStorageService) GetPresignedURL(ctx context.Context, key string, ex
 	return "", nil
 }
 
+func (m *MockStorageService) GetPresignedUploadURL(ctx context.Context, key, contentType string, contentLength int64, expirationMinutes int) (string, error) {
+	if m.GetPresignedUploadURLFunc != nil {
+		return m.GetPresignedUploadURLFunc(ctx, key, contentType, contentLength, expirationMinutes)
+	}
+	return "", nil
+}
+
+func (m *MockStorageService) Stat(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+	if m.StatFunc != nil {
+		return m.StatFunc(ctx, key)
+	}
+	return &ports.ObjectInfo{}, nil
+}
+
+func (m *MockStorageService) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	if m.CreateMultipartUploadFunc != nil {
+		return m.CreateMultipartUploadFunc(ctx, key, contentType)
+	}
+	return "", nil
+}
+
+func (m *MockStorageService) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, data []byte) (string, error) {
+	if m.UploadPartFunc != nil {
+		return m.UploadPartFunc(ctx, key, uploadID, partNumber, data)
+	}
+	return "", nil
+}
+
+func (m *MockStorageService) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []entities.UploadPart) error {
+	if m.CompleteMultipartUploadFunc != nil {
+		return m.CompleteMultipartUploadFunc(ctx, key, uploadID, parts)
+	}
+	return nil
+}
+
+func (m *MockStorageService) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	if m.AbortMultipartUploadFunc != nil {
+		return m.AbortMultipartUploadFunc(ctx, key, uploadID)
+	}
+	return nil
+}
+
 func (m *MockStorageService) Delete(ctx context.Context, key string) error {
 	if m.DeleteFunc != nil {
 		return m.DeleteFunc(ctx, key)
@@ -80,11 +175,70 @@ func (m *MockStorageService) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+func (m *MockStorageService) DeletePrefix(ctx context.Context, prefix string) error {
+	if m.DeletePrefixFunc != nil {
+		return m.DeletePrefixFunc(ctx, prefix)
+	}
+	return nil
+}
+
+func (m *MockStorageService) Copy(ctx context.Context, sourceKey, key string) error {
+	if m.CopyFunc != nil {
+		return m.CopyFunc(ctx, sourceKey, key)
+	}
+	return nil
+}
+
+func (m *MockStorageService) CopyPrefix(ctx context.Context, sourcePrefix, prefix string) error {
+	if m.CopyPrefixFunc != nil {
+		return m.CopyPrefixFunc(ctx, sourcePrefix, prefix)
+	}
+	return nil
+}
+
+// MockUploadSessionRepository is a mock implementation of UploadSessionRepository interface
+type MockUploadSessionRepository struct {
+	SaveFunc     func(ctx context.Context, session *entities.UploadSession) error
+	FindByIDFunc func(ctx context.Context, sessionID string) (*entities.UploadSession, error)
+	UpdateFunc   func(ctx context.Context, session *entities.UploadSession) error
+	DeleteFunc   func(ctx context.Context, sessionID string) error
+}
+
+func (m *MockUploadSessionRepository) Save(ctx context.Context, session *entities.UploadSession) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, session)
+	}
+	return nil
+}
+
+func (m *MockUploadSessionRepository) FindByID(ctx context.Context, sessionID string) (*entities.UploadSession, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, sessionID)
+	}
+	return nil, nil
+}
+
+func (m *MockUploadSessionRepository) Update(ctx context.Context, session *entities.UploadSession) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, session)
+	}
+	return nil
+}
+
+func (m *MockUploadSessionRepository) Delete(ctx context.Context, sessionID string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, sessionID)
+	}
+	return nil
+}
+
 // MockVideoQueue is a mock implementation of VideoQueue interface
 type MockVideoQueue struct {
-	SendFunc   func(ctx context.Context, message dto.VideoProcessMessage) error
-	GetFunc    func(ctx context.Context) ([]types.Message, error)
-	DeleteFunc func(ctx context.Context, message types.Message) error
+	SendFunc             func(ctx context.Context, message dto.VideoProcessMessage) error
+	GetFunc              func(ctx context.Context, maxMessages int, visibilityTimeout time.Duration) ([]types.Message, error)
+	ChangeVisibilityFunc func(ctx context.Context, message types.Message, timeout time.Duration) error
+	DeleteFunc           func(ctx context.Context, message types.Message) error
+	DeadLetterFunc       func(ctx context.Context, message types.Message, reason string) error
 }
 
 func (m *MockVideoQueue) Send(ctx context.Context, message dto.VideoProcessMessage) error {
@@ -94,13 +248,20 @@ func (m *MockVideoQueue) Send(ctx context.Context, message dto.VideoProcessMessa
 	return nil
 }
 
-func (m *MockVideoQueue) Get(ctx context.Context) ([]types.Message, error) {
+func (m *MockVideoQueue) Get(ctx context.Context, maxMessages int, visibilityTimeout time.Duration) ([]types.Message, error) {
 	if m.GetFunc != nil {
-		return m.GetFunc(ctx)
+		return m.GetFunc(ctx, maxMessages, visibilityTimeout)
 	}
 	return nil, nil
 }
 
+func (m *MockVideoQueue) ChangeVisibility(ctx context.Context, message types.Message, timeout time.Duration) error {
+	if m.ChangeVisibilityFunc != nil {
+		return m.ChangeVisibilityFunc(ctx, message, timeout)
+	}
+	return nil
+}
+
 func (m *MockVideoQueue) Delete(ctx context.Context, message types.Message) error {
 	if m.DeleteFunc != nil {
 		return m.DeleteFunc(ctx, message)
@@ -108,6 +269,13 @@ func (m *MockVideoQueue) Delete(ctx context.Context, message types.Message) erro
 	return nil
 }
 
+func (m *MockVideoQueue) DeadLetter(ctx context.Context, message types.Message, reason string) error {
+	if m.DeadLetterFunc != nil {
+		return m.DeadLetterFunc(ctx, message, reason)
+	}
+	return nil
+}
+
 // MockNotificationService is a mock implementation of NotificationService interface
 type MockNotificationService struct {
 	SendVideoProcessedNotificationFunc func(ctx context.Context, email, videoID, originalName string) error
@@ -127,3 +295,193 @@ func (m *MockNotificationService) SendVideoFailedNotification(ctx context.Contex
 	}
 	return nil
 }
+
+// MockVideoProber is a mock implementation of VideoProber interface
+type MockVideoProber struct {
+	ProbeFunc func(ctx context.Context, source string) (*entities.MediaInfo, error)
+}
+
+func (m *MockVideoProber) Probe(ctx context.Context, source string) (*entities.MediaInfo, error) {
+	if m.ProbeFunc != nil {
+		return m.ProbeFunc(ctx, source)
+	}
+	return &entities.MediaInfo{
+		FormatName: "mov,mp4,m4a,3gp,3g2,mj2",
+		Duration:   time.Minute,
+		BitRate:    2000000,
+		Streams: []entities.MediaStream{
+			{Index: 0, CodecType: entities.StreamTypeVideo, CodecName: "h264", Width: 1280, Height: 720, FrameRate: 30},
+			{Index: 1, CodecType: entities.StreamTypeAudio, CodecName: "aac"},
+		},
+	}, nil
+}
+
+// MockVideoEventSubscriber is a mock implementation of VideoEventSubscriber interface
+type MockVideoEventSubscriber struct {
+	SubscribeFunc func(ctx context.Context, userID string) <-chan *entities.Video
+}
+
+func (m *MockVideoEventSubscriber) Subscribe(ctx context.Context, userID string) <-chan *entities.Video {
+	if m.SubscribeFunc != nil {
+		return m.SubscribeFunc(ctx, userID)
+	}
+	updates := make(chan *entities.Video)
+	context.AfterFunc(ctx, func() { close(updates) })
+	return updates
+}
+
+// MockWebhookRepository is a mock implementation of WebhookRepository interface
+type MockWebhookRepository struct {
+	SaveFunc         func(ctx context.Context, webhook *entities.Webhook) error
+	FindByIDFunc     func(ctx context.Context, webhookID string) (*entities.Webhook, error)
+	FindByUserIDFunc func(ctx context.Context, userID string) ([]*entities.Webhook, error)
+	UpdateFunc       func(ctx context.Context, webhook *entities.Webhook) error
+	DeleteFunc       func(ctx context.Context, webhookID string) error
+}
+
+func (m *MockWebhookRepository) Save(ctx context.Context, webhook *entities.Webhook) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, webhook)
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) FindByID(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, webhookID)
+	}
+	return nil, ports.ErrWebhookNotFound
+}
+
+func (m *MockWebhookRepository) FindByUserID(ctx context.Context, userID string) ([]*entities.Webhook, error) {
+	if m.FindByUserIDFunc != nil {
+		return m.FindByUserIDFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockWebhookRepository) Update(ctx context.Context, webhook *entities.Webhook) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, webhook)
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, webhookID string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, webhookID)
+	}
+	return nil
+}
+
+// MockWebhookDeliveryRepository is a mock implementation of WebhookDeliveryRepository interface
+type MockWebhookDeliveryRepository struct {
+	SaveFunc            func(ctx context.Context, delivery *entities.WebhookDelivery) error
+	FindByIDFunc        func(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error)
+	UpdateFunc          func(ctx context.Context, delivery *entities.WebhookDelivery) error
+	FindByWebhookIDFunc func(ctx context.Context, webhookID string, limit int) ([]*entities.WebhookDelivery, error)
+}
+
+func (m *MockWebhookDeliveryRepository) Save(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, delivery)
+	}
+	return nil
+}
+
+func (m *MockWebhookDeliveryRepository) FindByID(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, deliveryID)
+	}
+	return nil, ports.ErrWebhookDeliveryNotFound
+}
+
+func (m *MockWebhookDeliveryRepository) Update(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, delivery)
+	}
+	return nil
+}
+
+func (m *MockWebhookDeliveryRepository) FindByWebhookID(ctx context.Context, webhookID string, limit int) ([]*entities.WebhookDelivery, error) {
+	if m.FindByWebhookIDFunc != nil {
+		return m.FindByWebhookIDFunc(ctx, webhookID, limit)
+	}
+	return nil, nil
+}
+
+// MockWebhookQueue is a mock implementation of WebhookQueue interface
+type MockWebhookQueue struct {
+	SendFunc             func(ctx context.Context, message dto.WebhookDeliveryMessage) error
+	GetFunc              func(ctx context.Context, maxMessages int, visibilityTimeout time.Duration) ([]types.Message, error)
+	ChangeVisibilityFunc func(ctx context.Context, message types.Message, timeout time.Duration) error
+	DeleteFunc           func(ctx context.Context, message types.Message) error
+}
+
+func (m *MockWebhookQueue) Send(ctx context.Context, message dto.WebhookDeliveryMessage) error {
+	if m.SendFunc != nil {
+		return m.SendFunc(ctx, message)
+	}
+	return nil
+}
+
+func (m *MockWebhookQueue) Get(ctx context.Context, maxMessages int, visibilityTimeout time.Duration) ([]types.Message, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, maxMessages, visibilityTimeout)
+	}
+	return nil, nil
+}
+
+func (m *MockWebhookQueue) ChangeVisibility(ctx context.Context, message types.Message, timeout time.Duration) error {
+	if m.ChangeVisibilityFunc != nil {
+		return m.ChangeVisibilityFunc(ctx, message, timeout)
+	}
+	return nil
+}
+
+func (m *MockWebhookQueue) Delete(ctx context.Context, message types.Message) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, message)
+	}
+	return nil
+}
+
+// MockWebhookClient is a mock implementation of WebhookClient interface
+type MockWebhookClient struct {
+	PostFunc func(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
+
+func (m *MockWebhookClient) Post(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	if m.PostFunc != nil {
+		return m.PostFunc(ctx, url, headers, body)
+	}
+	return 200, nil
+}
+
+// MockUsageRepository is a mock implementation of UsageRepository interface
+type MockUsageRepository struct {
+	GetFunc     func(ctx context.Context, userID string) (*entities.Usage, error)
+	ReserveFunc func(ctx context.Context, userID string, bytes int64, limits entities.UsageLimits, now time.Time) error
+	AdjustFunc  func(ctx context.Context, userID string, bytes int64, videos int) error
+}
+
+func (m *MockUsageRepository) Get(ctx context.Context, userID string) (*entities.Usage, error) {
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, userID)
+	}
+	return &entities.Usage{UserID: userID}, nil
+}
+
+func (m *MockUsageRepository) Reserve(ctx context.Context, userID string, bytes int64, limits entities.UsageLimits, now time.Time) error {
+	if m.ReserveFunc != nil {
+		return m.ReserveFunc(ctx, userID, bytes, limits, now)
+	}
+	return nil
+}
+
+func (m *MockUsageRepository) Adjust(ctx context.Context, userID string, bytes int64, videos int) error {
+	if m.AdjustFunc != nil {
+		return m.AdjustFunc(ctx, userID, bytes, videos)
+	}
+	return nil
+}
@@ -2,30 +2,145 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
 )
 
+// VersionConflictError is returned by VideoRepository writes when the stored
+// video is not the version the caller loaded, because it was written since.
+type VersionConflictError struct {
+	VideoID string
+	Version int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("video %s was modified since version %d", e.VideoID, e.Version)
+}
+
+// ErrVideoNotFound is returned by VideoRepository reads for a video that does
+// not exist, or no longer does.
+var ErrVideoNotFound = errors.New("video not found")
+
+// ErrUploadSessionNotFound is returned by UploadSessionRepository.FindByID
+// for a session that does not exist, or no longer does.
+var ErrUploadSessionNotFound = errors.New("upload session not found")
+
+// ErrUploadSessionConflict is returned by UploadSessionRepository.Update when
+// the session was written since it was loaded.
+var ErrUploadSessionConflict = errors.New("upload session was modified concurrently")
+
+// ErrInvalidCursor is returned by VideoRepository.FindByUserID for a cursor
+// that was not issued for the same user and sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type VideoSortField string
+
+const (
+	VideoSortCreatedAt VideoSortField = "created_at"
+	VideoSortUpdatedAt VideoSortField = "updated_at"
+)
+
+// VideoListQuery selects one page of a user's videos.
+type VideoListQuery struct {
+	Status    entities.VideoStatus // only videos in this status, all when empty
+	SortBy    VideoSortField
+	Ascending bool
+	Limit     int
+	// Cursor continues after the page that returned it, empty for the first
+	Cursor string
+}
+
+type VideoPage struct {
+	Videos []*entities.Video
+	// NextCursor is empty on the last page
+	NextCursor string
+}
+
 type VideoRepository interface {
+	// Save creates the video and fails with a VersionConflictError if it
+	// already exists. Save and Update bump Version on success.
 	Save(ctx context.Context, video *entities.Video) error
 	FindByID(ctx context.Context, videoID string) (*entities.Video, error)
-	FindByUserID(ctx context.Context, userID string) ([]*entities.Video, error)
+	FindByUserID(ctx context.Context, userID string, query VideoListQuery) (*VideoPage, error)
+	// FindByContentHash returns up to limit videos of any user whose raw
+	// upload has the hash, newest first.
+	FindByContentHash(ctx context.Context, contentHash string, limit int) ([]*entities.Video, error)
+	// FindProcessed scans the videos of every user for completed and expired
+	// ones. Limit bounds the videos read per page rather than the ones
+	// returned, so a page may come back empty before the last one.
+	FindProcessed(ctx context.Context, cursor string, limit int) (*VideoPage, error)
+	// Update fails with a VersionConflictError unless the stored video is
+	// still at video.Version.
 	Update(ctx context.Context, video *entities.Video) error
+	// Delete removes the video, with the same version check as Update.
+	Delete(ctx context.Context, video *entities.Video) error
+}
+
+type UploadSessionRepository interface {
+	Save(ctx context.Context, session *entities.UploadSession) error
+	FindByID(ctx context.Context, sessionID string) (*entities.UploadSession, error)
+	// Update fails with ErrUploadSessionConflict unless the stored session
+	// is still at session.Version, and bumps Version on success.
+	Update(ctx context.Context, session *entities.UploadSession) error
+	Delete(ctx context.Context, sessionID string) error
 }
 
 type VideoQueue interface {
 	Send(ctx context.Context, message dto.VideoProcessMessage) error
-	Get(ctx context.Context) ([]types.Message, error)
+	// Get receives up to maxMessages messages, hidden from other consumers
+	// for visibilityTimeout.
+	Get(ctx context.Context, maxMessages int, visibilityTimeout time.Duration) ([]types.Message, error)
+	// ChangeVisibility hides a received message for timeout from now on.
+	ChangeVisibility(ctx context.Context, message types.Message, timeout time.Duration) error
 	Delete(ctx context.Context, message types.Message) error
+	// DeadLetter moves a message that cannot be processed to the
+	// dead-letter queue, recording why.
+	DeadLetter(ctx context.Context, message types.Message, reason string) error
+}
+
+var ErrObjectNotFound = errors.New("object not found")
+
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
 }
 
 type StorageService interface {
 	Upload(ctx context.Context, key string, data []byte, contentType string) error
+	UploadStream(ctx context.Context, key string, body io.Reader, contentType string) (int64, error)
 	Download(ctx context.Context, key string) ([]byte, error)
+	// DownloadStream returns the object body; callers must close it.
+	DownloadStream(ctx context.Context, key string) (io.ReadCloser, error)
 	GetPresignedURL(ctx context.Context, key string, expirationMinutes int) (string, error)
+	GetPresignedUploadURL(ctx context.Context, key, contentType string, contentLength int64, expirationMinutes int) (string, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error)
+	UploadPart(ctx context.Context, key, uploadID string, partNumber int32, data []byte) (string, error)
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []entities.UploadPart) error
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
 	Delete(ctx context.Context, key string) error
+	// DeletePrefix deletes every object whose key starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+	// Copy copies an object within storage, without downloading it.
+	Copy(ctx context.Context, sourceKey, key string) error
+	// CopyPrefix copies every object under sourcePrefix to the same key
+	// under prefix.
+	CopyPrefix(ctx context.Context, sourcePrefix, prefix string) error
+}
+
+// ErrInvalidVideo is returned by a VideoProber when the source is not a
+// media file it can decode.
+var ErrInvalidVideo = errors.New("invalid video")
+
+type VideoProber interface {
+	// Probe inspects the media at source, a local path or URL.
+	Probe(ctx context.Context, source string) (*entities.MediaInfo, error)
 }
 
 type TokenService interface {
@@ -41,3 +156,84 @@ type NotificationService interface {
 	SendVideoProcessedNotification(ctx context.Context, email, videoID, originalName string) error
 	SendVideoFailedNotification(ctx context.Context, email, videoID, originalName, errorMessage string) error
 }
+
+// ErrWebhookNotFound is returned by WebhookRepository reads for a webhook
+// that does not exist.
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+type WebhookRepository interface {
+	Save(ctx context.Context, webhook *entities.Webhook) error
+	FindByID(ctx context.Context, webhookID string) (*entities.Webhook, error)
+	FindByUserID(ctx context.Context, userID string) ([]*entities.Webhook, error)
+	Update(ctx context.Context, webhook *entities.Webhook) error
+	Delete(ctx context.Context, webhookID string) error
+}
+
+// ErrWebhookDeliveryExists is returned by WebhookDeliveryRepository.Save for
+// a delivery that was already recorded.
+var ErrWebhookDeliveryExists = errors.New("webhook delivery already exists")
+
+// ErrWebhookDeliveryNotFound is returned by WebhookDeliveryRepository reads
+// for a delivery that does not exist, or has expired.
+var ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+type WebhookDeliveryRepository interface {
+	Save(ctx context.Context, delivery *entities.WebhookDelivery) error
+	FindByID(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error)
+	Update(ctx context.Context, delivery *entities.WebhookDelivery) error
+	// FindByWebhookID returns the latest deliveries to the webhook, newest
+	// first.
+	FindByWebhookID(ctx context.Context, webhookID string, limit int) ([]*entities.WebhookDelivery, error)
+}
+
+type WebhookQueue interface {
+	Send(ctx context.Context, message dto.WebhookDeliveryMessage) error
+	// Get receives up to maxMessages messages, hidden from other consumers
+	// for visibilityTimeout.
+	Get(ctx context.Context, maxMessages int, visibilityTimeout time.Duration) ([]types.Message, error)
+	// ChangeVisibility hides a received message for timeout from now on.
+	ChangeVisibility(ctx context.Context, message types.Message, timeout time.Duration) error
+	Delete(ctx context.Context, message types.Message) error
+}
+
+type WebhookClient interface {
+	// Post sends body to url and returns the status code of the response.
+	Post(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
+
+// VideoEventPublisher hands each saved state of a video to the subscribers
+// of its owner.
+type VideoEventPublisher interface {
+	Publish(video *entities.Video)
+}
+
+type VideoEventSubscriber interface {
+	// Subscribe delivers each saved state of userID's videos until ctx is
+	// done, then closes the channel. A subscriber that falls behind misses
+	// states rather than holding up the others. The videos are shared and
+	// must not be modified.
+	Subscribe(ctx context.Context, userID string) <-chan *entities.Video
+}
+
+// QuotaExceededError is returned by UsageRepository.Reserve when one more
+// video would exceed one of the user's quotas.
+type QuotaExceededError struct {
+	Quota entities.Quota
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s quota exceeded", e.Quota)
+}
+
+// UsageRepository keeps the usage counters of each user. Writes are atomic,
+// so concurrent uploads cannot overshoot a quota together.
+type UsageRepository interface {
+	// Get returns a zero usage for a user that has none recorded.
+	Get(ctx context.Context, userID string) (*entities.Usage, error)
+	// Reserve counts one more video of size bytes and one more upload in the
+	// current hour, or fails with a QuotaExceededError without counting it.
+	Reserve(ctx context.Context, userID string, bytes int64, limits entities.UsageLimits, now time.Time) error
+	// Adjust adds the deltas to the stored bytes and video count, which
+	// never drop below zero.
+	Adjust(ctx context.Context, userID string, bytes int64, videos int) error
+}
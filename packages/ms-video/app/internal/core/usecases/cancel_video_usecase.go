@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// cancelVideoAttempts bounds how often a cancellation is retried.
+const cancelVideoAttempts = 3
+
+type CancelVideoUsecase struct {
+	videoRepository ports.VideoRepository
+}
+
+func NewCancelVideoUsecase(videoRepository ports.VideoRepository) *CancelVideoUsecase {
+	return &CancelVideoUsecase{
+		videoRepository: videoRepository,
+	}
+}
+
+// Execute marks a pending or processing video as cancelled.
+func (u *CancelVideoUsecase) Execute(ctx context.Context, videoID, userID string) (*dto.UploadVideoOutput, error) {
+	video, err := u.videoRepository.FindByID(ctx, videoID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("video not found")
+	}
+
+	if video.UserID != userID {
+		return nil, utils.NewUnauthorizedError("you don't have permission to cancel this video")
+	}
+
+	if video.AwaitingUpload {
+		return nil, utils.NewConflictError("video upload has not been confirmed, delete the video instead")
+	}
+
+	for attempt := 1; ; attempt++ {
+		if video.IsTerminal() {
+			return nil, utils.NewConflictError(fmt.Sprintf("video is already %s", video.Status))
+		}
+
+		video.Cancel()
+		err := u.videoRepository.Update(ctx, video)
+		var conflict *ports.VersionConflictError
+		if !errors.As(err, &conflict) {
+			if err != nil {
+				return nil, utils.NewInternalServerError("failed to cancel video")
+			}
+			break
+		}
+
+		if attempt == cancelVideoAttempts {
+			return nil, utils.NewConflictError("video is being updated, try again")
+		}
+
+		video, err = u.videoRepository.FindByID(ctx, videoID)
+		if errors.Is(err, ports.ErrVideoNotFound) {
+			return nil, utils.NewNotFoundError("video not found")
+		} else if err != nil {
+			return nil, utils.NewInternalServerError("failed to cancel video")
+		}
+	}
+
+	log.Printf("Cancelled video %s", video.ID)
+
+	return &dto.UploadVideoOutput{
+		VideoID:      video.ID,
+		OriginalName: video.OriginalName,
+		Status:       string(video.Status),
+		Message:      "Video processing cancelled",
+	}, nil
+}
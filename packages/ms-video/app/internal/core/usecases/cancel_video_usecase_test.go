@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func TestCancelVideoUsecase_Execute_Success(t *testing.T) {
+	ctx := context.Background()
+
+	for _, status := range []entities.VideoStatus{entities.VideoStatusPending, entities.VideoStatusProcessing} {
+		t.Run(string(status), func(t *testing.T) {
+			var savedStatus entities.VideoStatus
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return &entities.Video{ID: id, UserID: "user-123", Status: status, ProgressPercent: 40}, nil
+				},
+				UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+					savedStatus = v.Status
+					return nil
+				},
+			}
+
+			output, err := NewCancelVideoUsecase(videoRepo).Execute(ctx, "video-123", "user-123")
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if savedStatus != entities.VideoStatusCancelled {
+				t.Errorf("expected video saved as cancelled, got %s", savedStatus)
+			}
+			if output.Status != string(entities.VideoStatusCancelled) {
+				t.Errorf("expected status cancelled, got %s", output.Status)
+			}
+		})
+	}
+}
+
+func TestCancelVideoUsecase_Execute_RetriesWhenUpdatedConcurrently(t *testing.T) {
+	ctx := context.Background()
+
+	versions := []int64{1, 2}
+	findCalls := 0
+	var updateVersions []int64
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			version := versions[findCalls]
+			findCalls++
+			return &entities.Video{ID: id, UserID: "user-123", Status: entities.VideoStatusProcessing, Version: version}, nil
+		},
+		UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+			updateVersions = append(updateVersions, v.Version)
+			if v.Version == 1 {
+				return &ports.VersionConflictError{VideoID: v.ID, Version: v.Version}
+			}
+			return nil
+		},
+	}
+
+	_, err := NewCancelVideoUsecase(videoRepo).Execute(ctx, "video-123", "user-123")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(updateVersions, []int64{1, 2}) {
+		t.Errorf("expected cancellation retried at the reloaded version, got %v", updateVersions)
+	}
+}
+
+func TestCancelVideoUsecase_Execute_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		userID       string
+		findErr      error
+		prepare      func(video *entities.Video)
+		updateErr    error
+		expectedCode int
+	}{
+		{
+			name:         "should return 404 when video not found",
+			userID:       "user-123",
+			findErr:      ports.ErrVideoNotFound,
+			expectedCode: 404,
+		},
+		{
+			name:         "should return 401 for another user's video",
+			userID:       "other-user",
+			expectedCode: 401,
+		},
+		{
+			name:   "should return 409 for a completed video",
+			userID: "user-123",
+			prepare: func(video *entities.Video) {
+				video.Status = entities.VideoStatusCompleted
+			},
+			expectedCode: 409,
+		},
+		{
+			name:   "should return 409 for a video that is already cancelled",
+			userID: "user-123",
+			prepare: func(video *entities.Video) {
+				video.Status = entities.VideoStatusCancelled
+			},
+			expectedCode: 409,
+		},
+		{
+			name:   "should return 409 for a video awaiting upload",
+			userID: "user-123",
+			prepare: func(video *entities.Video) {
+				video.AwaitingUpload = true
+			},
+			expectedCode: 409,
+		},
+		{
+			name:         "should return 409 when the video keeps changing",
+			userID:       "user-123",
+			updateErr:    &ports.VersionConflictError{VideoID: "video-123", Version: 1},
+			expectedCode: 409,
+		},
+		{
+			name:         "should return 500 when the video cannot be saved",
+			userID:       "user-123",
+			updateErr:    errors.New("dynamodb unavailable"),
+			expectedCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					if tt.findErr != nil {
+						return nil, tt.findErr
+					}
+					video := &entities.Video{ID: id, UserID: "user-123", Status: entities.VideoStatusProcessing, Version: 1}
+					if tt.prepare != nil {
+						tt.prepare(video)
+					}
+					return video, nil
+				},
+				UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+					return tt.updateErr
+				},
+			}
+
+			_, err := NewCancelVideoUsecase(videoRepo).Execute(ctx, "video-123", tt.userID)
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+			if httpErr.StatusCode != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+type ConfirmUploadUsecase struct {
+	videoRepository ports.VideoRepository
+	storageService  ports.StorageService
+	videoQueue      ports.VideoQueue
+	videoValidator  *VideoValidator
+	usageRepository ports.UsageRepository
+}
+
+func NewConfirmUploadUsecase(
+	videoRepository ports.VideoRepository,
+	storageService ports.StorageService,
+	videoQueue ports.VideoQueue,
+	videoValidator *VideoValidator,
+	usageRepository ports.UsageRepository,
+) *ConfirmUploadUsecase {
+	return &ConfirmUploadUsecase{
+		videoRepository: videoRepository,
+		storageService:  storageService,
+		videoQueue:      videoQueue,
+		videoValidator:  videoValidator,
+		usageRepository: usageRepository,
+	}
+}
+
+func (u *ConfirmUploadUsecase) Execute(ctx context.Context, videoID, userID string) (*dto.UploadVideoOutput, error) {
+	video, err := u.videoRepository.FindByID(ctx, videoID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("video not found")
+	}
+
+	if video.UserID != userID {
+		return nil, utils.NewUnauthorizedError("you don't have permission to confirm this video")
+	}
+
+	if !video.AwaitingUpload {
+		return nil, utils.NewConflictError("video upload has already been confirmed")
+	}
+
+	object, err := u.storageService.Stat(ctx, video.RawS3Key)
+	if errors.Is(err, ports.ErrObjectNotFound) {
+		return nil, utils.NewBadRequestError("video file has not been uploaded yet")
+	}
+	if err != nil {
+		return nil, utils.NewInternalServerError("failed to check uploaded video")
+	}
+
+	if object.Size != video.FileSize {
+		return nil, utils.NewBadRequestError(fmt.Sprintf("uploaded file size %d does not match declared size %d", object.Size, video.FileSize))
+	}
+
+	if _, err := u.videoValidator.Validate(ctx, video.RawS3Key, video.ExtractionOptions); err != nil {
+		if isBadRequest(err) {
+			discardRejectedUpload(ctx, u.videoRepository, u.storageService, u.usageRepository, video, err)
+		}
+		return nil, err
+	}
+
+	video.ConfirmUpload(object.Size)
+	var conflict *ports.VersionConflictError
+	if err := u.videoRepository.Update(ctx, video); errors.As(err, &conflict) {
+		// A concurrent confirmation won the race and queued the video
+		return nil, utils.NewConflictError("video upload has already been confirmed")
+	} else if err != nil {
+		return nil, utils.NewInternalServerError("failed to save video metadata")
+	}
+
+	if err := enqueueVideo(ctx, u.videoQueue, video); err != nil {
+		return nil, err
+	}
+
+	return &dto.UploadVideoOutput{
+		VideoID:      video.ID,
+		OriginalName: video.OriginalName,
+		Status:       string(video.Status),
+		Message:      "Video upload confirmed and queued for processing",
+	}, nil
+}
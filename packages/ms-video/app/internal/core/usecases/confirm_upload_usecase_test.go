@@ -0,0 +1,266 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func newAwaitingUploadVideo() *entities.Video {
+	return &entities.Video{
+		ID:             "video-123",
+		UserID:         "user-123",
+		UserEmail:      "user@example.com",
+		OriginalName:   "test.mp4",
+		RawS3Key:       "raw/user-123/video-123/test.mp4",
+		Status:         entities.VideoStatusPending,
+		FileSize:       2048,
+		AwaitingUpload: true,
+	}
+}
+
+func TestConfirmUploadUsecase_Execute_Success(t *testing.T) {
+	ctx := context.Background()
+	video := newAwaitingUploadVideo()
+
+	var queuedMessage dto.VideoProcessMessage
+
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return video, nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		StatFunc: func(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+			return &ports.ObjectInfo{Size: 2048}, nil
+		},
+	}
+	videoQueue := &mocks.MockVideoQueue{
+		SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+			queuedMessage = message
+			return nil
+		},
+	}
+
+	usecase := NewConfirmUploadUsecase(videoRepo, storageService, videoQueue, newTestVideoValidator(storageService), &mocks.MockUsageRepository{})
+
+	output, err := usecase.Execute(ctx, video.ID, video.UserID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if output.VideoID != video.ID {
+		t.Errorf("expected VideoID '%s', got '%s'", video.ID, output.VideoID)
+	}
+
+	if video.AwaitingUpload {
+		t.Error("expected video to no longer be awaiting upload")
+	}
+
+	if queuedMessage.RawS3Key != video.RawS3Key {
+		t.Errorf("expected queued raw key '%s', got '%s'", video.RawS3Key, queuedMessage.RawS3Key)
+	}
+}
+
+func TestConfirmUploadUsecase_Execute_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		userID       string
+		prepare      func(video *entities.Video)
+		stat         func(ctx context.Context, key string) (*ports.ObjectInfo, error)
+		expectedCode int
+	}{
+		{
+			name:         "should reject other users",
+			userID:       "other-user",
+			expectedCode: 401,
+		},
+		{
+			name:   "should reject videos already confirmed",
+			userID: "user-123",
+			prepare: func(video *entities.Video) {
+				video.AwaitingUpload = false
+			},
+			expectedCode: 409,
+		},
+		{
+			name:   "should reject when object was not uploaded",
+			userID: "user-123",
+			stat: func(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+				return nil, ports.ErrObjectNotFound
+			},
+			expectedCode: 400,
+		},
+		{
+			name:   "should reject when object size differs from declared size",
+			userID: "user-123",
+			stat: func(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+				return &ports.ObjectInfo{Size: 10}, nil
+			},
+			expectedCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := newAwaitingUploadVideo()
+			if tt.prepare != nil {
+				tt.prepare(video)
+			}
+
+			queued := false
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return video, nil
+				},
+			}
+			storageService := &mocks.MockStorageService{StatFunc: tt.stat}
+			videoQueue := &mocks.MockVideoQueue{
+				SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+					queued = true
+					return nil
+				},
+			}
+
+			usecase := NewConfirmUploadUsecase(videoRepo, storageService, videoQueue, newTestVideoValidator(storageService), &mocks.MockUsageRepository{})
+
+			_, err := usecase.Execute(ctx, video.ID, tt.userID)
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+
+			if httpErr.StatusCode != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+			}
+
+			if queued {
+				t.Error("expected video not to be queued")
+			}
+		})
+	}
+}
+
+func TestConfirmUploadUsecase_Execute_ConcurrentConfirmation(t *testing.T) {
+	ctx := context.Background()
+	video := newAwaitingUploadVideo()
+
+	queued := false
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return video, nil
+		},
+		UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+			return &ports.VersionConflictError{VideoID: v.ID, Version: v.Version}
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		StatFunc: func(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+			return &ports.ObjectInfo{Size: 2048}, nil
+		},
+	}
+	videoQueue := &mocks.MockVideoQueue{
+		SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+			queued = true
+			return nil
+		},
+	}
+
+	usecase := NewConfirmUploadUsecase(videoRepo, storageService, videoQueue, newTestVideoValidator(storageService), &mocks.MockUsageRepository{})
+
+	_, err := usecase.Execute(ctx, video.ID, video.UserID)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != 409 {
+		t.Errorf("expected status code 409, got %d", httpErr.StatusCode)
+	}
+
+	if queued {
+		t.Error("expected video not to be queued twice")
+	}
+}
+
+func TestConfirmUploadUsecase_Execute_InvalidVideoContent(t *testing.T) {
+	ctx := context.Background()
+	video := newAwaitingUploadVideo()
+
+	deletedKey := ""
+	queued := false
+
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return video, nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		StatFunc: func(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+			return &ports.ObjectInfo{Size: 2048}, nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			deletedKey = key
+			return nil
+		},
+	}
+	videoQueue := &mocks.MockVideoQueue{
+		SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+			queued = true
+			return nil
+		},
+	}
+	prober := &mocks.MockVideoProber{
+		ProbeFunc: func(ctx context.Context, source string) (*entities.MediaInfo, error) {
+			return nil, ports.ErrInvalidVideo
+		},
+	}
+
+	var releasedBytes int64
+	var releasedVideos int
+	usageRepo := &mocks.MockUsageRepository{
+		AdjustFunc: func(ctx context.Context, userID string, bytes int64, videos int) error {
+			releasedBytes, releasedVideos = bytes, videos
+			return nil
+		},
+	}
+
+	usecase := NewConfirmUploadUsecase(videoRepo, storageService, videoQueue, NewVideoValidator(prober, storageService, DefaultVideoLimits()), usageRepo)
+
+	_, err := usecase.Execute(ctx, video.ID, video.UserID)
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != 400 {
+		t.Errorf("expected status code 400, got %d", httpErr.StatusCode)
+	}
+
+	if video.Status != entities.VideoStatusFailed {
+		t.Errorf("expected video status '%s', got '%s'", entities.VideoStatusFailed, video.Status)
+	}
+
+	if deletedKey != video.RawS3Key || video.RawDeletedAt == nil {
+		t.Errorf("expected raw object to be deleted, got '%s'", deletedKey)
+	}
+
+	if releasedBytes != -2048 || releasedVideos != 0 {
+		t.Errorf("expected the rejected upload released from usage, got %d bytes and %d videos", releasedBytes, releasedVideos)
+	}
+
+	if queued {
+		t.Error("expected rejected video not to be queued")
+	}
+}
@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"log"
+	"os"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+// dedupCandidatesLimit bounds how many same-content videos are checked.
+const dedupCandidatesLimit = 20
+
+// DedupAcrossUsersFromEnv reports whether DEDUP_ACROSS_USERS is enabled.
+func DedupAcrossUsersFromEnv() bool {
+	return os.Getenv("DEDUP_ACROSS_USERS") == "true"
+}
+
+// VideoDeduplicator finds a processed video an upload can reuse.
+type VideoDeduplicator struct {
+	videoRepository ports.VideoRepository
+	acrossUsers     bool
+}
+
+func NewVideoDeduplicator(videoRepository ports.VideoRepository, acrossUsers bool) *VideoDeduplicator {
+	return &VideoDeduplicator{
+		videoRepository: videoRepository,
+		acrossUsers:     acrossUsers,
+	}
+}
+
+// MarkDuplicate points video at a completed video it can reuse the outputs
+// of, preferring the user's own, and returns that video or nil.
+func (d *VideoDeduplicator) MarkDuplicate(ctx context.Context, video *entities.Video) *entities.Video {
+	if video.ContentHash == "" {
+		return nil
+	}
+
+	candidates, err := d.videoRepository.FindByContentHash(ctx, video.ContentHash, dedupCandidatesLimit)
+	if err != nil {
+		log.Printf("Failed to look for duplicates of video %s: %v", video.ID, err)
+		return nil
+	}
+
+	var source *entities.Video
+	for _, candidate := range candidates {
+		if !video.CanReuseOutputsOf(candidate) {
+			continue
+		}
+		if candidate.UserID == video.UserID {
+			source = candidate
+			break
+		}
+		if d.acrossUsers && source == nil {
+			source = candidate
+		}
+	}
+
+	if source != nil {
+		video.DuplicateOf = source.ID
+	}
+	return source
+}
+
+// hashingReader computes the SHA-256 of everything read through it.
+type hashingReader struct {
+	reader io.Reader
+	hash   hash.Hash
+}
+
+func newHashingReader(reader io.Reader) *hashingReader {
+	return &hashingReader{reader: reader, hash: sha256.New()}
+}
+
+func (r *hashingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.hash.Write(p[:n])
+	return n, err
+}
+
+// Sum is the hex SHA-256 of what was read so far.
+func (r *hashingReader) Sum() string {
+	return hex.EncodeToString(r.hash.Sum(nil))
+}
@@ -0,0 +1,95 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+)
+
+func newTestDeduplicator() *VideoDeduplicator {
+	return NewVideoDeduplicator(&mocks.MockVideoRepository{}, false)
+}
+
+func TestVideoDeduplicator_MarkDuplicate(t *testing.T) {
+	ctx := context.Background()
+
+	processed := func(id, userID string, options entities.ExtractionOptions) *entities.Video {
+		return &entities.Video{
+			ID:                id,
+			UserID:            userID,
+			Status:            entities.VideoStatusCompleted,
+			ProcessedS3Key:    "processed/" + userID + "/" + id + ".zip",
+			ContentHash:       "hash-1",
+			ExtractionOptions: options,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		candidates  []*entities.Video
+		findErr     error
+		acrossUsers bool
+		expected    string
+	}{
+		{
+			name: "should prefer the user's own video",
+			candidates: []*entities.Video{
+				processed("other-video", "user-456", entities.ExtractionOptions{}),
+				processed("own-video", "user-123", entities.ExtractionOptions{}),
+			},
+			acrossUsers: true,
+			expected:    "own-video",
+		},
+		{
+			name:       "should not reuse another user's video by default",
+			candidates: []*entities.Video{processed("other-video", "user-456", entities.ExtractionOptions{})},
+		},
+		{
+			name:        "should reuse another user's video when enabled",
+			candidates:  []*entities.Video{processed("other-video", "user-456", entities.ExtractionOptions{})},
+			acrossUsers: true,
+			expected:    "other-video",
+		},
+		{
+			name:       "should ignore videos extracted with other options",
+			candidates: []*entities.Video{processed("own-video", "user-123", entities.ExtractionOptions{FPS: 5})},
+		},
+		{
+			name: "should ignore videos that are not processed yet",
+			candidates: []*entities.Video{
+				{ID: "own-video", UserID: "user-123", Status: entities.VideoStatusProcessing, ContentHash: "hash-1"},
+			},
+		},
+		{
+			name:    "should not mark a duplicate when the lookup fails",
+			findErr: errors.New("dynamodb error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoRepo := &mocks.MockVideoRepository{
+				FindByContentHashFunc: func(ctx context.Context, contentHash string, limit int) ([]*entities.Video, error) {
+					if contentHash != "hash-1" {
+						t.Errorf("expected lookup by hash-1, got %q", contentHash)
+					}
+					return tt.candidates, tt.findErr
+				},
+			}
+			video := entities.NewVideo("user-123", "user@example.com", "test.mp4", "raw/user-123/test.mp4", 100)
+			video.ContentHash = "hash-1"
+
+			source := NewVideoDeduplicator(videoRepo, tt.acrossUsers).MarkDuplicate(ctx, video)
+
+			if video.DuplicateOf != tt.expected {
+				t.Errorf("expected duplicate of %q, got %q", tt.expected, video.DuplicateOf)
+			}
+			if (source != nil) != (tt.expected != "") || (source != nil && source.ID != tt.expected) {
+				t.Errorf("expected source %q, got %+v", tt.expected, source)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// deleteVideoAttempts bounds how often a deletion starts over.
+const deleteVideoAttempts = 3
+
+type DeleteVideoUsecase struct {
+	videoRepository   ports.VideoRepository
+	sessionRepository ports.UploadSessionRepository
+	storageService    ports.StorageService
+	usageRepository   ports.UsageRepository
+}
+
+func NewDeleteVideoUsecase(videoRepository ports.VideoRepository, sessionRepository ports.UploadSessionRepository, storageService ports.StorageService, usageRepository ports.UsageRepository) *DeleteVideoUsecase {
+	return &DeleteVideoUsecase{
+		videoRepository:   videoRepository,
+		sessionRepository: sessionRepository,
+		storageService:    storageService,
+		usageRepository:   usageRepository,
+	}
+}
+
+// Execute deletes the files of the video, then the video itself.
+func (u *DeleteVideoUsecase) Execute(ctx context.Context, videoID, userID string) error {
+	video, err := u.videoRepository.FindByID(ctx, videoID)
+	if err != nil {
+		return utils.NewNotFoundError("video not found")
+	}
+
+	if video.UserID != userID {
+		return utils.NewUnauthorizedError("you don't have permission to delete this video")
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := u.deleteFiles(ctx, video); err != nil {
+			log.Printf("Failed to delete files of video %s: %v", video.ID, err)
+			return utils.NewInternalServerError("failed to delete video files")
+		}
+
+		err := u.videoRepository.Delete(ctx, video)
+		var conflict *ports.VersionConflictError
+		if !errors.As(err, &conflict) {
+			if err != nil {
+				return utils.NewInternalServerError("failed to delete video")
+			}
+			log.Printf("Deleted video %s", video.ID)
+			releaseVideoUsage(ctx, u.usageRepository, video)
+			return nil
+		}
+
+		if attempt == deleteVideoAttempts {
+			return utils.NewConflictError("video is being updated, try again")
+		}
+
+		video, err = u.videoRepository.FindByID(ctx, videoID)
+		if errors.Is(err, ports.ErrVideoNotFound) {
+			// Deleted by a concurrent request
+			return nil
+		} else if err != nil {
+			return utils.NewInternalServerError("failed to delete video")
+		}
+	}
+}
+
+func (u *DeleteVideoUsecase) deleteFiles(ctx context.Context, video *entities.Video) error {
+	if video.AwaitingUpload && video.UploadSessionID != "" {
+		if err := u.deleteUploadSession(ctx, video.UploadSessionID); err != nil {
+			return err
+		}
+	}
+
+	if video.RawS3Key != "" {
+		if err := u.storageService.Delete(ctx, video.RawS3Key); err != nil {
+			return err
+		}
+	}
+
+	return deleteVideoOutputs(ctx, u.storageService, video.UserID, video.ID)
+}
+
+// deleteUploadSession aborts a resumable upload still in progress and removes
+// its session.
+func (u *DeleteVideoUsecase) deleteUploadSession(ctx context.Context, sessionID string) error {
+	session, err := u.sessionRepository.FindByID(ctx, sessionID)
+	if errors.Is(err, ports.ErrUploadSessionNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err := u.storageService.AbortMultipartUpload(ctx, session.RawS3Key, session.UploadID); err != nil {
+		return err
+	}
+	if err := u.storageService.Delete(ctx, session.TailKey()); err != nil {
+		return err
+	}
+
+	return u.sessionRepository.Delete(ctx, session.ID)
+}
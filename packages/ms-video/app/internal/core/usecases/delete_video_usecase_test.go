@@ -0,0 +1,324 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func TestDeleteVideoUsecase_Execute_Success(t *testing.T) {
+	ctx := context.Background()
+
+	video := &entities.Video{
+		ID:          "video-123",
+		UserID:      "user-123",
+		RawS3Key:    "raw/user-123/video-123/video.mp4",
+		FileSize:    1000,
+		OutputBytes: 500,
+		Status:      entities.VideoStatusCompleted,
+		Version:     4,
+	}
+
+	var deletedVideo *entities.Video
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return video, nil
+		},
+		DeleteFunc: func(ctx context.Context, v *entities.Video) error {
+			deletedVideo = v
+			return nil
+		},
+	}
+
+	var deleted []string
+	storageService := &mocks.MockStorageService{
+		DeleteFunc: func(ctx context.Context, key string) error {
+			deleted = append(deleted, key)
+			return nil
+		},
+		DeletePrefixFunc: func(ctx context.Context, prefix string) error {
+			deleted = append(deleted, prefix+"*")
+			return nil
+		},
+	}
+
+	var releasedBytes int64
+	var releasedVideos int
+	usageRepo := &mocks.MockUsageRepository{
+		AdjustFunc: func(ctx context.Context, userID string, bytes int64, videos int) error {
+			releasedBytes, releasedVideos = bytes, videos
+			return nil
+		},
+	}
+
+	err := NewDeleteVideoUsecase(videoRepo, &mocks.MockUploadSessionRepository{}, storageService, usageRepo).Execute(ctx, video.ID, video.UserID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if releasedBytes != -1500 || releasedVideos != -1 {
+		t.Errorf("expected the upload and outputs to be released, got %d bytes and %d videos", releasedBytes, releasedVideos)
+	}
+
+	expected := []string{
+		"raw/user-123/video-123/video.mp4",
+		"processed/user-123/video-123.zip",
+		"processed/user-123/video-123/frames/*",
+		"previews/user-123/video-123/*",
+		"hls/user-123/video-123/*",
+	}
+	if !reflect.DeepEqual(deleted, expected) {
+		t.Errorf("expected deleted objects %v, got %v", expected, deleted)
+	}
+
+	if deletedVideo == nil || deletedVideo.Version != 4 {
+		t.Errorf("expected video to be deleted at version 4, got %+v", deletedVideo)
+	}
+}
+
+func TestDeleteVideoUsecase_Execute_AbortsResumableUpload(t *testing.T) {
+	ctx := context.Background()
+
+	video := &entities.Video{
+		ID:              "video-123",
+		UserID:          "user-123",
+		RawS3Key:        "raw/user-123/video-123/video.mp4",
+		FileSize:        1000,
+		Status:          entities.VideoStatusPending,
+		AwaitingUpload:  true,
+		UploadSessionID: "session-123",
+	}
+	session := entities.NewUploadSession(video.ID, video.UserID, video.RawS3Key, "multipart-1", video.FileSize)
+	session.ID = video.UploadSessionID
+
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return video, nil
+		},
+	}
+	var deletedSession string
+	sessionRepo := &mocks.MockUploadSessionRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.UploadSession, error) {
+			if id != session.ID {
+				return nil, ports.ErrUploadSessionNotFound
+			}
+			return session, nil
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			deletedSession = id
+			return nil
+		},
+	}
+	var aborted string
+	var deleted []string
+	storageService := &mocks.MockStorageService{
+		AbortMultipartUploadFunc: func(ctx context.Context, key, uploadID string) error {
+			aborted = uploadID
+			return nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			deleted = append(deleted, key)
+			return nil
+		},
+	}
+
+	err := NewDeleteVideoUsecase(videoRepo, sessionRepo, storageService, &mocks.MockUsageRepository{}).Execute(ctx, video.ID, video.UserID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if aborted != "multipart-1" {
+		t.Errorf("expected the multipart upload to be aborted, got '%s'", aborted)
+	}
+	if deletedSession != session.ID {
+		t.Errorf("expected upload session %s to be deleted, got '%s'", session.ID, deletedSession)
+	}
+	if len(deleted) == 0 || deleted[0] != session.TailKey() {
+		t.Errorf("expected the upload tail to be deleted, got %v", deleted)
+	}
+}
+
+func TestDeleteVideoUsecase_Execute_KeepsSameNamedUpload(t *testing.T) {
+	ctx := context.Background()
+
+	videos := map[string]*entities.Video{}
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			videos[video.ID] = video
+			return nil
+		},
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return videos[id], nil
+		},
+	}
+
+	stored := map[string]bool{}
+	storageService := &mocks.MockStorageService{
+		UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+			stored[key] = true
+			return io.Copy(io.Discard, body)
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			delete(stored, key)
+			return nil
+		},
+	}
+
+	uploadUsecase := NewUploadVideoUsecase(videoRepo, storageService, &mocks.MockVideoQueue{}, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, DefaultUsageLimits(), newTestDeduplicator())
+
+	first, err := uploadUsecase.Execute(ctx, newUploadInput("first video"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	second, err := uploadUsecase.Execute(ctx, newUploadInput("second video"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := NewDeleteVideoUsecase(videoRepo, &mocks.MockUploadSessionRepository{}, storageService, &mocks.MockUsageRepository{}).Execute(ctx, first.VideoID, "user-123"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if stored[videos[first.VideoID].RawS3Key] {
+		t.Error("expected the deleted video's upload to be removed")
+	}
+	if !stored[videos[second.VideoID].RawS3Key] {
+		t.Errorf("expected the other upload '%s' to be kept, got %v", videos[second.VideoID].RawS3Key, stored)
+	}
+}
+
+func TestDeleteVideoUsecase_Execute_RetriesWhenUpdatedConcurrently(t *testing.T) {
+	ctx := context.Background()
+
+	versions := []int64{1, 2}
+	findCalls := 0
+	var deleteVersions []int64
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			version := versions[findCalls]
+			findCalls++
+			return &entities.Video{ID: id, UserID: "user-123", Status: entities.VideoStatusProcessing, Version: version}, nil
+		},
+		DeleteFunc: func(ctx context.Context, v *entities.Video) error {
+			deleteVersions = append(deleteVersions, v.Version)
+			if v.Version == 1 {
+				return &ports.VersionConflictError{VideoID: v.ID, Version: v.Version}
+			}
+			return nil
+		},
+	}
+
+	zipDeletes := 0
+	storageService := &mocks.MockStorageService{
+		DeleteFunc: func(ctx context.Context, key string) error {
+			zipDeletes++
+			return nil
+		},
+	}
+
+	err := NewDeleteVideoUsecase(videoRepo, &mocks.MockUploadSessionRepository{}, storageService, &mocks.MockUsageRepository{}).Execute(ctx, "video-123", "user-123")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(deleteVersions, []int64{1, 2}) {
+		t.Errorf("expected deletion retried at the reloaded version, got %v", deleteVersions)
+	}
+	if zipDeletes != 2 {
+		t.Errorf("expected files deleted again before retrying, got %d deletions", zipDeletes)
+	}
+}
+
+func TestDeleteVideoUsecase_Execute_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	owned := func() *entities.Video {
+		return &entities.Video{ID: "video-123", UserID: "user-123", Version: 1}
+	}
+
+	tests := []struct {
+		name           string
+		userID         string
+		findErr        error
+		storageErr     error
+		deleteErr      error
+		expectedCode   int
+		expectDeletion bool
+	}{
+		{
+			name:         "should return 404 when video not found",
+			userID:       "user-123",
+			findErr:      ports.ErrVideoNotFound,
+			expectedCode: 404,
+		},
+		{
+			name:         "should return 401 for another user's video",
+			userID:       "other-user",
+			expectedCode: 401,
+		},
+		{
+			name:         "should return 500 and keep the video when files cannot be deleted",
+			userID:       "user-123",
+			storageErr:   errors.New("s3 unavailable"),
+			expectedCode: 500,
+		},
+		{
+			name:           "should return 409 when the video keeps changing",
+			userID:         "user-123",
+			deleteErr:      &ports.VersionConflictError{VideoID: "video-123", Version: 1},
+			expectedCode:   409,
+			expectDeletion: true,
+		},
+		{
+			name:           "should return 500 when the record cannot be deleted",
+			userID:         "user-123",
+			deleteErr:      errors.New("dynamodb unavailable"),
+			expectedCode:   500,
+			expectDeletion: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deletionAttempted := false
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					if tt.findErr != nil {
+						return nil, tt.findErr
+					}
+					return owned(), nil
+				},
+				DeleteFunc: func(ctx context.Context, v *entities.Video) error {
+					deletionAttempted = true
+					return tt.deleteErr
+				},
+			}
+			storageService := &mocks.MockStorageService{
+				DeletePrefixFunc: func(ctx context.Context, prefix string) error {
+					return tt.storageErr
+				},
+			}
+
+			err := NewDeleteVideoUsecase(videoRepo, &mocks.MockUploadSessionRepository{}, storageService, &mocks.MockUsageRepository{}).Execute(ctx, "video-123", tt.userID)
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+			if httpErr.StatusCode != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+			}
+			if deletionAttempted != tt.expectDeletion {
+				t.Errorf("expected record deletion attempted: %v, got %v", tt.expectDeletion, deletionAttempted)
+			}
+		})
+	}
+}
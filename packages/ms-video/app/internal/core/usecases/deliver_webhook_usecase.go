@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// Headers sent with every delivery.
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+func DefaultWebhookRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 8,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    1 * time.Hour,
+	}
+}
+
+// WebhookRetryPolicyFromEnv reads the delivery retry policy from the
+// environment.
+func WebhookRetryPolicyFromEnv() RetryPolicy {
+	policy := DefaultWebhookRetryPolicy()
+	return RetryPolicy{
+		MaxAttempts: max(utils.GetEnvInt("WEBHOOK_MAX_ATTEMPTS", policy.MaxAttempts), 1),
+		BaseDelay:   time.Duration(utils.GetEnvInt("WEBHOOK_RETRY_BASE_DELAY_SECONDS", int(policy.BaseDelay.Seconds()))) * time.Second,
+		MaxDelay:    time.Duration(utils.GetEnvInt("WEBHOOK_RETRY_MAX_DELAY_SECONDS", int(policy.MaxDelay.Seconds()))) * time.Second,
+	}
+}
+
+type DeliverWebhookUsecase struct {
+	deliveryRepository ports.WebhookDeliveryRepository
+	webhookRepository  ports.WebhookRepository
+	webhookClient      ports.WebhookClient
+	retryPolicy        RetryPolicy
+}
+
+func NewDeliverWebhookUsecase(
+	deliveryRepository ports.WebhookDeliveryRepository,
+	webhookRepository ports.WebhookRepository,
+	webhookClient ports.WebhookClient,
+	retryPolicy RetryPolicy,
+) *DeliverWebhookUsecase {
+	return &DeliverWebhookUsecase{
+		deliveryRepository: deliveryRepository,
+		webhookRepository:  webhookRepository,
+		webhookClient:      webhookClient,
+		retryPolicy:        retryPolicy,
+	}
+}
+
+// Execute posts the delivery for the attempt-th time, counting from 1.
+func (u *DeliverWebhookUsecase) Execute(ctx context.Context, message dto.WebhookDeliveryMessage, attempt int) error {
+	delivery, err := u.deliveryRepository.FindByID(ctx, message.DeliveryID)
+	if errors.Is(err, ports.ErrWebhookDeliveryNotFound) {
+		log.Printf("Skipping webhook delivery %s: not found", message.DeliveryID)
+		return nil
+	} else if err != nil {
+		return &RetryError{Err: err, Delay: u.retryPolicy.Backoff(attempt)}
+	}
+
+	if delivery.IsFinished() {
+		return nil
+	}
+
+	webhook, err := u.webhookRepository.FindByID(ctx, delivery.WebhookID)
+	if errors.Is(err, ports.ErrWebhookNotFound) {
+		delivery.RecordAttempt(0, errors.New("webhook was deleted"), true)
+		return u.deliveryRepository.Update(ctx, delivery)
+	} else if err != nil {
+		return &RetryError{Err: err, Delay: u.retryPolicy.Backoff(attempt)}
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	headers := map[string]string{
+		"Content-Type":         "application/json",
+		WebhookSignatureHeader: "sha256=" + SignWebhookPayload(webhook.Secret, timestamp, body),
+		WebhookTimestampHeader: timestamp,
+		WebhookEventHeader:     string(delivery.Event),
+		WebhookDeliveryHeader:  delivery.ID,
+	}
+
+	status, postErr := u.webhookClient.Post(ctx, webhook.URL, headers, body)
+	if postErr == nil && (status < 200 || status > 299) {
+		postErr = fmt.Errorf("receiver responded with status %d", status)
+	}
+	if postErr != nil && ctx.Err() != nil {
+		// Interrupted by shutdown, the next delivery of the message tries again
+		return ctx.Err()
+	}
+
+	final := u.retryPolicy.Exhausted(attempt)
+	delivery.RecordAttempt(status, postErr, final)
+	if err := u.deliveryRepository.Update(ctx, delivery); err != nil {
+		log.Printf("Failed to record attempt %d of webhook delivery %s: %v", attempt, delivery.ID, err)
+	}
+
+	if postErr == nil {
+		log.Printf("Delivered %s webhook %s for video %s", delivery.Event, webhook.ID, delivery.VideoID)
+		return nil
+	}
+	if final {
+		log.Printf("Giving up on webhook delivery %s after %d attempts: %v", delivery.ID, attempt, postErr)
+		return nil
+	}
+
+	return &RetryError{Err: postErr, Delay: u.retryPolicy.Backoff(attempt)}
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of timestamp and body that
+// receivers compare against the signature header.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,179 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+func TestDeliverWebhookUsecase_Execute(t *testing.T) {
+	ctx := context.Background()
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}
+
+	tests := []struct {
+		name           string
+		attempt        int
+		status         int
+		postErr        error
+		webhookErr     error
+		deliveryStatus entities.WebhookDeliveryStatus
+		expectPost     bool
+		expectedStatus entities.WebhookDeliveryStatus
+		expectedDelay  time.Duration
+	}{
+		{
+			name:           "should mark the delivery succeeded on a 2xx response",
+			attempt:        1,
+			status:         204,
+			expectPost:     true,
+			expectedStatus: entities.WebhookDeliverySucceeded,
+		},
+		{
+			name:           "should retry with backoff on an error response",
+			attempt:        2,
+			status:         503,
+			expectPost:     true,
+			expectedStatus: entities.WebhookDeliveryPending,
+			expectedDelay:  2 * time.Second,
+		},
+		{
+			name:           "should retry when the receiver cannot be reached",
+			attempt:        1,
+			postErr:        errors.New("connection refused"),
+			expectPost:     true,
+			expectedStatus: entities.WebhookDeliveryPending,
+			expectedDelay:  time.Second,
+		},
+		{
+			name:           "should give up after the last attempt",
+			attempt:        3,
+			status:         500,
+			expectPost:     true,
+			expectedStatus: entities.WebhookDeliveryFailed,
+		},
+		{
+			name:           "should fail the delivery when the webhook was deleted",
+			attempt:        1,
+			webhookErr:     ports.ErrWebhookNotFound,
+			expectedStatus: entities.WebhookDeliveryFailed,
+		},
+		{
+			name:           "should not post a delivery that already succeeded",
+			attempt:        2,
+			deliveryStatus: entities.WebhookDeliverySucceeded,
+			expectedStatus: entities.WebhookDeliverySucceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delivery := &entities.WebhookDelivery{
+				ID:        "delivery-1",
+				WebhookID: "hook-1",
+				Event:     entities.WebhookEventVideoCompleted,
+				Payload:   `{"id":"delivery-1"}`,
+				Status:    entities.WebhookDeliveryPending,
+			}
+			if tt.deliveryStatus != "" {
+				delivery.Status = tt.deliveryStatus
+			}
+
+			deliveryRepo := &mocks.MockWebhookDeliveryRepository{
+				FindByIDFunc: func(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error) {
+					return delivery, nil
+				},
+			}
+			webhookRepo := &mocks.MockWebhookRepository{
+				FindByIDFunc: func(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+					if tt.webhookErr != nil {
+						return nil, tt.webhookErr
+					}
+					return &entities.Webhook{ID: webhookID, URL: "https://example.com/hook", Secret: "0123456789abcdef"}, nil
+				},
+			}
+			posted := false
+			client := &mocks.MockWebhookClient{
+				PostFunc: func(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+					posted = true
+					return tt.status, tt.postErr
+				},
+			}
+
+			err := NewDeliverWebhookUsecase(deliveryRepo, webhookRepo, client, policy).Execute(ctx, dto.WebhookDeliveryMessage{DeliveryID: "delivery-1"}, tt.attempt)
+
+			if posted != tt.expectPost {
+				t.Errorf("expected post: %v, got %v", tt.expectPost, posted)
+			}
+			if delivery.Status != tt.expectedStatus {
+				t.Errorf("expected delivery status %s, got %s", tt.expectedStatus, delivery.Status)
+			}
+
+			var retryErr *RetryError
+			if tt.expectedDelay > 0 {
+				if !errors.As(err, &retryErr) {
+					t.Fatalf("expected RetryError, got %v", err)
+				}
+				if retryErr.Delay != tt.expectedDelay {
+					t.Errorf("expected retry in %s, got %s", tt.expectedDelay, retryErr.Delay)
+				}
+			} else if err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDeliverWebhookUsecase_Execute_SignsPayload(t *testing.T) {
+	secret := "0123456789abcdef"
+	payload := `{"id":"delivery-1","event":"video.completed"}`
+
+	deliveryRepo := &mocks.MockWebhookDeliveryRepository{
+		FindByIDFunc: func(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error) {
+			return &entities.WebhookDelivery{ID: deliveryID, WebhookID: "hook-1", Event: entities.WebhookEventVideoCompleted, Payload: payload, Status: entities.WebhookDeliveryPending}, nil
+		},
+	}
+	webhookRepo := &mocks.MockWebhookRepository{
+		FindByIDFunc: func(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+			return &entities.Webhook{ID: webhookID, URL: "https://example.com/hook", Secret: secret}, nil
+		},
+	}
+	var headers map[string]string
+	var body []byte
+	client := &mocks.MockWebhookClient{
+		PostFunc: func(ctx context.Context, url string, h map[string]string, b []byte) (int, error) {
+			headers, body = h, b
+			return 200, nil
+		},
+	}
+
+	err := NewDeliverWebhookUsecase(deliveryRepo, webhookRepo, client, DefaultWebhookRetryPolicy()).Execute(context.Background(), dto.WebhookDeliveryMessage{DeliveryID: "delivery-1"}, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if string(body) != payload {
+		t.Errorf("expected the stored payload to be posted, got %s", body)
+	}
+	expected := "sha256=" + SignWebhookPayload(secret, headers[WebhookTimestampHeader], body)
+	if headers[WebhookSignatureHeader] != expected {
+		t.Errorf("expected signature %s, got %s", expected, headers[WebhookSignatureHeader])
+	}
+	if headers[WebhookEventHeader] != "video.completed" || headers[WebhookDeliveryHeader] != "delivery-1" {
+		t.Errorf("expected event and delivery headers, got %v", headers)
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	expected := "b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+
+	if got := SignWebhookPayload("secret", "1700000000", []byte("{}")); got != expected {
+		t.Errorf("expected signature %s, got %s", expected, got)
+	}
+}
@@ -35,6 +35,10 @@ func (u *DownloadVideoUsecase) Execute(ctx context.Context, videoID, userID stri
 		return nil, utils.NewUnauthorizedError("you don't have permission to download this video")
 	}
 
+	if video.Status == entities.VideoStatusExpired {
+		return nil, expiredError(video)
+	}
+
 	if video.Status != entities.VideoStatusCompleted {
 		return nil, utils.NewBadRequestError(fmt.Sprintf("video is not ready for download. Current status: %s", video.Status))
 	}
@@ -161,20 +161,29 @@ func TestDownloadVideoUsecase_Execute_VideoNotReady(t *testing.T) {
 	userID := "user-123"
 
 	tests := []struct {
-		name   string
-		status entities.VideoStatus
+		name         string
+		status       entities.VideoStatus
+		expectedCode int
 	}{
 		{
-			name:   "should reject pending video",
-			status: entities.VideoStatusPending,
+			name:         "should reject pending video",
+			status:       entities.VideoStatusPending,
+			expectedCode: 400,
 		},
 		{
-			name:   "should reject processing video",
-			status: entities.VideoStatusProcessing,
+			name:         "should reject processing video",
+			status:       entities.VideoStatusProcessing,
+			expectedCode: 400,
 		},
 		{
-			name:   "should reject failed video",
-			status: entities.VideoStatusFailed,
+			name:         "should reject failed video",
+			status:       entities.VideoStatusFailed,
+			expectedCode: 400,
+		},
+		{
+			name:         "should return 410 for expired video",
+			status:       entities.VideoStatusExpired,
+			expectedCode: 410,
 		},
 	}
 
@@ -213,8 +222,8 @@ func TestDownloadVideoUsecase_Execute_VideoNotReady(t *testing.T) {
 				t.Fatalf("expected HttpError, got %T", err)
 			}
 
-			if httpErr.StatusCode != 400 {
-				t.Errorf("expected status code 400, got %d", httpErr.StatusCode)
+			if httpErr.StatusCode != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
 			}
 		})
 	}
@@ -0,0 +1,43 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+)
+
+// uploadFrames stores every frame under prefix, followed by the manifest.
+func (u *ProcessVideoUsecase) uploadFrames(ctx context.Context, prefix string, frames []string, timestamps []float64, options entities.ExtractionOptions) (int64, error) {
+	manifest := entities.FrameManifest{
+		Format: options.Format,
+		Frames: make([]entities.FrameManifestEntry, 0, len(frames)),
+	}
+
+	var uploaded int64
+	for i, framePath := range frames {
+		name := entities.FrameName(i+1, options.Format)
+		size, err := u.uploadFile(ctx, prefix+name, framePath, options.Format.ContentType())
+		uploaded += size
+		if err != nil {
+			return uploaded, fmt.Errorf("failed to upload frame %d: %w", i+1, err)
+		}
+
+		entry := entities.FrameManifestEntry{Number: i + 1, Name: name, SizeBytes: size}
+		if i < len(timestamps) {
+			entry.TimestampSeconds = timestamps[i]
+		}
+		manifest.Frames = append(manifest.Frames, entry)
+	}
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return uploaded, fmt.Errorf("failed to encode frames manifest: %w", err)
+	}
+	if err := u.storageService.Upload(ctx, prefix+entities.FramesManifestName, data, "application/json"); err != nil {
+		return uploaded, fmt.Errorf("failed to upload frames manifest: %w", err)
+	}
+
+	return uploaded + int64(len(data)), nil
+}
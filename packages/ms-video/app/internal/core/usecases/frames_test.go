@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+)
+
+func TestProcessVideoUsecase_UploadFrames(t *testing.T) {
+	ctx := context.Background()
+	options := entities.ExtractionOptions{Format: entities.FrameFormatPNG}.WithDefaults()
+	_, frames := writeTestVideoFiles(t, []byte("fake video content"), [][]byte{[]byte("frame1"), []byte("frame2 data")})
+
+	t.Run("should store each frame and a manifest with their timestamps", func(t *testing.T) {
+		uploaded := map[string]string{}
+		var manifestData []byte
+		storageService := &mocks.MockStorageService{
+			UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+				uploaded[key] = contentType
+				return io.Copy(io.Discard, body)
+			},
+			UploadFunc: func(ctx context.Context, key string, data []byte, contentType string) error {
+				if key != "processed/user-123/video-123/frames/manifest.json" {
+					t.Errorf("expected manifest key, got '%s'", key)
+				}
+				manifestData = data
+				return nil
+			},
+		}
+		usecase := NewProcessVideoUsecase(&mocks.MockVideoRepository{}, storageService, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+		size, err := usecase.uploadFrames(ctx, "processed/user-123/video-123/frames/", frames, []float64{0, 2.5}, options)
+
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		if uploaded["processed/user-123/video-123/frames/frame_0001.png"] != "image/png" || uploaded["processed/user-123/video-123/frames/frame_0002.png"] != "image/png" {
+			t.Errorf("expected both frames stored as PNG, got %v", uploaded)
+		}
+
+		var manifest entities.FrameManifest
+		if err := json.Unmarshal(manifestData, &manifest); err != nil {
+			t.Fatalf("expected a JSON manifest, got %v", err)
+		}
+
+		expected := []entities.FrameManifestEntry{
+			{Number: 1, Name: "frame_0001.png", TimestampSeconds: 0, SizeBytes: 6},
+			{Number: 2, Name: "frame_0002.png", TimestampSeconds: 2.5, SizeBytes: 11},
+		}
+		if manifest.Format != entities.FrameFormatPNG || len(manifest.Frames) != len(expected) {
+			t.Fatalf("expected 2 PNG frames in manifest, got %+v", manifest)
+		}
+		for i := range expected {
+			if manifest.Frames[i] != expected[i] {
+				t.Errorf("expected frame %+v, got %+v", expected[i], manifest.Frames[i])
+			}
+		}
+
+		if size != 17+int64(len(manifestData)) {
+			t.Errorf("expected frames and manifest counted, got %d bytes", size)
+		}
+	})
+
+	t.Run("should not write the manifest when a frame fails to upload", func(t *testing.T) {
+		manifestWritten := false
+		storageService := &mocks.MockStorageService{
+			UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+				if strings.HasSuffix(key, "frame_0002.png") {
+					return 0, errors.New("upload failed")
+				}
+				return io.Copy(io.Discard, body)
+			},
+			UploadFunc: func(ctx context.Context, key string, data []byte, contentType string) error {
+				manifestWritten = true
+				return nil
+			},
+		}
+		usecase := NewProcessVideoUsecase(&mocks.MockVideoRepository{}, storageService, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+		size, err := usecase.uploadFrames(ctx, "processed/user-123/video-123/frames/", frames, []float64{0, 2.5}, options)
+
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if manifestWritten {
+			t.Error("expected no manifest for frames that were not all stored")
+		}
+
+		if size != 6 {
+			t.Errorf("expected the stored frame to be counted, got %d bytes", size)
+		}
+	})
+}
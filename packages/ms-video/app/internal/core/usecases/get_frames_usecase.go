@@ -0,0 +1,144 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+const (
+	DefaultFramesLimit = 100
+	MaxFramesLimit     = 1000
+)
+
+type GetFramesUsecase struct {
+	videoRepository ports.VideoRepository
+	storageService  ports.StorageService
+}
+
+func NewGetFramesUsecase(
+	videoRepository ports.VideoRepository,
+	storageService ports.StorageService,
+) *GetFramesUsecase {
+	return &GetFramesUsecase{
+		videoRepository: videoRepository,
+		storageService:  storageService,
+	}
+}
+
+// List returns a page of the frames manifest of the video.
+func (u *GetFramesUsecase) List(ctx context.Context, input dto.ListFramesInput) (*dto.ListFramesOutput, error) {
+	if input.Limit < 0 || input.Limit > MaxFramesLimit {
+		return nil, utils.NewBadRequestError(fmt.Sprintf("limit must be between 1 and %d", MaxFramesLimit))
+	}
+	limit := DefaultFramesLimit
+	if input.Limit > 0 {
+		limit = input.Limit
+	}
+
+	start := 0
+	if input.NextToken != "" {
+		number, err := strconv.Atoi(input.NextToken)
+		if err != nil || number < 1 {
+			return nil, utils.NewBadRequestError("invalid next_token")
+		}
+		start = number - 1
+	}
+
+	video, manifest, err := u.findFrames(ctx, input.VideoID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	start = min(start, len(manifest.Frames))
+	end := min(start+limit, len(manifest.Frames))
+	output := &dto.ListFramesOutput{
+		VideoID:     video.ID,
+		Format:      string(manifest.Format),
+		TotalFrames: len(manifest.Frames),
+		Frames:      make([]dto.FrameOutput, 0, end-start),
+	}
+	for _, frame := range manifest.Frames[start:end] {
+		output.Frames = append(output.Frames, dto.FrameOutput{
+			Number:           frame.Number,
+			TimestampSeconds: frame.TimestampSeconds,
+			SizeBytes:        frame.SizeBytes,
+		})
+	}
+	if end < len(manifest.Frames) {
+		output.NextToken = strconv.Itoa(manifest.Frames[end].Number)
+	}
+
+	return output, nil
+}
+
+// Get returns a presigned URL for frame number n of the video, counting
+// from 1.
+func (u *GetFramesUsecase) Get(ctx context.Context, videoID, userID string, number int) (*dto.FrameURLOutput, error) {
+	video, manifest, err := u.findFrames(ctx, videoID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	frame := manifest.Frame(number)
+	if frame == nil {
+		return nil, utils.NewNotFoundError(fmt.Sprintf("frame %d not found, the video has %d frames", number, len(manifest.Frames)))
+	}
+
+	expirationMinutes := 15
+	presignedURL, err := u.storageService.GetPresignedURL(ctx, video.FramesS3Prefix+frame.Name, expirationMinutes)
+	if err != nil {
+		return nil, utils.NewInternalServerError("failed to generate frame URL")
+	}
+
+	return &dto.FrameURLOutput{
+		VideoID:          video.ID,
+		Number:           frame.Number,
+		TimestampSeconds: frame.TimestampSeconds,
+		PresignedURL:     presignedURL,
+		ExpiresIn:        expirationMinutes * 60,
+	}, nil
+}
+
+// findFrames loads the video of the user and the manifest of its frames.
+func (u *GetFramesUsecase) findFrames(ctx context.Context, videoID, userID string) (*entities.Video, *entities.FrameManifest, error) {
+	video, err := u.videoRepository.FindByID(ctx, videoID)
+	if err != nil {
+		return nil, nil, utils.NewNotFoundError("video not found")
+	}
+
+	if video.UserID != userID {
+		return nil, nil, utils.NewUnauthorizedError("you don't have permission to view this video")
+	}
+
+	if video.Status == entities.VideoStatusExpired {
+		return nil, nil, expiredError(video)
+	}
+
+	if video.Status != entities.VideoStatusCompleted {
+		return nil, nil, utils.NewBadRequestError(fmt.Sprintf("video is not ready. Current status: %s", video.Status))
+	}
+
+	// Older videos only have their frames in the ZIP
+	if video.FramesS3Prefix == "" {
+		return nil, nil, utils.NewNotFoundError("frames are not available for this video, reprocess it to store them")
+	}
+
+	data, err := u.storageService.Download(ctx, video.FramesS3Prefix+entities.FramesManifestName)
+	if err != nil {
+		return nil, nil, utils.NewInternalServerError("failed to load frames")
+	}
+
+	var manifest entities.FrameManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, utils.NewInternalServerError("failed to load frames")
+	}
+
+	return video, &manifest, nil
+}
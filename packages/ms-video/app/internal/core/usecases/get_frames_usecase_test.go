@@ -0,0 +1,247 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func newTestFramesStorage(t *testing.T, frameCount int) *mocks.MockStorageService {
+	manifest := entities.FrameManifest{Format: entities.FrameFormatJPG}
+	for i := 1; i <= frameCount; i++ {
+		manifest.Frames = append(manifest.Frames, entities.FrameManifestEntry{
+			Number:           i,
+			Name:             entities.FrameName(i, entities.FrameFormatJPG),
+			TimestampSeconds: float64(i-1) * 0.5,
+			SizeBytes:        100,
+		})
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("failed to encode manifest: %v", err)
+	}
+
+	return &mocks.MockStorageService{
+		DownloadFunc: func(ctx context.Context, key string) ([]byte, error) {
+			if key != "processed/user-123/video-123/frames/manifest.json" {
+				t.Errorf("expected manifest key, got '%s'", key)
+			}
+			return data, nil
+		},
+		GetPresignedURLFunc: func(ctx context.Context, key string, expirationMinutes int) (string, error) {
+			return "https://s3.example.com/" + key + "?signed", nil
+		},
+	}
+}
+
+func newTestFramesVideo() *entities.Video {
+	return &entities.Video{
+		ID:             "video-123",
+		UserID:         "user-123",
+		Status:         entities.VideoStatusCompleted,
+		FramesS3Prefix: "processed/user-123/video-123/frames/",
+	}
+}
+
+func TestGetFramesUsecase_List(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		limit         int
+		nextToken     string
+		expectedFirst int
+		expectedCount int
+		expectedNext  string
+	}{
+		{
+			name:          "should return the first page with a token for the next",
+			limit:         2,
+			expectedFirst: 1,
+			expectedCount: 2,
+			expectedNext:  "3",
+		},
+		{
+			name:          "should continue from the token",
+			limit:         2,
+			nextToken:     "3",
+			expectedFirst: 3,
+			expectedCount: 2,
+			expectedNext:  "5",
+		},
+		{
+			name:          "should return the last page without a token",
+			limit:         2,
+			nextToken:     "5",
+			expectedFirst: 5,
+			expectedCount: 1,
+		},
+		{
+			name:          "should return the default page size",
+			expectedFirst: 1,
+			expectedCount: 5,
+		},
+		{
+			name:          "should return an empty page past the end",
+			nextToken:     "10",
+			expectedCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return newTestFramesVideo(), nil
+				},
+			}
+			usecase := NewGetFramesUsecase(videoRepo, newTestFramesStorage(t, 5))
+
+			output, err := usecase.List(ctx, dto.ListFramesInput{VideoID: "video-123", UserID: "user-123", Limit: tt.limit, NextToken: tt.nextToken})
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if output.TotalFrames != 5 || output.Format != "jpg" {
+				t.Errorf("expected 5 jpg frames in total, got %d %s", output.TotalFrames, output.Format)
+			}
+
+			if len(output.Frames) != tt.expectedCount {
+				t.Fatalf("expected %d frames, got %d", tt.expectedCount, len(output.Frames))
+			}
+
+			if tt.expectedCount > 0 && output.Frames[0].Number != tt.expectedFirst {
+				t.Errorf("expected page to start at frame %d, got %d", tt.expectedFirst, output.Frames[0].Number)
+			}
+
+			if output.NextToken != tt.expectedNext {
+				t.Errorf("expected next token '%s', got '%s'", tt.expectedNext, output.NextToken)
+			}
+		})
+	}
+}
+
+func TestGetFramesUsecase_Get_Success(t *testing.T) {
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return newTestFramesVideo(), nil
+		},
+	}
+	usecase := NewGetFramesUsecase(videoRepo, newTestFramesStorage(t, 5))
+
+	output, err := usecase.Get(context.Background(), "video-123", "user-123", 3)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expectedURL := "https://s3.example.com/processed/user-123/video-123/frames/frame_0003.jpg?signed"
+	if output.PresignedURL != expectedURL {
+		t.Errorf("expected URL '%s', got '%s'", expectedURL, output.PresignedURL)
+	}
+
+	if output.Number != 3 || output.TimestampSeconds != 1 {
+		t.Errorf("expected frame 3 at 1s, got %+v", output)
+	}
+}
+
+func TestGetFramesUsecase_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		userID       string
+		video        *entities.Video
+		findErr      error
+		number       int
+		limit        int
+		nextToken    string
+		expectedCode int
+	}{
+		{
+			name:         "should return 404 when video not found",
+			userID:       "user-123",
+			findErr:      errors.New("not found"),
+			expectedCode: 404,
+		},
+		{
+			name:         "should return 401 for another user's video",
+			userID:       "other-user",
+			video:        newTestFramesVideo(),
+			expectedCode: 401,
+		},
+		{
+			name:         "should return 400 while video is processing",
+			userID:       "user-123",
+			video:        &entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusProcessing},
+			expectedCode: 400,
+		},
+		{
+			name:         "should return 410 when the outputs have expired",
+			userID:       "user-123",
+			video:        &entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusExpired},
+			expectedCode: 410,
+		},
+		{
+			name:         "should return 404 when the frames were not stored",
+			userID:       "user-123",
+			video:        &entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusCompleted},
+			expectedCode: 404,
+		},
+		{
+			name:         "should return 404 for a frame past the end",
+			userID:       "user-123",
+			video:        newTestFramesVideo(),
+			number:       6,
+			expectedCode: 404,
+		},
+		{
+			name:         "should return 400 for a limit over the max",
+			userID:       "user-123",
+			video:        newTestFramesVideo(),
+			limit:        MaxFramesLimit + 1,
+			expectedCode: 400,
+		},
+		{
+			name:         "should return 400 for an invalid next token",
+			userID:       "user-123",
+			video:        newTestFramesVideo(),
+			nextToken:    "abc",
+			expectedCode: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return tt.video, tt.findErr
+				},
+			}
+			usecase := NewGetFramesUsecase(videoRepo, newTestFramesStorage(t, 5))
+
+			var err error
+			if tt.number > 0 {
+				_, err = usecase.Get(ctx, "video-123", tt.userID, tt.number)
+			} else {
+				_, err = usecase.List(ctx, dto.ListFramesInput{VideoID: "video-123", UserID: tt.userID, Limit: tt.limit, NextToken: tt.nextToken})
+			}
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+
+			if httpErr.StatusCode != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+			}
+		})
+	}
+}
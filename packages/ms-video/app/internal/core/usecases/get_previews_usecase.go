@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+var spriteNamePattern = regexp.MustCompile(`sprite_[0-9]+\.jpg`)
+
+type GetPreviewsUsecase struct {
+	videoRepository ports.VideoRepository
+	storageService  ports.StorageService
+}
+
+func NewGetPreviewsUsecase(
+	videoRepository ports.VideoRepository,
+	storageService ports.StorageService,
+) *GetPreviewsUsecase {
+	return &GetPreviewsUsecase{
+		videoRepository: videoRepository,
+		storageService:  storageService,
+	}
+}
+
+// Execute returns the thumbnails WebVTT with its sprite references replaced
+// by presigned URLs, so a player can use it as is.
+func (u *GetPreviewsUsecase) Execute(ctx context.Context, videoID, userID string) (*dto.PreviewsOutput, error) {
+	video, err := u.videoRepository.FindByID(ctx, videoID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("video not found")
+	}
+
+	if video.UserID != userID {
+		return nil, utils.NewUnauthorizedError("you don't have permission to view this video")
+	}
+
+	if video.Status == entities.VideoStatusExpired {
+		return nil, expiredError(video)
+	}
+
+	if video.Status != entities.VideoStatusCompleted {
+		return nil, utils.NewBadRequestError(fmt.Sprintf("video is not ready. Current status: %s", video.Status))
+	}
+
+	if video.PreviewsS3Prefix == "" {
+		return nil, utils.NewNotFoundError("previews are not available for this video")
+	}
+
+	vtt, err := u.storageService.Download(ctx, video.PreviewsS3Prefix+ThumbnailsVTTName)
+	if err != nil {
+		return nil, utils.NewInternalServerError("failed to load previews")
+	}
+
+	expirationMinutes := 60
+	sprites := spriteNamePattern.FindAllString(string(vtt), -1)
+	sprites = uniqueStrings(sprites)
+
+	replacements := make([]string, 0, len(sprites)*2)
+	spriteURLs := make([]string, len(sprites))
+	for i, sprite := range sprites {
+		spriteURL, err := u.storageService.GetPresignedURL(ctx, video.PreviewsS3Prefix+sprite, expirationMinutes)
+		if err != nil {
+			return nil, utils.NewInternalServerError("failed to generate preview URLs")
+		}
+		spriteURLs[i] = spriteURL
+		replacements = append(replacements, sprite, spriteURL)
+	}
+
+	return &dto.PreviewsOutput{
+		VideoID:         video.ID,
+		VTT:             strings.NewReplacer(replacements...).Replace(string(vtt)),
+		Sprites:         spriteURLs,
+		Columns:         SpriteColumns,
+		Rows:            SpriteRows,
+		ThumbnailWidth:  SpriteThumbnailWidth,
+		ThumbnailHeight: SpriteThumbnailHeight,
+		ExpiresIn:       expirationMinutes * 60,
+	}, nil
+}
+
+// uniqueStrings drops repeated values, keeping the first occurrence.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := values[:0]
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,125 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func TestGetPreviewsUsecase_Execute_Success(t *testing.T) {
+	ctx := context.Background()
+
+	video := &entities.Video{
+		ID:               "video-123",
+		UserID:           "user-123",
+		Status:           entities.VideoStatusCompleted,
+		PreviewsS3Prefix: "previews/user-123/video-123/",
+	}
+
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return video, nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		DownloadFunc: func(ctx context.Context, key string) ([]byte, error) {
+			if key != "previews/user-123/video-123/thumbnails.vtt" {
+				t.Errorf("expected thumbnails key, got '%s'", key)
+			}
+			return []byte(buildThumbnailsVTT([]float64{0, 1, 2})), nil
+		},
+		GetPresignedURLFunc: func(ctx context.Context, key string, expirationMinutes int) (string, error) {
+			return "https://s3.example.com/" + key + "?signed", nil
+		},
+	}
+
+	usecase := NewGetPreviewsUsecase(videoRepo, storageService)
+
+	output, err := usecase.Execute(ctx, video.ID, video.UserID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(output.Sprites) != 1 {
+		t.Fatalf("expected 1 sprite, got %d", len(output.Sprites))
+	}
+
+	expectedURL := "https://s3.example.com/previews/user-123/video-123/sprite_000.jpg?signed"
+	if output.Sprites[0] != expectedURL {
+		t.Errorf("expected sprite URL '%s', got '%s'", expectedURL, output.Sprites[0])
+	}
+
+	if strings.Count(output.VTT, expectedURL+"#xywh=") != 3 {
+		t.Errorf("expected every cue to point at the presigned sprite, got %q", output.VTT)
+	}
+
+	if output.Columns != SpriteColumns || output.Rows != SpriteRows {
+		t.Errorf("expected %dx%d grid, got %dx%d", SpriteColumns, SpriteRows, output.Columns, output.Rows)
+	}
+}
+
+func TestGetPreviewsUsecase_Execute_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		userID       string
+		video        *entities.Video
+		findErr      error
+		expectedCode int
+	}{
+		{
+			name:         "should return 404 when video not found",
+			userID:       "user-123",
+			findErr:      errors.New("not found"),
+			expectedCode: 404,
+		},
+		{
+			name:         "should return 401 for another user's video",
+			userID:       "other-user",
+			video:        &entities.Video{UserID: "user-123", Status: entities.VideoStatusCompleted, PreviewsS3Prefix: "previews/"},
+			expectedCode: 401,
+		},
+		{
+			name:         "should return 400 while video is processing",
+			userID:       "user-123",
+			video:        &entities.Video{UserID: "user-123", Status: entities.VideoStatusProcessing},
+			expectedCode: 400,
+		},
+		{
+			name:         "should return 404 when video has no previews",
+			userID:       "user-123",
+			video:        &entities.Video{UserID: "user-123", Status: entities.VideoStatusCompleted},
+			expectedCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return tt.video, tt.findErr
+				},
+			}
+
+			usecase := NewGetPreviewsUsecase(videoRepo, &mocks.MockStorageService{})
+
+			_, err := usecase.Execute(ctx, "video-123", tt.userID)
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+
+			if httpErr.StatusCode != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+			}
+		})
+	}
+}
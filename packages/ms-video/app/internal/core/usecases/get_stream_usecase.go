@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+var renditionPlaylistPattern = regexp.MustCompile(`^[0-9]+p\.m3u8$`)
+
+// GetStreamUsecase serves the HLS playlists of a video.
+type GetStreamUsecase struct {
+	videoRepository ports.VideoRepository
+	storageService  ports.StorageService
+}
+
+func NewGetStreamUsecase(
+	videoRepository ports.VideoRepository,
+	storageService ports.StorageService,
+) *GetStreamUsecase {
+	return &GetStreamUsecase{
+		videoRepository: videoRepository,
+		storageService:  storageService,
+	}
+}
+
+// MasterPlaylist returns the master playlist with rendition URIs relative to
+// GET /video/{id}/stream, so they resolve to GET /video/{id}/stream/{playlist}.
+func (u *GetStreamUsecase) MasterPlaylist(ctx context.Context, videoID, userID string) (string, error) {
+	video, err := u.findStreamableVideo(ctx, videoID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	playlist, err := u.storageService.Download(ctx, video.HLSS3Prefix+HLSMasterPlaylistName)
+	if err != nil {
+		return "", utils.NewInternalServerError("failed to load stream")
+	}
+
+	return rewritePlaylistURIs(string(playlist), func(uri string) (string, error) {
+		return "stream/" + uri, nil
+	})
+}
+
+// RenditionPlaylist returns a rendition playlist with presigned segment URLs.
+func (u *GetStreamUsecase) RenditionPlaylist(ctx context.Context, videoID, userID, name string) (string, error) {
+	if !renditionPlaylistPattern.MatchString(name) {
+		return "", utils.NewNotFoundError("playlist not found")
+	}
+
+	video, err := u.findStreamableVideo(ctx, videoID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	playlist, err := u.storageService.Download(ctx, video.HLSS3Prefix+name)
+	if err != nil {
+		return "", utils.NewNotFoundError("playlist not found")
+	}
+
+	expirationMinutes := 60
+	return rewritePlaylistURIs(string(playlist), func(uri string) (string, error) {
+		segmentURL, err := u.storageService.GetPresignedURL(ctx, video.HLSS3Prefix+uri, expirationMinutes)
+		if err != nil {
+			return "", utils.NewInternalServerError("failed to generate segment URLs")
+		}
+		return segmentURL, nil
+	})
+}
+
+func (u *GetStreamUsecase) findStreamableVideo(ctx context.Context, videoID, userID string) (*entities.Video, error) {
+	video, err := u.videoRepository.FindByID(ctx, videoID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("video not found")
+	}
+
+	if video.UserID != userID {
+		return nil, utils.NewUnauthorizedError("you don't have permission to view this video")
+	}
+
+	if video.Status == entities.VideoStatusExpired {
+		return nil, expiredError(video)
+	}
+
+	if video.Status != entities.VideoStatusCompleted {
+		return nil, utils.NewBadRequestError(fmt.Sprintf("video is not ready. Current status: %s", video.Status))
+	}
+
+	if video.HLSS3Prefix == "" {
+		return nil, utils.NewNotFoundError("stream is not available for this video")
+	}
+
+	return video, nil
+}
+
+// rewritePlaylistURIs replaces every URI line of an m3u8 playlist; tag and
+// blank lines are kept as they are.
+func rewritePlaylistURIs(playlist string, rewrite func(uri string) (string, error)) (string, error) {
+	var out strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(playlist))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			rewritten, err := rewrite(line)
+			if err != nil {
+				return "", err
+			}
+			line = rewritten
+		}
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func newStreamableVideo() *entities.Video {
+	return &entities.Video{
+		ID:          "video-123",
+		UserID:      "user-123",
+		Status:      entities.VideoStatusCompleted,
+		HLSS3Prefix: "hls/user-123/video-123/",
+	}
+}
+
+func TestGetStreamUsecase_MasterPlaylist(t *testing.T) {
+	ctx := context.Background()
+	video := newStreamableVideo()
+
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return video, nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		DownloadFunc: func(ctx context.Context, key string) ([]byte, error) {
+			if key != "hls/user-123/video-123/master.m3u8" {
+				t.Errorf("expected master playlist key, got '%s'", key)
+			}
+			return []byte(buildHLSMasterPlaylist(entities.DefaultHLSLadder()[1:], 1280, 720, true)), nil
+		},
+	}
+
+	usecase := NewGetStreamUsecase(videoRepo, storageService)
+
+	playlist, err := usecase.MasterPlaylist(ctx, video.ID, video.UserID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !strings.Contains(playlist, "\nstream/720p.m3u8\n") || !strings.Contains(playlist, "\nstream/360p.m3u8\n") {
+		t.Errorf("expected rendition URIs to point at the stream endpoint, got %q", playlist)
+	}
+
+	if !strings.HasPrefix(playlist, "#EXTM3U\n") {
+		t.Errorf("expected tags to be kept, got %q", playlist)
+	}
+}
+
+func TestGetStreamUsecase_RenditionPlaylist(t *testing.T) {
+	ctx := context.Background()
+	video := newStreamableVideo()
+
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return video, nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		DownloadFunc: func(ctx context.Context, key string) ([]byte, error) {
+			return []byte("#EXTM3U\n#EXTINF:6.0,\n720p_0000.ts\n#EXTINF:2.5,\n720p_0001.ts\n#EXT-X-ENDLIST\n"), nil
+		},
+		GetPresignedURLFunc: func(ctx context.Context, key string, expirationMinutes int) (string, error) {
+			return "https://s3.example.com/" + key + "?signed", nil
+		},
+	}
+
+	usecase := NewGetStreamUsecase(videoRepo, storageService)
+
+	playlist, err := usecase.RenditionPlaylist(ctx, video.ID, video.UserID, "720p.m3u8")
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for _, segment := range []string{"720p_0000.ts", "720p_0001.ts"} {
+		expectedURL := "https://s3.example.com/hls/user-123/video-123/" + segment + "?signed"
+		if !strings.Contains(playlist, expectedURL) {
+			t.Errorf("expected presigned URL %q in playlist, got %q", expectedURL, playlist)
+		}
+	}
+
+	if !strings.HasSuffix(playlist, "#EXT-X-ENDLIST\n") {
+		t.Errorf("expected tags to be kept, got %q", playlist)
+	}
+}
+
+func TestGetStreamUsecase_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		userID       string
+		playlist     string
+		prepare      func(video *entities.Video)
+		findErr      error
+		expectedCode int
+	}{
+		{
+			name:         "should return 404 when video not found",
+			userID:       "user-123",
+			findErr:      errors.New("not found"),
+			expectedCode: 404,
+		},
+		{
+			name:         "should reject other users",
+			userID:       "other-user",
+			expectedCode: 401,
+		},
+		{
+			name:   "should reject videos that are not completed",
+			userID: "user-123",
+			prepare: func(video *entities.Video) {
+				video.Status = entities.VideoStatusProcessing
+			},
+			expectedCode: 400,
+		},
+		{
+			name:   "should return 404 when no stream was generated",
+			userID: "user-123",
+			prepare: func(video *entities.Video) {
+				video.HLSS3Prefix = ""
+			},
+			expectedCode: 404,
+		},
+		{
+			name:         "should reject playlist names outside the ladder",
+			userID:       "user-123",
+			playlist:     "../master.m3u8",
+			expectedCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := newStreamableVideo()
+			if tt.prepare != nil {
+				tt.prepare(video)
+			}
+
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					if tt.findErr != nil {
+						return nil, tt.findErr
+					}
+					return video, nil
+				},
+			}
+
+			usecase := NewGetStreamUsecase(videoRepo, &mocks.MockStorageService{})
+
+			var err error
+			if tt.playlist != "" {
+				_, err = usecase.RenditionPlaylist(ctx, video.ID, tt.userID, tt.playlist)
+			} else {
+				_, err = usecase.MasterPlaylist(ctx, video.ID, tt.userID)
+			}
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+
+			if httpErr.StatusCode != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+			}
+		})
+	}
+}
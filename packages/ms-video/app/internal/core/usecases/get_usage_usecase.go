@@ -0,0 +1,46 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+type GetUsageUsecase struct {
+	usageRepository ports.UsageRepository
+	usageLimits     entities.UsageLimits
+}
+
+func NewGetUsageUsecase(
+	usageRepository ports.UsageRepository,
+	usageLimits entities.UsageLimits,
+) *GetUsageUsecase {
+	return &GetUsageUsecase{
+		usageRepository: usageRepository,
+		usageLimits:     usageLimits,
+	}
+}
+
+func (u *GetUsageUsecase) Execute(ctx context.Context, userID string) (*dto.UsageOutput, error) {
+	usage, err := u.usageRepository.Get(ctx, userID)
+	if err != nil {
+		return nil, utils.NewInternalServerError("failed to load usage")
+	}
+
+	now := time.Now()
+	windowEnd := time.Unix(entities.UploadWindowStart(now), 0).Add(time.Hour).UTC()
+
+	return &dto.UsageOutput{
+		StoredBytes:       usage.StoredBytes,
+		MaxStoredBytes:    u.usageLimits.MaxStoredBytes,
+		VideoCount:        usage.VideoCount,
+		MaxVideos:         u.usageLimits.MaxVideos,
+		UploadsThisHour:   usage.UploadsInWindow(now),
+		MaxUploadsPerHour: u.usageLimits.MaxUploadsPerHour,
+		WindowResetsAt:    windowEnd.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
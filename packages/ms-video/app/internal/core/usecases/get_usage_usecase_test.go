@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func TestGetUsageUsecase_Execute(t *testing.T) {
+	ctx := context.Background()
+	limits := entities.UsageLimits{MaxStoredBytes: 1000, MaxVideos: 10, MaxUploadsPerHour: 5}
+	now := time.Now()
+
+	tests := []struct {
+		name            string
+		usage           *entities.Usage
+		getErr          error
+		expectedCode    int
+		expectedUploads int
+	}{
+		{
+			name:            "should report usage and limits",
+			usage:           &entities.Usage{UserID: "user-123", StoredBytes: 400, VideoCount: 2, UploadWindow: entities.UploadWindowStart(now), WindowUploads: 3},
+			expectedUploads: 3,
+		},
+		{
+			name:  "should not report uploads of a previous hour",
+			usage: &entities.Usage{UserID: "user-123", StoredBytes: 400, VideoCount: 2, UploadWindow: entities.UploadWindowStart(now.Add(-time.Hour)), WindowUploads: 3},
+		},
+		{
+			name:         "should return 500 when usage cannot be loaded",
+			getErr:       errors.New("dynamodb error"),
+			expectedCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usageRepo := &mocks.MockUsageRepository{
+				GetFunc: func(ctx context.Context, userID string) (*entities.Usage, error) {
+					return tt.usage, tt.getErr
+				},
+			}
+
+			output, err := NewGetUsageUsecase(usageRepo, limits).Execute(ctx, "user-123")
+
+			if tt.expectedCode != 0 {
+				httpErr, ok := err.(*utils.HttpError)
+				if !ok {
+					t.Fatalf("expected HttpError, got %T", err)
+				}
+				if httpErr.StatusCode != tt.expectedCode {
+					t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if output.StoredBytes != 400 || output.MaxStoredBytes != 1000 || output.VideoCount != 2 || output.MaxVideos != 10 {
+				t.Errorf("expected stored bytes and videos with their limits, got %+v", output)
+			}
+			if output.UploadsThisHour != tt.expectedUploads || output.MaxUploadsPerHour != 5 {
+				t.Errorf("expected %d uploads this hour, got %+v", tt.expectedUploads, output)
+			}
+			if output.WindowResetsAt == "" {
+				t.Error("expected the window reset time to be set")
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+const (
+	HLSMasterPlaylistName = "master.m3u8"
+	HLSSegmentSeconds     = 6
+)
+
+// HLSLadderFromEnv reads the HLS_LADDER setting, falling back to the default
+// ladder when it is unset or invalid.
+func HLSLadderFromEnv() []entities.HLSRendition {
+	value := utils.GetEnv("HLS_LADDER", "")
+	if value == "" {
+		return entities.DefaultHLSLadder()
+	}
+
+	ladder, err := entities.ParseHLSLadder(value)
+	if err != nil {
+		log.Printf("Invalid HLS_LADDER %q, using default ladder: %v", value, err)
+		return entities.DefaultHLSLadder()
+	}
+	return ladder
+}
+
+// createHLS transcodes the video into the renditions of the ladder that fit
+// it and uploads them under prefix.
+func (u *ProcessVideoUsecase) createHLS(ctx context.Context, prefix, workDir, videoPath string, info *entities.MediaInfo) (int64, error) {
+	if info == nil {
+		return 0, fmt.Errorf("video could not be probed")
+	}
+	source := info.VideoStream()
+	if source == nil {
+		return 0, fmt.Errorf("video has no video stream")
+	}
+	hasAudio := false
+	for _, stream := range info.Streams {
+		if stream.CodecType == entities.StreamTypeAudio {
+			hasAudio = true
+		}
+	}
+
+	outputDir := filepath.Join(workDir, "hls")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return 0, fmt.Errorf("failed to create hls dir: %w", err)
+	}
+
+	renditions := entities.HLSRenditionsFor(u.hlsLadder, source.Height)
+	for _, rendition := range renditions {
+		log.Printf("Transcoding %s rendition of %s", rendition.Name(), prefix)
+		err := buildHLSCommand(ctx, videoPath, outputDir, rendition, hasAudio).
+			ErrorToStdOut().
+			Run()
+		if err != nil {
+			return 0, fmt.Errorf("failed to transcode %s rendition: %w", rendition.Name(), err)
+		}
+	}
+
+	master := buildHLSMasterPlaylist(renditions, source.Width, source.Height, hasAudio)
+	if err := ioutil.WriteFile(filepath.Join(outputDir, HLSMasterPlaylistName), []byte(master), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write master playlist: %w", err)
+	}
+
+	files, err := ioutil.ReadDir(outputDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read hls directory: %w", err)
+	}
+
+	var uploaded int64
+	for _, file := range files {
+		size, err := u.uploadFile(ctx, prefix+file.Name(), filepath.Join(outputDir, file.Name()), hlsContentType(file.Name()))
+		uploaded += size
+		if err != nil {
+			return uploaded, fmt.Errorf("failed to upload %s: %w", file.Name(), err)
+		}
+	}
+
+	return uploaded, nil
+}
+
+// uploadFile uploads the file at path and returns its size.
+func (u *ProcessVideoUsecase) uploadFile(ctx context.Context, key, path, contentType string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return u.storageService.UploadStream(ctx, key, file, contentType)
+}
+
+func buildHLSCommand(ctx context.Context, videoPath, outputDir string, rendition entities.HLSRendition, hasAudio bool) *ffmpeg.Stream {
+	name := rendition.Name()
+	args := ffmpeg.KwArgs{
+		"vf":                   fmt.Sprintf("scale=-2:%d", rendition.Height),
+		"c:v":                  "libx264",
+		"preset":               "veryfast",
+		"profile:v":            "main",
+		"b:v":                  fmt.Sprintf("%dk", rendition.VideoBitrateKbps),
+		"maxrate":              fmt.Sprintf("%dk", rendition.VideoBitrateKbps*107/100),
+		"bufsize":              fmt.Sprintf("%dk", rendition.VideoBitrateKbps*3/2),
+		"sc_threshold":         "0",
+		"force_key_frames":     fmt.Sprintf("expr:gte(t,n_forced*%d)", HLSSegmentSeconds),
+		"f":                    "hls",
+		"hls_time":             fmt.Sprint(HLSSegmentSeconds),
+		"hls_playlist_type":    "vod",
+		"hls_segment_filename": filepath.Join(outputDir, name+"_%04d.ts"),
+	}
+	if hasAudio {
+		args["c:a"] = "aac"
+		args["b:a"] = fmt.Sprintf("%dk", rendition.AudioBitrateKbps)
+		args["ac"] = "2"
+	} else {
+		args["an"] = ""
+	}
+
+	return ffmpeg.OutputContext(ctx, []*ffmpeg.Stream{ffmpeg.Input(videoPath)}, filepath.Join(outputDir, name+".m3u8"), args).
+		OverWriteOutput()
+}
+
+// buildHLSMasterPlaylist lists the renditions, highest first.
+func buildHLSMasterPlaylist(renditions []entities.HLSRendition, sourceWidth, sourceHeight int, hasAudio bool) string {
+	var playlist strings.Builder
+	playlist.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+
+	for _, rendition := range renditions {
+		bandwidth := rendition.VideoBitrateKbps * 1000
+		if hasAudio {
+			bandwidth += rendition.AudioBitrateKbps * 1000
+		}
+
+		width := 0
+		if sourceHeight > 0 {
+			width = (sourceWidth*rendition.Height/sourceHeight + 1) &^ 1
+		}
+
+		fmt.Fprintf(&playlist, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d\n%s.m3u8\n",
+			bandwidth, width, rendition.Height, rendition.Name())
+	}
+
+	return playlist.String()
+}
+
+func hlsContentType(fileName string) string {
+	switch filepath.Ext(fileName) {
+	case ".m3u8":
+		return "application/vnd.apple.mpegurl"
+	case ".ts":
+		return "video/mp2t"
+	}
+	return "application/octet-stream"
+}
@@ -0,0 +1,65 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+)
+
+func TestBuildHLSCommand(t *testing.T) {
+	rendition := entities.HLSRendition{Height: 720, VideoBitrateKbps: 2800, AudioBitrateKbps: 128}
+
+	tests := []struct {
+		name        string
+		hasAudio    bool
+		expected    []string
+		notExpected []string
+	}{
+		{
+			name:     "should transcode video and audio into hls segments",
+			hasAudio: true,
+			expected: []string{"-vf scale=-2:720", "-c:v libx264", "-b:v 2800k", "-c:a aac", "-b:a 128k", "-f hls", "-hls_playlist_type vod", "720p_%04d.ts", "720p.m3u8"},
+		},
+		{
+			name:        "should drop audio when the source has none",
+			hasAudio:    false,
+			expected:    []string{"-an"},
+			notExpected: []string{"-c:a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := strings.Join(buildHLSCommand(context.Background(), "input.mp4", "out", rendition, tt.hasAudio).GetArgs(), " ")
+
+			for _, part := range tt.expected {
+				if !strings.Contains(args, part) {
+					t.Errorf("expected ffmpeg args to contain %q, got %q", part, args)
+				}
+			}
+			for _, part := range tt.notExpected {
+				if strings.Contains(args, part) {
+					t.Errorf("expected ffmpeg args not to contain %q, got %q", part, args)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildHLSMasterPlaylist(t *testing.T) {
+	renditions := []entities.HLSRendition{
+		{Height: 720, VideoBitrateKbps: 2800, AudioBitrateKbps: 128},
+		{Height: 360, VideoBitrateKbps: 800, AudioBitrateKbps: 96},
+	}
+
+	playlist := buildHLSMasterPlaylist(renditions, 1920, 1080, true)
+
+	expected := "#EXTM3U\n#EXT-X-VERSION:3\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=2928000,RESOLUTION=1280x720\n720p.m3u8\n" +
+		"#EXT-X-STREAM-INF:BANDWIDTH=896000,RESOLUTION=640x360\n360p.m3u8\n"
+	if playlist != expected {
+		t.Errorf("expected playlist %q, got %q", expected, playlist)
+	}
+}
@@ -2,9 +2,18 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
 )
 
 type ListVideosUsecase struct {
@@ -17,27 +26,87 @@ func NewListVideosUsecase(videoRepository ports.VideoRepository) *ListVideosUsec
 	}
 }
 
-func (u *ListVideosUsecase) Execute(ctx context.Context, userID string) (*dto.ListVideosOutput, error) {
-	videos, err := u.videoRepository.FindByUserID(ctx, userID)
+func (u *ListVideosUsecase) Execute(ctx context.Context, input dto.ListVideosInput) (*dto.ListVideosOutput, error) {
+	query, err := buildVideoListQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := u.videoRepository.FindByUserID(ctx, input.UserID, query)
+	if errors.Is(err, ports.ErrInvalidCursor) {
+		return nil, utils.NewBadRequestError("invalid next_token")
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	videoOutputs := make([]dto.VideoOutput, len(videos))
-	for i, video := range videos {
-		videoOutputs[i] = dto.VideoOutput{
-			ID:              video.ID,
-			OriginalName:    video.OriginalName,
-			Status:          string(video.Status),
-			ProgressPercent: video.ProgressPercent,
-			FileSize:        video.FileSize,
-			ErrorMessage:    video.ErrorMessage,
-			CreatedAt:       video.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:       video.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
-		}
+	videoOutputs := make([]dto.VideoOutput, len(page.Videos))
+	for i, video := range page.Videos {
+		videoOutputs[i] = newVideoOutput(video)
 	}
 
 	return &dto.ListVideosOutput{
-		Videos: videoOutputs,
+		Videos:    videoOutputs,
+		NextToken: page.NextCursor,
 	}, nil
 }
+
+func newVideoOutput(video *entities.Video) dto.VideoOutput {
+	output := dto.VideoOutput{
+		ID:               video.ID,
+		OriginalName:     video.OriginalName,
+		Status:           string(video.Status),
+		ProgressPercent:  video.ProgressPercent,
+		FileSize:         video.FileSize,
+		ErrorMessage:     video.ErrorMessage,
+		CreatedAt:        video.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt:        video.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Pinned:           video.Pinned,
+		ExpirationReason: video.ExpirationReason,
+		Metadata:         video.Metadata,
+	}
+	if video.RawDeletedAt != nil {
+		output.RawDeletedAt = video.RawDeletedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return output
+}
+
+// buildVideoListQuery validates the listing parameters and fills in the
+// defaults: the newest videos first, DefaultListLimit at a time.
+func buildVideoListQuery(input dto.ListVideosInput) (ports.VideoListQuery, error) {
+	query := ports.VideoListQuery{
+		Status: entities.VideoStatus(input.Status),
+		SortBy: ports.VideoSortCreatedAt,
+		Limit:  DefaultListLimit,
+		Cursor: input.NextToken,
+	}
+
+	if input.Limit < 0 || input.Limit > MaxListLimit {
+		return query, utils.NewBadRequestError(fmt.Sprintf("limit must be between 1 and %d", MaxListLimit))
+	}
+	if input.Limit > 0 {
+		query.Limit = input.Limit
+	}
+
+	if query.Status != "" && !query.Status.IsValid() {
+		return query, utils.NewBadRequestError(fmt.Sprintf("unknown status %q", input.Status))
+	}
+
+	switch ports.VideoSortField(input.Sort) {
+	case "", ports.VideoSortCreatedAt:
+	case ports.VideoSortUpdatedAt:
+		query.SortBy = ports.VideoSortUpdatedAt
+	default:
+		return query, utils.NewBadRequestError("sort must be created_at or updated_at")
+	}
+
+	switch input.Order {
+	case "", "desc":
+	case "asc":
+		query.Ascending = true
+	default:
+		return query, utils.NewBadRequestError("order must be asc or desc")
+	}
+
+	return query, nil
+}
@@ -3,11 +3,15 @@ package usecases
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
 )
 
 func TestListVideosUsecase_Execute_Success(t *testing.T) {
@@ -49,17 +53,17 @@ func TestListVideosUsecase_Execute_Success(t *testing.T) {
 	}
 
 	videoRepo := &mocks.MockVideoRepository{
-		FindByUserIDFunc: func(ctx context.Context, uid string) ([]*entities.Video, error) {
+		FindByUserIDFunc: func(ctx context.Context, uid string, query ports.VideoListQuery) (*ports.VideoPage, error) {
 			if uid != userID {
 				t.Errorf("expected userID '%s', got '%s'", userID, uid)
 			}
-			return videos, nil
+			return &ports.VideoPage{Videos: videos}, nil
 		},
 	}
 
 	usecase := NewListVideosUsecase(videoRepo)
 
-	output, err := usecase.Execute(ctx, userID)
+	output, err := usecase.Execute(ctx, dto.ListVideosInput{UserID: userID})
 
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -117,14 +121,14 @@ func TestListVideosUsecase_Execute_EmptyList(t *testing.T) {
 	userID := "user-with-no-videos"
 
 	videoRepo := &mocks.MockVideoRepository{
-		FindByUserIDFunc: func(ctx context.Context, uid string) ([]*entities.Video, error) {
-			return []*entities.Video{}, nil
+		FindByUserIDFunc: func(ctx context.Context, uid string, query ports.VideoListQuery) (*ports.VideoPage, error) {
+			return &ports.VideoPage{Videos: []*entities.Video{}}, nil
 		},
 	}
 
 	usecase := NewListVideosUsecase(videoRepo)
 
-	output, err := usecase.Execute(ctx, userID)
+	output, err := usecase.Execute(ctx, dto.ListVideosInput{UserID: userID})
 
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -144,14 +148,14 @@ func TestListVideosUsecase_Execute_RepositoryError(t *testing.T) {
 	userID := "user-123"
 
 	videoRepo := &mocks.MockVideoRepository{
-		FindByUserIDFunc: func(ctx context.Context, uid string) ([]*entities.Video, error) {
+		FindByUserIDFunc: func(ctx context.Context, uid string, query ports.VideoListQuery) (*ports.VideoPage, error) {
 			return nil, errors.New("database connection failed")
 		},
 	}
 
 	usecase := NewListVideosUsecase(videoRepo)
 
-	output, err := usecase.Execute(ctx, userID)
+	output, err := usecase.Execute(ctx, dto.ListVideosInput{UserID: userID})
 
 	if err == nil {
 		t.Fatal("expected error from repository, got nil")
@@ -183,14 +187,14 @@ func TestListVideosUsecase_Execute_WithFailedVideo(t *testing.T) {
 	}
 
 	videoRepo := &mocks.MockVideoRepository{
-		FindByUserIDFunc: func(ctx context.Context, uid string) ([]*entities.Video, error) {
-			return videos, nil
+		FindByUserIDFunc: func(ctx context.Context, uid string, query ports.VideoListQuery) (*ports.VideoPage, error) {
+			return &ports.VideoPage{Videos: videos}, nil
 		},
 	}
 
 	usecase := NewListVideosUsecase(videoRepo)
 
-	output, err := usecase.Execute(ctx, userID)
+	output, err := usecase.Execute(ctx, dto.ListVideosInput{UserID: userID})
 
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -266,14 +270,14 @@ func TestListVideosUsecase_Execute_MultipleStatuses(t *testing.T) {
 	}
 
 	videoRepo := &mocks.MockVideoRepository{
-		FindByUserIDFunc: func(ctx context.Context, uid string) ([]*entities.Video, error) {
-			return videos, nil
+		FindByUserIDFunc: func(ctx context.Context, uid string, query ports.VideoListQuery) (*ports.VideoPage, error) {
+			return &ports.VideoPage{Videos: videos}, nil
 		},
 	}
 
 	usecase := NewListVideosUsecase(videoRepo)
 
-	output, err := usecase.Execute(ctx, userID)
+	output, err := usecase.Execute(ctx, dto.ListVideosInput{UserID: userID})
 
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -304,3 +308,95 @@ func TestListVideosUsecase_Execute_MultipleStatuses(t *testing.T) {
 		t.Errorf("expected 1 failed video, got %d", statusCounts[string(entities.VideoStatusFailed)])
 	}
 }
+
+func TestListVideosUsecase_Execute_Query(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		input         dto.ListVideosInput
+		expected      ports.VideoListQuery
+		expectedError string
+	}{
+		{
+			name:     "should list the newest videos first by default",
+			input:    dto.ListVideosInput{UserID: "user-123"},
+			expected: ports.VideoListQuery{SortBy: ports.VideoSortCreatedAt, Limit: DefaultListLimit},
+		},
+		{
+			name:     "should pass the filter, sort and cursor",
+			input:    dto.ListVideosInput{UserID: "user-123", Limit: 5, NextToken: "token", Status: "failed", Sort: "updated_at", Order: "asc"},
+			expected: ports.VideoListQuery{Status: entities.VideoStatusFailed, SortBy: ports.VideoSortUpdatedAt, Ascending: true, Limit: 5, Cursor: "token"},
+		},
+		{
+			name:          "should reject a limit above the maximum",
+			input:         dto.ListVideosInput{UserID: "user-123", Limit: MaxListLimit + 1},
+			expectedError: "limit must be between 1 and 100",
+		},
+		{
+			name:          "should reject an unknown status",
+			input:         dto.ListVideosInput{UserID: "user-123", Status: "done"},
+			expectedError: `unknown status "done"`,
+		},
+		{
+			name:          "should reject an unknown sort",
+			input:         dto.ListVideosInput{UserID: "user-123", Sort: "name"},
+			expectedError: "sort must be created_at or updated_at",
+		},
+		{
+			name:          "should reject an unknown order",
+			input:         dto.ListVideosInput{UserID: "user-123", Order: "up"},
+			expectedError: "order must be asc or desc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *ports.VideoListQuery
+			videoRepo := &mocks.MockVideoRepository{
+				FindByUserIDFunc: func(ctx context.Context, uid string, query ports.VideoListQuery) (*ports.VideoPage, error) {
+					received = &query
+					return &ports.VideoPage{NextCursor: "next"}, nil
+				},
+			}
+
+			output, err := NewListVideosUsecase(videoRepo).Execute(ctx, tt.input)
+
+			if tt.expectedError != "" {
+				var httpErr *utils.HttpError
+				if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest || httpErr.Message != tt.expectedError {
+					t.Fatalf("expected bad request %q, got %v", tt.expectedError, err)
+				}
+				if received != nil {
+					t.Error("expected repository not to be queried")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if received == nil || *received != tt.expected {
+				t.Errorf("expected query %+v, got %+v", tt.expected, received)
+			}
+			if output.NextToken != "next" {
+				t.Errorf("expected next token 'next', got '%s'", output.NextToken)
+			}
+		})
+	}
+}
+
+func TestListVideosUsecase_Execute_InvalidNextToken(t *testing.T) {
+	videoRepo := &mocks.MockVideoRepository{
+		FindByUserIDFunc: func(ctx context.Context, uid string, query ports.VideoListQuery) (*ports.VideoPage, error) {
+			return nil, ports.ErrInvalidCursor
+		},
+	}
+
+	_, err := NewListVideosUsecase(videoRepo).Execute(context.Background(), dto.ListVideosInput{UserID: "user-123", NextToken: "bogus"})
+
+	var httpErr *utils.HttpError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected bad request, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// pinVideoAttempts bounds how often pinning is retried.
+const pinVideoAttempts = 3
+
+type PinVideoUsecase struct {
+	videoRepository ports.VideoRepository
+}
+
+func NewPinVideoUsecase(videoRepository ports.VideoRepository) *PinVideoUsecase {
+	return &PinVideoUsecase{
+		videoRepository: videoRepository,
+	}
+}
+
+// Execute pins or unpins the video.
+func (u *PinVideoUsecase) Execute(ctx context.Context, videoID, userID string, pinned bool) (*dto.VideoOutput, error) {
+	video, err := u.videoRepository.FindByID(ctx, videoID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("video not found")
+	}
+
+	if video.UserID != userID {
+		return nil, utils.NewUnauthorizedError("you don't have permission to pin this video")
+	}
+
+	for attempt := 1; ; attempt++ {
+		if pinned && video.Status == entities.VideoStatusExpired {
+			return nil, utils.NewConflictError("video outputs have already expired, reprocess the video to pin it")
+		}
+		if video.Pinned == pinned {
+			break
+		}
+
+		video.SetPinned(pinned)
+		err := u.videoRepository.Update(ctx, video)
+		var conflict *ports.VersionConflictError
+		if !errors.As(err, &conflict) {
+			if err != nil {
+				return nil, utils.NewInternalServerError("failed to pin video")
+			}
+			break
+		}
+
+		if attempt == pinVideoAttempts {
+			return nil, utils.NewConflictError("video is being updated, try again")
+		}
+
+		video, err = u.videoRepository.FindByID(ctx, videoID)
+		if errors.Is(err, ports.ErrVideoNotFound) {
+			return nil, utils.NewNotFoundError("video not found")
+		} else if err != nil {
+			return nil, utils.NewInternalServerError("failed to pin video")
+		}
+	}
+
+	output := newVideoOutput(video)
+	return &output, nil
+}
@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func TestPinVideoUsecase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		video          *entities.Video
+		findErr        error
+		userID         string
+		pinned         bool
+		expectedCode   int
+		expectUpdate   bool
+		expectedPinned bool
+	}{
+		{
+			name:           "should pin a completed video",
+			video:          &entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusCompleted},
+			userID:         "user-123",
+			pinned:         true,
+			expectUpdate:   true,
+			expectedPinned: true,
+		},
+		{
+			name:         "should unpin a pinned video",
+			video:        &entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusCompleted, Pinned: true},
+			userID:       "user-123",
+			expectUpdate: true,
+		},
+		{
+			name:           "should not save a video that is already pinned",
+			video:          &entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusCompleted, Pinned: true},
+			userID:         "user-123",
+			pinned:         true,
+			expectedPinned: true,
+		},
+		{
+			name:         "should return 409 when pinning an expired video",
+			video:        &entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusExpired},
+			userID:       "user-123",
+			pinned:       true,
+			expectedCode: 409,
+		},
+		{
+			name:         "should return 401 for another user's video",
+			video:        &entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusCompleted},
+			userID:       "other-user",
+			pinned:       true,
+			expectedCode: 401,
+		},
+		{
+			name:         "should return 404 when video not found",
+			findErr:      ports.ErrVideoNotFound,
+			userID:       "user-123",
+			pinned:       true,
+			expectedCode: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := false
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return tt.video, tt.findErr
+				},
+				UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+					updated = true
+					return nil
+				},
+			}
+
+			output, err := NewPinVideoUsecase(videoRepo).Execute(ctx, "video-123", tt.userID, tt.pinned)
+
+			if tt.expectedCode != 0 {
+				httpErr, ok := err.(*utils.HttpError)
+				if !ok {
+					t.Fatalf("expected HttpError, got %T", err)
+				}
+				if httpErr.StatusCode != tt.expectedCode {
+					t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if updated != tt.expectUpdate {
+				t.Errorf("expected update %v, got %v", tt.expectUpdate, updated)
+			}
+			if output.Pinned != tt.expectedPinned {
+				t.Errorf("expected pinned %v, got %v", tt.expectedPinned, output.Pinned)
+			}
+		})
+	}
+}
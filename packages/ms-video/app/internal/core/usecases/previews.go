@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// Sprite sheets are grids of SpriteColumns x SpriteRows thumbnails, each
+// letterboxed to SpriteThumbnailWidth x SpriteThumbnailHeight.
+const (
+	SpriteColumns         = 10
+	SpriteRows            = 10
+	SpriteThumbnailWidth  = 160
+	SpriteThumbnailHeight = 90
+
+	ThumbnailsVTTName = "thumbnails.vtt"
+)
+
+func spriteName(index int) string {
+	return fmt.Sprintf("sprite_%03d.jpg", index)
+}
+
+// generateSpriteSheets tiles the frameCount frames matching framesPattern
+// into sprite sheets in outputDir and returns their paths, in order.
+func generateSpriteSheets(ctx context.Context, framesPattern, outputDir string, frameCount int) ([]string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create previews dir: %w", err)
+	}
+
+	err := buildSpriteCommand(ctx, framesPattern, filepath.Join(outputDir, "sprite_%03d.jpg")).
+		ErrorToStdOut().
+		Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to tile sprite sheets with ffmpeg: %w", err)
+	}
+
+	spriteCount := (frameCount + SpriteColumns*SpriteRows - 1) / (SpriteColumns * SpriteRows)
+	sprites := make([]string, spriteCount)
+	for i := range sprites {
+		sprites[i] = filepath.Join(outputDir, spriteName(i))
+	}
+
+	return sprites, nil
+}
+
+func buildSpriteCommand(ctx context.Context, framesPattern, outputPattern string) *ffmpeg.Stream {
+	stream := ffmpeg.Input(framesPattern, ffmpeg.KwArgs{"start_number": "1"}).
+		Filter("scale", ffmpeg.Args{}, ffmpeg.KwArgs{
+			"w":                           SpriteThumbnailWidth,
+			"h":                           SpriteThumbnailHeight,
+			"force_original_aspect_ratio": "decrease",
+		}).
+		Filter("pad", ffmpeg.Args{}, ffmpeg.KwArgs{
+			"w": SpriteThumbnailWidth,
+			"h": SpriteThumbnailHeight,
+			"x": "(ow-iw)/2",
+			"y": "(oh-ih)/2",
+		}).
+		Filter("tile", ffmpeg.Args{fmt.Sprintf("%dx%d", SpriteColumns, SpriteRows)})
+
+	return ffmpeg.OutputContext(ctx, []*ffmpeg.Stream{stream}, outputPattern, ffmpeg.KwArgs{"q:v": "5", "start_number": "0"}).
+		OverWriteOutput()
+}
+
+// buildThumbnailsVTT maps the time range each frame covers to its tile in
+// the sprite sheets.
+func buildThumbnailsVTT(timestamps []float64) string {
+	perSprite := SpriteColumns * SpriteRows
+
+	var vtt strings.Builder
+	vtt.WriteString("WEBVTT\n")
+	for i, start := range timestamps {
+		end := start + 1
+		if i+1 < len(timestamps) {
+			end = timestamps[i+1]
+		} else if i > 0 {
+			end = start + (start - timestamps[i-1])
+		}
+
+		tile := i % perSprite
+		x := (tile % SpriteColumns) * SpriteThumbnailWidth
+		y := (tile / SpriteColumns) * SpriteThumbnailHeight
+
+		fmt.Fprintf(&vtt, "\n%s --> %s\n%s#xywh=%d,%d,%d,%d\n",
+			formatVTTTimestamp(start), formatVTTTimestamp(end),
+			spriteName(i/perSprite), x, y, SpriteThumbnailWidth, SpriteThumbnailHeight)
+	}
+
+	return vtt.String()
+}
+
+func formatVTTTimestamp(seconds float64) string {
+	millis := int64(seconds*1000 + 0.5)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", millis/3600000, millis/60000%60, millis/1000%60, millis%1000)
+}
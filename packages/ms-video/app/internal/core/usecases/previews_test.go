@@ -0,0 +1,69 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBuildThumbnailsVTT(t *testing.T) {
+	timestamps := make([]float64, SpriteColumns*SpriteRows+2)
+	for i := range timestamps {
+		timestamps[i] = float64(i) * 2
+	}
+
+	vtt := buildThumbnailsVTT(timestamps)
+
+	if !strings.HasPrefix(vtt, "WEBVTT\n") {
+		t.Fatalf("expected WEBVTT header, got %q", vtt[:min(len(vtt), 20)])
+	}
+
+	expectedCues := []string{
+		"00:00:00.000 --> 00:00:02.000\nsprite_000.jpg#xywh=0,0,160,90",
+		"00:00:02.000 --> 00:00:04.000\nsprite_000.jpg#xywh=160,0,160,90",
+		"00:00:22.000 --> 00:00:24.000\nsprite_000.jpg#xywh=160,90,160,90",
+		"00:03:20.000 --> 00:03:22.000\nsprite_001.jpg#xywh=0,0,160,90",
+		"00:03:22.000 --> 00:03:24.000\nsprite_001.jpg#xywh=160,0,160,90",
+	}
+	for _, cue := range expectedCues {
+		if !strings.Contains(vtt, cue) {
+			t.Errorf("expected VTT to contain cue %q", cue)
+		}
+	}
+}
+
+func TestBuildThumbnailsVTT_SingleFrame(t *testing.T) {
+	vtt := buildThumbnailsVTT([]float64{5})
+
+	if !strings.Contains(vtt, "00:00:05.000 --> 00:00:06.000\nsprite_000.jpg#xywh=0,0,160,90") {
+		t.Errorf("expected single frame to last one second, got %q", vtt)
+	}
+}
+
+func TestFormatVTTTimestamp(t *testing.T) {
+	tests := []struct {
+		seconds  float64
+		expected string
+	}{
+		{0, "00:00:00.000"},
+		{1.5, "00:00:01.500"},
+		{3725.042, "01:02:05.042"},
+	}
+
+	for _, tt := range tests {
+		if result := formatVTTTimestamp(tt.seconds); result != tt.expected {
+			t.Errorf("expected %s for %v, got %s", tt.expected, tt.seconds, result)
+		}
+	}
+}
+
+func TestBuildSpriteCommand(t *testing.T) {
+	args := strings.Join(buildSpriteCommand(context.Background(), "frame_%04d.png", "sprite_%03d.jpg").GetArgs(), " ")
+
+	expected := []string{"-start_number 1 -i frame_%04d.png", "scale=", "pad=", "tile=10x10", "-start_number 0", "sprite_%03d.jpg"}
+	for _, part := range expected {
+		if !strings.Contains(args, part) {
+			t.Errorf("expected ffmpeg args to contain %q, got %q", part, args)
+		}
+	}
+}
@@ -2,13 +2,18 @@ package usecases
 
 import (
 	"archive/zip"
-	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	ffmpeg "github.com/u2takey/ffmpeg-go"
@@ -22,112 +27,234 @@ type ProcessVideoUsecase struct {
 	videoRepository     ports.VideoRepository
 	storageService      ports.StorageService
 	notificationService ports.NotificationService
+	videoProber         ports.VideoProber
+	hlsLadder           []entities.HLSRendition
+	retryPolicy         RetryPolicy
+	webhookPublisher    *WebhookPublisher
+	usageRepository     ports.UsageRepository
+	progressInterval    time.Duration
 }
 
 func NewProcessVideoUsecase(
 	videoRepository ports.VideoRepository,
 	storageService ports.StorageService,
 	notificationService ports.NotificationService,
+	videoProber ports.VideoProber,
+	hlsLadder []entities.HLSRendition,
+	retryPolicy RetryPolicy,
+	webhookPublisher *WebhookPublisher,
+	usageRepository ports.UsageRepository,
 ) *ProcessVideoUsecase {
 	return &ProcessVideoUsecase{
 		videoRepository:     videoRepository,
 		storageService:      storageService,
 		notificationService: notificationService,
+		videoProber:         videoProber,
+		hlsLadder:           hlsLadder,
+		retryPolicy:         retryPolicy,
+		webhookPublisher:    webhookPublisher,
+		usageRepository:     usageRepository,
+		progressInterval:    ProgressUpdateInterval,
 	}
 }
 
-func (u *ProcessVideoUsecase) Execute(ctx context.Context, message dto.VideoProcessMessage) error {
+// errVideoFinished and its variants stop processing of a video finished
+// elsewhere.
+var (
+	errVideoFinished  = errors.New("video was already finished")
+	errVideoDeleted   = fmt.Errorf("%w: video was deleted", errVideoFinished)
+	errVideoCancelled = fmt.Errorf("%w: video was cancelled", errVideoFinished)
+)
+
+// finishedError is the error that stops processing of a terminal video.
+func finishedError(video *entities.Video) error {
+	if video.Status == entities.VideoStatusCancelled {
+		return errVideoCancelled
+	}
+	return fmt.Errorf("%w with status %s", errVideoFinished, video.Status)
+}
+
+// Execute processes the video for the attempt-th time, counting from 1, and
+// returns a *RetryError for a transient failure while attempts remain.
+func (u *ProcessVideoUsecase) Execute(ctx context.Context, message dto.VideoProcessMessage, attempt int) error {
+	err := u.process(ctx, message, attempt)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, errVideoDeleted):
+		log.Printf("Video %s was deleted, removing its outputs", message.VideoID)
+		if err := deleteVideoOutputs(ctx, u.storageService, message.UserID, message.VideoID); err != nil {
+			log.Printf("Failed to remove outputs of deleted video %s: %v", message.VideoID, err)
+		}
+		return nil
+	case errors.Is(err, errVideoCancelled):
+		log.Printf("Video %s was cancelled, removing its outputs", message.VideoID)
+		if err := deleteVideoOutputs(ctx, u.storageService, message.UserID, message.VideoID); err != nil {
+			log.Printf("Failed to remove outputs of cancelled video %s: %v", message.VideoID, err)
+		}
+		return nil
+	case errors.Is(err, errVideoFinished):
+		log.Printf("Skipping video %s: %v", message.VideoID, err)
+		return nil
+	case ctx.Err() != nil:
+		// ffmpeg killed by the context is not a permanent failure
+		return ctx.Err()
+	case u.isFinalFailure(err, attempt):
+		return err
+	}
+
+	return &RetryError{Err: err, Delay: u.retryPolicy.Backoff(attempt)}
+}
+
+func (u *ProcessVideoUsecase) isFinalFailure(err error, attempt int) bool {
+	return IsPermanent(err) || u.retryPolicy.Exhausted(attempt)
+}
+
+func (u *ProcessVideoUsecase) process(ctx context.Context, message dto.VideoProcessMessage, attempt int) error {
 	video, err := u.videoRepository.FindByID(ctx, message.VideoID)
-	if err != nil {
+	if errors.Is(err, ports.ErrVideoNotFound) {
+		return errVideoDeleted
+	} else if err != nil {
 		log.Printf("Failed to find video %s: %v", message.VideoID, err)
 		return err
 	}
 
+	if video.IsTerminal() {
+		return finishedError(video)
+	}
+
 	video.UpdateProgress(10, entities.VideoStatusProcessing)
-	if err := u.videoRepository.Update(ctx, video); err != nil {
+	if err := u.updateVideo(ctx, video); err != nil {
 		log.Printf("Failed to update video status: %v", err)
 		return err
 	}
 
-	log.Printf("Downloading video from S3: %s", message.RawS3Key)
-	videoData, err := u.storageService.Download(ctx, message.RawS3Key)
-	if err != nil {
-		video.MarkAsFailed(fmt.Sprintf("failed to download raw video: %v", err))
-		u.videoRepository.Update(ctx, video)
-		
-		if message.UserEmail != "" {
-			notifyErr := u.notificationService.SendVideoFailedNotification(ctx, message.UserEmail, video.ID, video.OriginalName, fmt.Sprintf("Failed to download raw video: %v", err))
-			if notifyErr != nil {
-				log.Printf("Failed to send failure notification: %v", notifyErr)
-			}
+	if video.DuplicateOf != "" {
+		if reused, err := u.reuseOutputs(ctx, video, message); reused {
+			return err
 		}
-		
+	}
+
+	workDir, err := ioutil.TempDir("", "video-processing-")
+	if err != nil {
+		log.Printf("Failed to create work dir for video %s: %v", video.ID, err)
 		return err
 	}
+	defer os.RemoveAll(workDir)
 
-	video.UpdateProgress(30, entities.VideoStatusProcessing)
-	u.videoRepository.Update(ctx, video)
+	log.Printf("Downloading video from S3: %s", message.RawS3Key)
+	videoPath := filepath.Join(workDir, "input"+filepath.Ext(video.OriginalName))
+	if err := u.downloadToFile(ctx, message.RawS3Key, videoPath); err != nil {
+		return u.failVideo(ctx, video, message, attempt, "download raw video", err)
+	}
+
+	video.UpdateProgress(extractionStartPercent, entities.VideoStatusProcessing)
+	if err := u.updateVideo(ctx, video); errors.Is(err, errVideoFinished) {
+		return err
+	}
+
+	// The length of the video turns ffmpeg's position into a percentage
+	mediaInfo, err := u.videoProber.Probe(ctx, videoPath)
+	if errors.Is(err, ports.ErrInvalidVideo) {
+		return u.failVideo(ctx, video, message, attempt, "read video", err)
+	} else if err != nil {
+		log.Printf("Failed to probe video %s, extraction progress will not be reported: %v", video.ID, err)
+		mediaInfo = nil
+	} else {
+		video.Metadata = entities.NewVideoMetadata(mediaInfo)
+	}
 
 	log.Printf("Extracting frames from video %s", message.VideoID)
-	frames, err := u.extractFrames(videoData, video.ID, video.OriginalName)
-	if err != nil {
-		video.MarkAsFailed(fmt.Sprintf("failed to extract frames: %v", err))
-		u.videoRepository.Update(ctx, video)
-		
-		if message.UserEmail != "" {
-			notifyErr := u.notificationService.SendVideoFailedNotification(ctx, message.UserEmail, video.ID, video.OriginalName, fmt.Sprintf("Failed to extract frames: %v", err))
-			if notifyErr != nil {
-				log.Printf("Failed to send failure notification: %v", notifyErr)
-			}
+	options := message.Options.WithDefaults()
+	framesDir := filepath.Join(workDir, "frames")
+
+	ctx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+	stopWatching := u.watchVideo(ctx, video.ID, stop)
+	defer stopWatching()
+	update := func(ctx context.Context, video *entities.Video) error {
+		err := u.updateVideo(ctx, video)
+		if errors.Is(err, errVideoFinished) {
+			stop(err)
 		}
-		
 		return err
 	}
-	
-	video.UpdateProgress(60, entities.VideoStatusProcessing)
-	u.videoRepository.Update(ctx, video)
 
-	log.Printf("Creating ZIP file for video %s with %d frames", message.VideoID, len(frames))
-	zipData, err := u.createZipFile(video.OriginalName, videoData, frames)
+	progress := newProgressReporter(update, video, u.progressInterval)
+	extractionProgress := newFFmpegProgressWriter(extractionDuration(mediaInfo, options), progress.Phase(ctx, extractionStartPercent, extractionEndPercent))
+	frames, timestamps, err := u.extractFrames(ctx, videoPath, framesDir, video.ID, options, extractionProgress)
 	if err != nil {
-		video.MarkAsFailed(fmt.Sprintf("failed to create zip file: %v", err))
-		u.videoRepository.Update(ctx, video)
-		
-		if message.UserEmail != "" {
-			notifyErr := u.notificationService.SendVideoFailedNotification(ctx, message.UserEmail, video.ID, video.OriginalName, fmt.Sprintf("Failed to create zip file: %v", err))
-			if notifyErr != nil {
-				log.Printf("Failed to send failure notification: %v", notifyErr)
-			}
-		}
-		
+		return u.failVideo(ctx, video, message, attempt, "extract frames", err)
+	}
+
+	video.UpdateProgress(extractionEndPercent, entities.VideoStatusProcessing)
+	if err := u.updateVideo(ctx, video); errors.Is(err, errVideoFinished) {
 		return err
 	}
-		
-	video.UpdateProgress(80, entities.VideoStatusProcessing)
-	u.videoRepository.Update(ctx, video)
 
-	processedS3Key := fmt.Sprintf("processed/%s/%s.zip", message.UserID, video.ID)
-	log.Printf("Uploading processed video to S3: %s", processedS3Key)
-	if err := u.storageService.Upload(ctx, processedS3Key, zipData, "application/zip"); err != nil {
-		video.MarkAsFailed(fmt.Sprintf("failed to upload processed video: %v", err))
-		u.videoRepository.Update(ctx, video)
+	processedS3Key := videoProcessedKey(message.UserID, video.ID)
+	log.Printf("Uploading ZIP file with %d frames to S3: %s", len(frames), processedS3Key)
+	outputBytes, err := u.uploadZipFile(ctx, processedS3Key, video.OriginalName, videoPath, frames, options, video.Metadata)
+	if err != nil {
+		return u.failVideo(ctx, video, message, attempt, "upload processed video", err)
+	}
 
-		if message.UserEmail != "" {
-			notifyErr := u.notificationService.SendVideoFailedNotification(ctx, message.UserEmail, video.ID, video.OriginalName, fmt.Sprintf("Failed to upload processed video: %v", err))
-			if notifyErr != nil {
-				log.Printf("Failed to send failure notification: %v", notifyErr)
-			}
+	video.UpdateProgress(85, entities.VideoStatusProcessing)
+	if err := u.updateVideo(ctx, video); errors.Is(err, errVideoFinished) {
+		return err
+	}
+
+	// Frames, previews and HLS are extras that do not fail the video
+	framesPrefix := videoFramesPrefix(message.UserID, video.ID)
+	framesBytes, err := u.uploadFrames(ctx, framesPrefix, frames, timestamps, options)
+	outputBytes += framesBytes
+	if err != nil {
+		log.Printf("Failed to store frames of video %s: %v", video.ID, err)
+	} else {
+		video.FramesS3Prefix = framesPrefix
+	}
+
+	previewsPrefix := videoPreviewsPrefix(message.UserID, video.ID)
+	previewsBytes, err := u.createPreviews(ctx, previewsPrefix, workDir, framesDir, len(frames), timestamps, options)
+	outputBytes += previewsBytes
+	if err != nil {
+		log.Printf("Failed to create previews for video %s: %v", video.ID, err)
+	} else if len(frames) > 0 {
+		video.PreviewsS3Prefix = previewsPrefix
+	}
+
+	if options.HLS {
+		video.UpdateProgress(90, entities.VideoStatusProcessing)
+		if err := u.updateVideo(ctx, video); errors.Is(err, errVideoFinished) {
+			return err
 		}
 
-		return err
+		hlsPrefix := videoHLSPrefix(message.UserID, video.ID)
+		hlsBytes, err := u.createHLS(ctx, hlsPrefix, workDir, videoPath, mediaInfo)
+		outputBytes += hlsBytes
+		if err != nil {
+			log.Printf("Failed to create HLS output for video %s: %v", video.ID, err)
+		} else {
+			video.HLSS3Prefix = hlsPrefix
+		}
 	}
 
+	stopWatching()
 	log.Printf("Video processing completed: %s", message.VideoID)
-	video.MarkAsCompleted(processedS3Key)
-	if err := u.videoRepository.Update(ctx, video); err != nil {
+	return u.completeVideo(ctx, video, message, processedS3Key, outputBytes)
+}
+
+// completeVideo records the outputs of the video, counts them against the
+// usage of its owner and tells the user it is ready.
+func (u *ProcessVideoUsecase) completeVideo(ctx context.Context, video *entities.Video, message dto.VideoProcessMessage, processedS3Key string, outputBytes int64) error {
+	video.MarkAsCompleted(processedS3Key, outputBytes)
+	if err := u.updateVideo(ctx, video); err != nil {
 		log.Printf("Failed to mark video as completed: %v", err)
 		return err
 	}
+	if err := u.usageRepository.Adjust(ctx, video.UserID, outputBytes, 0); err != nil {
+		log.Printf("Failed to count outputs of video %s against usage: %v", video.ID, err)
+	}
 
 	if message.UserEmail != "" {
 		notifyErr := u.notificationService.SendVideoProcessedNotification(ctx, message.UserEmail, video.ID, video.OriginalName)
@@ -135,112 +262,524 @@ func (u *ProcessVideoUsecase) Execute(ctx context.Context, message dto.VideoProc
 			log.Printf("Failed to send success notification: %v", notifyErr)
 		}
 	}
+	u.webhookPublisher.Publish(ctx, entities.WebhookEventVideoCompleted, video)
+
+	return nil
+}
+
+// reuseOutputs completes a duplicate video with copies of the outputs of the
+// video it duplicates, or reports false when it has to be processed after all.
+func (u *ProcessVideoUsecase) reuseOutputs(ctx context.Context, video *entities.Video, message dto.VideoProcessMessage) (bool, error) {
+	source, err := u.videoRepository.FindByID(ctx, video.DuplicateOf)
+	if err != nil || !video.CanReuseOutputsOf(source) {
+		log.Printf("Outputs of video %s cannot be reused for video %s, processing it instead: %v", video.DuplicateOf, video.ID, err)
+		video.DuplicateOf = ""
+		return false, nil
+	}
+
+	log.Printf("Reusing outputs of video %s for duplicate video %s", source.ID, video.ID)
+	if err := u.copyOutputs(ctx, source, video, message.UserID); err != nil {
+		if ctx.Err() != nil {
+			return true, ctx.Err()
+		}
+		log.Printf("Failed to copy outputs of video %s for video %s, processing it instead: %v", source.ID, video.ID, err)
+		if err := deleteVideoOutputs(ctx, u.storageService, message.UserID, video.ID); err != nil {
+			log.Printf("Failed to remove partial copies for video %s: %v", video.ID, err)
+		}
+		video.PreviewsS3Prefix = ""
+		video.HLSS3Prefix = ""
+		video.FramesS3Prefix = ""
+		video.DuplicateOf = ""
+		return false, nil
+	}
+
+	// The content is the same, so is what ffprobe would find in it
+	if source.Metadata != nil {
+		video.Metadata = source.Metadata
+	}
+	return true, u.completeVideo(ctx, video, message, videoProcessedKey(message.UserID, video.ID), source.OutputBytes)
+}
+
+// copyOutputs copies the processed ZIP, frames, previews and HLS renditions
+// of source to the keys of video, recording the extras on video as they are
+// copied.
+func (u *ProcessVideoUsecase) copyOutputs(ctx context.Context, source, video *entities.Video, userID string) error {
+	if err := u.storageService.Copy(ctx, source.ProcessedS3Key, videoProcessedKey(userID, video.ID)); err != nil {
+		return fmt.Errorf("failed to copy processed video: %w", err)
+	}
+
+	// The manifest names frames relative to itself, so it holds for the copy
+	if source.FramesS3Prefix != "" {
+		prefix := videoFramesPrefix(userID, video.ID)
+		if err := u.storageService.CopyPrefix(ctx, source.FramesS3Prefix, prefix); err != nil {
+			return fmt.Errorf("failed to copy frames: %w", err)
+		}
+		video.FramesS3Prefix = prefix
+	}
+
+	if source.PreviewsS3Prefix != "" {
+		prefix := videoPreviewsPrefix(userID, video.ID)
+		if err := u.storageService.CopyPrefix(ctx, source.PreviewsS3Prefix, prefix); err != nil {
+			return fmt.Errorf("failed to copy previews: %w", err)
+		}
+		video.PreviewsS3Prefix = prefix
+	}
+
+	if source.HLSS3Prefix != "" {
+		prefix := videoHLSPrefix(userID, video.ID)
+		if err := u.storageService.CopyPrefix(ctx, source.HLSS3Prefix, prefix); err != nil {
+			return fmt.Errorf("failed to copy HLS output: %w", err)
+		}
+		video.HLSS3Prefix = prefix
+	}
 
 	return nil
 }
 
-func (u *ProcessVideoUsecase) extractFrames(videoData []byte, videoID, originalName string) ([][]byte, error) {
-	tmpDir, err := ioutil.TempDir("", "video-processing-")
+// failVideo records that the video failed at step and returns the cause.
+func (u *ProcessVideoUsecase) failVideo(ctx context.Context, video *entities.Video, message dto.VideoProcessMessage, attempt int, step string, cause error) error {
+	if ctx.Err() != nil {
+		// Interrupted rather than failed
+		return context.Cause(ctx)
+	}
+
+	final := u.isFinalFailure(cause, attempt)
+	if final {
+		video.MarkAsFailed(fmt.Sprintf("failed to %s: %v", step, cause))
+	} else {
+		log.Printf("Attempt %d of %d for video %s failed, retrying: %v", attempt, u.retryPolicy.MaxAttempts, video.ID, cause)
+		video.MarkForRetry(fmt.Sprintf("attempt %d failed to %s: %v", attempt, step, cause))
+	}
+
+	if err := u.updateVideo(ctx, video); errors.Is(err, errVideoFinished) {
+		return err
+	} else if err != nil {
+		log.Printf("Failed to save failure of video %s: %v", video.ID, err)
+	}
+
+	if final && message.UserEmail != "" {
+		notifyErr := u.notificationService.SendVideoFailedNotification(ctx, message.UserEmail, video.ID, video.OriginalName, fmt.Sprintf("Failed to %s: %v", step, cause))
+		if notifyErr != nil {
+			log.Printf("Failed to send failure notification: %v", notifyErr)
+		}
+	}
+	if final {
+		u.webhookPublisher.Publish(ctx, entities.WebhookEventVideoFailed, video)
+	}
+
+	return cause
+}
+
+// watchVideo checks the video every progress interval and stops processing
+// once it is finished elsewhere, so ffmpeg is stopped even when it reports no
+// progress. The returned function stops watching.
+func (u *ProcessVideoUsecase) watchVideo(ctx context.Context, videoID string, stop context.CancelCauseFunc) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(u.progressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			video, err := u.videoRepository.FindByID(ctx, videoID)
+			switch {
+			case ctx.Err() != nil:
+				return
+			case errors.Is(err, ports.ErrVideoNotFound):
+				stop(errVideoDeleted)
+				return
+			case err != nil:
+				log.Printf("Failed to check status of video %s: %v", videoID, err)
+			case video.IsTerminal():
+				stop(finishedError(video))
+				return
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// updateVideo saves the video, reloading it when another writer got there
+// first.
+func (u *ProcessVideoUsecase) updateVideo(ctx context.Context, video *entities.Video) error {
+	err := u.videoRepository.Update(ctx, video)
+
+	var conflict *ports.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return err
+	}
+
+	latest, findErr := u.videoRepository.FindByID(ctx, video.ID)
+	if errors.Is(findErr, ports.ErrVideoNotFound) {
+		return errVideoDeleted
+	} else if findErr != nil {
+		return err
+	}
+
+	if latest.IsTerminal() {
+		return finishedError(latest)
+	}
+
+	video.Version = latest.Version
+	return u.videoRepository.Update(ctx, video)
+}
+
+// downloadToFile copies an object to path without holding it in memory.
+func (u *ProcessVideoUsecase) downloadToFile(ctx context.Context, key, path string) error {
+	body, err := u.storageService.DownloadStream(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	file, err := os.Create(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+		return fmt.Errorf("failed to create video file: %w", err)
 	}
-	defer os.RemoveAll(tmpDir)
 
-	videoPath := filepath.Join(tmpDir, "input"+filepath.Ext(originalName))
-	if err := ioutil.WriteFile(videoPath, videoData, 0644); err != nil {
-		return nil, fmt.Errorf("failed to write video file: %w", err)
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write video file: %w", err)
 	}
 
-	framesDir := filepath.Join(tmpDir, "frames")
+	return file.Close()
+}
+
+// extractFrames writes the frames to framesDir and returns their paths in
+// order, along with the position of each one in the video, in seconds.
+func (u *ProcessVideoUsecase) extractFrames(ctx context.Context, videoPath, framesDir, videoID string, options entities.ExtractionOptions, progress io.Writer) ([]string, []float64, error) {
 	if err := os.MkdirAll(framesDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create frames dir: %w", err)
+		return nil, nil, fmt.Errorf("failed to create frames dir: %w", err)
 	}
 
 	log.Printf("Extracting frames from %s", videoPath)
-	outputPattern := filepath.Join(framesDir, "frame_%04d.jpg")
-	
-	err = ffmpeg.Input(videoPath).Filter("fps", ffmpeg.Args{"1"}).Output(outputPattern, ffmpeg.KwArgs{
-		"q:v": "2",
-	}).OverWriteOutput().ErrorToStdOut().Run()
-	
+	outputPattern := filepath.Join(framesDir, "frame_%04d."+string(options.Format))
+
+	// Only the showinfo timestamps are kept from the log, which can be long
+	ffmpegLog := newFrameTimestampRecorder()
+	err := buildExtractionCommand(ctx, videoPath, outputPattern, options).
+		WithOutput(progress).
+		WithErrorOutput(io.MultiWriter(os.Stdout, ffmpegLog)).
+		Run()
+
 	if err != nil {
-		return nil, fmt.Errorf("failed to extract frames with ffmpeg: %w", err)
+		return nil, nil, fmt.Errorf("failed to extract frames with ffmpeg: %w", err)
 	}
 
 	files, err := ioutil.ReadDir(framesDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read frames directory: %w", err)
+		return nil, nil, fmt.Errorf("failed to read frames directory: %w", err)
 	}
 
-	var frames [][]byte
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-		framePath := filepath.Join(framesDir, file.Name())
-		frameData, err := ioutil.ReadFile(framePath)
-		if err != nil {
-			log.Printf("Failed to read frame %s: %v", file.Name(), err)
-			continue
-		}
-		frames = append(frames, frameData)
+	// Paths are built from the count to keep them in order past frame_9999
+	frames := make([]string, 0, len(files))
+	for i := range files {
+		frames = append(frames, fmt.Sprintf(outputPattern, i+1))
 	}
 
+	timestamps := ffmpegLog.Timestamps(len(frames), options)
+
 	log.Printf("Extracted %d frames from video %s", len(frames), videoID)
-	return frames, nil
+	return frames, timestamps, nil
 }
 
-func (u *ProcessVideoUsecase) createZipFile(originalName string, videoData []byte, frames [][]byte) ([]byte, error) {
-	buf := new(bytes.Buffer)
-	zipWriter := zip.NewWriter(buf)
+// createPreviews uploads sprite sheets of the frames and a WebVTT file that
+// points at them under prefix.
+func (u *ProcessVideoUsecase) createPreviews(ctx context.Context, prefix, workDir, framesDir string, frameCount int, timestamps []float64, options entities.ExtractionOptions) (int64, error) {
+	if frameCount == 0 {
+		return 0, nil
+	}
 
-	videoFile, err := zipWriter.Create(originalName)
+	spritesDir := filepath.Join(workDir, "previews")
+	sprites, err := generateSpriteSheets(ctx, filepath.Join(framesDir, "frame_%04d."+string(options.Format)), spritesDir, frameCount)
 	if err != nil {
-		return nil, err
-	}
-	if _, err := videoFile.Write(videoData); err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	for i, frameData := range frames {
-		frameName := fmt.Sprintf("frames/frame_%04d.jpg", i+1)
-		frameFile, err := zipWriter.Create(frameName)
+	var uploaded int64
+	for i, sprite := range sprites {
+		size, err := u.uploadFile(ctx, prefix+spriteName(i), sprite, "image/jpeg")
+		uploaded += size
 		if err != nil {
-			return nil, fmt.Errorf("failed to create frame file in zip: %w", err)
+			return uploaded, fmt.Errorf("failed to upload sprite sheet: %w", err)
 		}
-		if _, err := frameFile.Write(frameData); err != nil {
-			return nil, fmt.Errorf("failed to write frame data: %w", err)
+	}
+
+	vtt := buildThumbnailsVTT(timestamps)
+	if err := u.storageService.Upload(ctx, prefix+ThumbnailsVTTName, []byte(vtt), "text/vtt"); err != nil {
+		return uploaded, fmt.Errorf("failed to upload thumbnails index: %w", err)
+	}
+
+	return uploaded + int64(len(vtt)), nil
+}
+
+// buildExtractionCommand turns extraction options into the ffmpeg command
+// that writes the sampled frames to outputPattern.
+func buildExtractionCommand(ctx context.Context, videoPath, outputPattern string, options entities.ExtractionOptions) *ffmpeg.Stream {
+	inputArgs := ffmpeg.KwArgs{}
+	if options.StartSeconds > 0 {
+		inputArgs["ss"] = strconv.FormatFloat(options.StartSeconds, 'f', -1, 64)
+	}
+	if options.EndSeconds > 0 {
+		inputArgs["to"] = strconv.FormatFloat(options.EndSeconds, 'f', -1, 64)
+	}
+
+	outputArgs := ffmpeg.KwArgs{}
+
+	var stream *ffmpeg.Stream
+	switch options.Mode {
+	case entities.ExtractionModeScene:
+		stream = ffmpeg.Input(videoPath, inputArgs).
+			Filter("select", ffmpeg.Args{fmt.Sprintf("gt(scene,%s)", strconv.FormatFloat(options.SceneThreshold, 'f', -1, 64))})
+		outputArgs["fps_mode"] = "vfr"
+	case entities.ExtractionModeKeyframes:
+		// Skipping non-key frames in the decoder is much cheaper than
+		// decoding everything and filtering on pict_type
+		inputArgs["skip_frame"] = "nokey"
+		stream = ffmpeg.Input(videoPath, inputArgs)
+		outputArgs["fps_mode"] = "vfr"
+	default:
+		stream = ffmpeg.Input(videoPath, inputArgs).Filter("fps", ffmpeg.Args{frameRateArg(options)})
+	}
+
+	if options.MaxWidth > 0 || options.MaxHeight > 0 {
+		width, height := "iw", "ih"
+		if options.MaxWidth > 0 {
+			width = fmt.Sprintf("min(iw,%d)", options.MaxWidth)
+		}
+		if options.MaxHeight > 0 {
+			height = fmt.Sprintf("min(ih,%d)", options.MaxHeight)
 		}
+		stream = stream.Filter("scale", ffmpeg.Args{}, ffmpeg.KwArgs{
+			"w":                          width,
+			"h":                          height,
+			"force_original_aspect_ratio": "decrease",
+		})
+	}
+
+	// showinfo logs the timestamp of every frame that reaches the output
+	stream = stream.Filter("showinfo", ffmpeg.Args{})
+
+	switch options.Format {
+	case entities.FrameFormatJPG:
+		// Map quality 1-100 onto the mjpeg scale, where 2 is best and 31 worst
+		outputArgs["q:v"] = strconv.Itoa(2 + (100-options.Quality)*29/99)
+	case entities.FrameFormatWEBP:
+		outputArgs["c:v"] = "libwebp"
+		outputArgs["quality"] = strconv.Itoa(options.Quality)
 	}
 
+	output := ffmpeg.Output([]*ffmpeg.Stream{stream}, outputPattern, outputArgs).
+		GlobalArgs("-progress", "pipe:1", "-nostats")
+	// GlobalArgs starts a new stream, which would drop a context set earlier
+	output.Context = ctx
+	return output.OverWriteOutput()
+}
+
+var ptsTimePattern = regexp.MustCompile(`pts_time:\s*(-?[0-9.]+)`)
+
+// frameTimestampRecorder is an io.Writer for the ffmpeg log that keeps the
+// showinfo frame positions and discards everything else.
+type frameTimestampRecorder struct {
+	lines   lineWriter
+	ptsTime []float64
+}
+
+func newFrameTimestampRecorder() *frameTimestampRecorder {
+	r := &frameTimestampRecorder{}
+	r.lines.onLine = r.recordLine
+	return r
+}
+
+func (r *frameTimestampRecorder) Write(p []byte) (int, error) {
+	return r.lines.Write(p)
+}
+
+func (r *frameTimestampRecorder) recordLine(line string) {
+	if match := ptsTimePattern.FindStringSubmatch(line); match != nil {
+		if seconds, err := strconv.ParseFloat(match[1], 64); err == nil {
+			r.ptsTime = append(r.ptsTime, seconds)
+		}
+	}
+}
+
+// Timestamps returns the position of each of the frameCount frames.
+func (r *frameTimestampRecorder) Timestamps(frameCount int, options entities.ExtractionOptions) []float64 {
+	r.lines.Flush()
+
+	if len(r.ptsTime) == frameCount {
+		timestamps := make([]float64, frameCount)
+		for i, seconds := range r.ptsTime {
+			timestamps[i] = options.StartSeconds + max(seconds, 0)
+		}
+		return timestamps
+	}
+
+	timestamps := make([]float64, frameCount)
+	for i := range timestamps {
+		timestamps[i] = options.StartSeconds + float64(i)/options.FrameRate()
+	}
+	return timestamps
+}
+
+// parseFrameTimestamps reads frame positions from the showinfo lines in an
+// ffmpeg log.
+func parseFrameTimestamps(ffmpegLog string, frameCount int, options entities.ExtractionOptions) []float64 {
+	recorder := newFrameTimestampRecorder()
+	recorder.Write([]byte(ffmpegLog))
+	return recorder.Timestamps(frameCount, options)
+}
+
+// frameRateArg expresses the sampling rate for the fps filter, as a fraction
+// when sampling by interval so it stays exact.
+func frameRateArg(options entities.ExtractionOptions) string {
+	if options.IntervalSeconds > 0 {
+		return "1/" + strconv.FormatFloat(options.IntervalSeconds, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(options.FrameRate(), 'f', -1, 64)
+}
+
+// describeSampling explains the sampling rate in the archive README.
+func describeSampling(options entities.ExtractionOptions) string {
+	switch options.Mode {
+	case entities.ExtractionModeScene:
+		return fmt.Sprintf("1 frame per scene change, threshold %g", options.SceneThreshold)
+	case entities.ExtractionModeKeyframes:
+		return "keyframes only"
+	}
+	if options.IntervalSeconds > 0 {
+		return fmt.Sprintf("1 frame every %g seconds", options.IntervalSeconds)
+	}
+	if options.FrameRate() == 1 {
+		return "1 frame per second"
+	}
+	return fmt.Sprintf("%g frames per second", options.FrameRate())
+}
+
+// uploadZipFile streams the archive into S3 and returns its size.
+func (u *ProcessVideoUsecase) uploadZipFile(ctx context.Context, key, originalName, videoPath string, frames []string, options entities.ExtractionOptions, metadata *entities.VideoMetadata) (int64, error) {
+	reader, writer := io.Pipe()
+
+	zipErr := make(chan error, 1)
+	go func() {
+		err := u.createZipFile(writer, originalName, videoPath, frames, options, metadata)
+		writer.CloseWithError(err)
+		zipErr <- err
+	}()
+
+	size, err := u.storageService.UploadStream(ctx, key, reader, "application/zip")
+	// Unblocks the writer if the upload stopped reading early
+	reader.CloseWithError(io.ErrClosedPipe)
+
+	if zipErr := <-zipErr; zipErr != nil && err == nil {
+		err = zipErr
+	}
+	return size, err
+}
+
+// zipMetadata is the metadata.json of the archive.
+type zipMetadata struct {
+	OriginalFile      string                     `json:"original_file"`
+	ProcessedAt       time.Time                  `json:"processed_at"`
+	SizeBytes         int64                      `json:"size_bytes"`
+	FramesExtracted   int                        `json:"frames_extracted"`
+	Sampling          string                     `json:"sampling"`
+	ExtractionOptions entities.ExtractionOptions `json:"extraction_options"`
+	// Video is null when the video could not be probed
+	Video *entities.VideoMetadata `json:"video"`
+}
+
+// createZipFile writes the archive to w, one file at a time.
+func (u *ProcessVideoUsecase) createZipFile(w io.Writer, originalName, videoPath string, frames []string, options entities.ExtractionOptions, metadata *entities.VideoMetadata) error {
+	zipWriter := zip.NewWriter(w)
+
+	videoSize, err := addFileToZip(zipWriter, originalName, videoPath)
+	if err != nil {
+		return fmt.Errorf("failed to add video to zip: %w", err)
+	}
+
+	for i, framePath := range frames {
+		frameName := "frames/" + entities.FrameName(i+1, options.Format)
+		if _, err := addFileToZip(zipWriter, frameName, framePath); err != nil {
+			return fmt.Errorf("failed to add frame to zip: %w", err)
+		}
+	}
+
+	processedAt := time.Now()
 	metadataFile, err := zipWriter.Create("metadata.txt")
 	if err != nil {
-		return nil, err
+		return err
 	}
-	metadata := fmt.Sprintf("Original File: %s\nProcessed: %s\nSize: %d bytes\nFrames Extracted: %d\n", 
-		originalName, 
-		time.Now().Format(time.RFC3339), 
-		len(videoData),
-		len(frames))
-	if _, err := metadataFile.Write([]byte(metadata)); err != nil {
-		return nil, err
+	metadataText := fmt.Sprintf("Original File: %s\nProcessed: %s\nSize: %d bytes\nFrames Extracted: %d\nSampling: %s\n",
+		originalName,
+		processedAt.Format(time.RFC3339),
+		videoSize,
+		len(frames),
+		describeSampling(options))
+	if _, err := metadataFile.Write([]byte(metadataText)); err != nil {
+		return err
+	}
+
+	metadataJSONFile, err := zipWriter.Create("metadata.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(metadataJSONFile)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(zipMetadata{
+		OriginalFile:      originalName,
+		ProcessedAt:       processedAt.UTC().Truncate(time.Second),
+		SizeBytes:         videoSize,
+		FramesExtracted:   len(frames),
+		Sampling:          describeSampling(options),
+		ExtractionOptions: options,
+		Video:             metadata,
+	}); err != nil {
+		return err
 	}
 
 	readmeFile, err := zipWriter.Create("README.txt")
 	if err != nil {
-		return nil, err
+		return err
 	}
-	readme := fmt.Sprintf("Video Processing Complete\n\nOriginal file: %s\nProcessed on: %s\nFrames extracted: %d frames\n\nThis archive contains:\n- Original video file\n- Extracted frames (1 frame per second) in the 'frames' folder\n",
+	readme := fmt.Sprintf("Video Processing Complete\n\nOriginal file: %s\nProcessed on: %s\nFrames extracted: %d frames\n\nThis archive contains:\n- Original video file\n- Extracted frames (%s, %s) in the 'frames' folder\n- metadata.json describing the video and how it was processed\n",
 		originalName,
-		time.Now().Format("2006-01-02 15:04:05"),
-		len(frames))
+		processedAt.Format("2006-01-02 15:04:05"),
+		len(frames),
+		describeSampling(options),
+		strings.ToUpper(string(options.Format)))
 	if _, err := readmeFile.Write([]byte(readme)); err != nil {
-		return nil, err
+		return err
 	}
 
-	if err := zipWriter.Close(); err != nil {
-		return nil, err
+	return zipWriter.Close()
+}
+
+// addFileToZip copies the file at path into the archive as name and returns
+// its size.
+func addFileToZip(zipWriter *zip.Writer, name, path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	entry, err := zipWriter.Create(name)
+	if err != nil {
+		return 0, err
 	}
 
-	return buf.Bytes(), nil
+	return io.Copy(entry, file)
 }
@@ -1,14 +1,24 @@
 package usecases
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
 )
 
 func TestProcessVideoUsecase_Execute_VideoNotFound(t *testing.T) {
@@ -23,7 +33,7 @@ func TestProcessVideoUsecase_Execute_VideoNotFound(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	notificationService := &mocks.MockNotificationService{}
 
-	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService)
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
 
 	message := dto.VideoProcessMessage{
 		VideoID:   "non-existent-video",
@@ -32,7 +42,7 @@ func TestProcessVideoUsecase_Execute_VideoNotFound(t *testing.T) {
 		RawS3Key:  "raw/user-123/video.mp4",
 	}
 
-	err := usecase.Execute(ctx, message)
+	err := usecase.Execute(ctx, message, 1)
 
 	if err == nil {
 		t.Fatal("expected error when video not found, got nil")
@@ -66,7 +76,7 @@ func TestProcessVideoUsecase_Execute_DownloadFails(t *testing.T) {
 	}
 
 	storageService := &mocks.MockStorageService{
-		DownloadFunc: func(ctx context.Context, key string) ([]byte, error) {
+		DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
 			return nil, errors.New("download failed")
 		},
 	}
@@ -81,7 +91,7 @@ func TestProcessVideoUsecase_Execute_DownloadFails(t *testing.T) {
 		},
 	}
 
-	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService)
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
 
 	message := dto.VideoProcessMessage{
 		VideoID:   videoID,
@@ -90,7 +100,7 @@ func TestProcessVideoUsecase_Execute_DownloadFails(t *testing.T) {
 		RawS3Key:  "raw/user-123/test.mp4",
 	}
 
-	err := usecase.Execute(ctx, message)
+	err := usecase.Execute(ctx, message, DefaultRetryPolicy().MaxAttempts)
 
 	if err == nil {
 		t.Fatal("expected error when download fails, got nil")
@@ -109,6 +119,582 @@ func TestProcessVideoUsecase_Execute_DownloadFails(t *testing.T) {
 	}
 }
 
+func TestProcessVideoUsecase_Execute_ReusesDuplicateOutputs(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		sourceErr      error
+		copyErr        error
+		expectReuse    bool
+		expectedStatus entities.VideoStatus
+	}{
+		{
+			name:           "should copy the outputs of the original instead of processing",
+			expectReuse:    true,
+			expectedStatus: entities.VideoStatusCompleted,
+		},
+		{
+			name:           "should process the video when the original is gone",
+			sourceErr:      ports.ErrVideoNotFound,
+			expectedStatus: entities.VideoStatusFailed,
+		},
+		{
+			name:           "should process the video when copying fails",
+			copyErr:        errors.New("copy failed"),
+			expectedStatus: entities.VideoStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &entities.Video{
+				ID:           "video-123",
+				UserID:       "user-123",
+				OriginalName: "test.mp4",
+				Status:       entities.VideoStatusPending,
+				ContentHash:  "hash-1",
+				DuplicateOf:  "original-video",
+			}
+			source := &entities.Video{
+				ID:               "original-video",
+				UserID:           "user-123",
+				Status:           entities.VideoStatusCompleted,
+				ProcessedS3Key:   "processed/user-123/original-video.zip",
+				PreviewsS3Prefix: "previews/user-123/original-video/",
+				FramesS3Prefix:   "processed/user-123/original-video/frames/",
+				ContentHash:      "hash-1",
+				OutputBytes:      2048,
+				Metadata:         &entities.VideoMetadata{Container: "mp4", VideoCodec: "h264", DurationSeconds: 60},
+			}
+
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					if id == source.ID {
+						if tt.sourceErr != nil {
+							return nil, tt.sourceErr
+						}
+						return source, nil
+					}
+					return video, nil
+				},
+			}
+			copied := map[string]string{}
+			downloaded := false
+			storageService := &mocks.MockStorageService{
+				CopyFunc: func(ctx context.Context, sourceKey, key string) error {
+					copied[sourceKey] = key
+					return tt.copyErr
+				},
+				CopyPrefixFunc: func(ctx context.Context, sourcePrefix, prefix string) error {
+					copied[sourcePrefix] = prefix
+					return nil
+				},
+				DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					downloaded = true
+					return nil, errors.New("download failed")
+				},
+			}
+			var adjusted int64
+			usageRepo := &mocks.MockUsageRepository{
+				AdjustFunc: func(ctx context.Context, userID string, bytes int64, videos int) error {
+					adjusted += bytes
+					return nil
+				},
+			}
+
+			usecase := NewProcessVideoUsecase(videoRepo, storageService, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), usageRepo)
+
+			message := dto.VideoProcessMessage{
+				VideoID:  video.ID,
+				UserID:   "user-123",
+				RawS3Key: "raw/user-123/test.mp4",
+			}
+
+			_ = usecase.Execute(ctx, message, DefaultRetryPolicy().MaxAttempts)
+
+			if video.Status != tt.expectedStatus {
+				t.Errorf("expected status %s, got %s", tt.expectedStatus, video.Status)
+			}
+
+			if downloaded == tt.expectReuse {
+				t.Errorf("expected download %v, got %v", !tt.expectReuse, downloaded)
+			}
+
+			if !tt.expectReuse {
+				if video.DuplicateOf != "" {
+					t.Errorf("expected the processed video not to be a duplicate anymore, got '%s'", video.DuplicateOf)
+				}
+				return
+			}
+
+			if copied[source.ProcessedS3Key] != "processed/user-123/video-123.zip" || copied[source.PreviewsS3Prefix] != "previews/user-123/video-123/" || copied[source.FramesS3Prefix] != "processed/user-123/video-123/frames/" {
+				t.Errorf("expected outputs copied to the video's own keys, got %v", copied)
+			}
+
+			if video.ProcessedS3Key != "processed/user-123/video-123.zip" || video.PreviewsS3Prefix != "previews/user-123/video-123/" || video.FramesS3Prefix != "processed/user-123/video-123/frames/" || video.HLSS3Prefix != "" {
+				t.Errorf("expected the copies recorded on the video, got %+v", video)
+			}
+
+			if video.OutputBytes != source.OutputBytes || adjusted != source.OutputBytes {
+				t.Errorf("expected %d output bytes counted, got %d and %d", source.OutputBytes, video.OutputBytes, adjusted)
+			}
+
+			if video.Metadata != source.Metadata {
+				t.Errorf("expected the metadata of the original, got %+v", video.Metadata)
+			}
+		})
+	}
+}
+
+func TestProcessVideoUsecase_Execute_RetriesTransientFailures(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		attempt        int
+		downloadErr    error
+		expectRetry    bool
+		expectedStatus entities.VideoStatus
+	}{
+		{
+			name:           "should retry transient failures with backoff",
+			attempt:        2,
+			downloadErr:    errors.New("RequestTimeout: connection reset"),
+			expectRetry:    true,
+			expectedStatus: entities.VideoStatusPending,
+		},
+		{
+			name:           "should fail once attempts are exhausted",
+			attempt:        DefaultRetryPolicy().MaxAttempts,
+			downloadErr:    errors.New("RequestTimeout: connection reset"),
+			expectedStatus: entities.VideoStatusFailed,
+		},
+		{
+			name:           "should fail permanent failures on the first attempt",
+			attempt:        1,
+			downloadErr:    ports.ErrObjectNotFound,
+			expectedStatus: entities.VideoStatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := &entities.Video{
+				ID:           "video-123",
+				UserID:       "user-123",
+				UserEmail:    "user@example.com",
+				OriginalName: "test.mp4",
+				Status:       entities.VideoStatusPending,
+			}
+
+			notified := false
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return video, nil
+				},
+			}
+			storageService := &mocks.MockStorageService{
+				DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					return nil, tt.downloadErr
+				},
+			}
+			notificationService := &mocks.MockNotificationService{
+				SendVideoFailedNotificationFunc: func(ctx context.Context, email, videoID, originalName, errorMessage string) error {
+					notified = true
+					return nil
+				},
+			}
+			var webhookEvents []entities.WebhookEvent
+			webhookPublisher := NewWebhookPublisher(
+				&mocks.MockWebhookRepository{
+					FindByUserIDFunc: func(ctx context.Context, userID string) ([]*entities.Webhook, error) {
+						return []*entities.Webhook{{ID: "hook-1", UserID: userID, Events: []entities.WebhookEvent{entities.WebhookEventVideoFailed}}}, nil
+					},
+				},
+				&mocks.MockWebhookDeliveryRepository{
+					SaveFunc: func(ctx context.Context, delivery *entities.WebhookDelivery) error {
+						webhookEvents = append(webhookEvents, delivery.Event)
+						return nil
+					},
+				},
+				&mocks.MockWebhookQueue{},
+			)
+
+			usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), webhookPublisher, &mocks.MockUsageRepository{})
+
+			err := usecase.Execute(ctx, dto.VideoProcessMessage{VideoID: video.ID, UserID: video.UserID, UserEmail: video.UserEmail}, tt.attempt)
+
+			var retryErr *RetryError
+			if errors.As(err, &retryErr) != tt.expectRetry {
+				t.Fatalf("expected retry %v, got %v", tt.expectRetry, err)
+			}
+
+			if tt.expectRetry && retryErr.Delay != DefaultRetryPolicy().Backoff(tt.attempt) {
+				t.Errorf("expected retry delay %v, got %v", DefaultRetryPolicy().Backoff(tt.attempt), retryErr.Delay)
+			}
+
+			if video.Status != tt.expectedStatus {
+				t.Errorf("expected video status '%s', got '%s'", tt.expectedStatus, video.Status)
+			}
+
+			if notified == tt.expectRetry {
+				t.Errorf("expected failure notification only for final failures, notified: %v", notified)
+			}
+
+			if published := len(webhookEvents) == 1; published == tt.expectRetry {
+				t.Errorf("expected video.failed webhook only for final failures, got %v", webhookEvents)
+			}
+		})
+	}
+}
+
+func TestProcessVideoUsecase_Execute_Interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	video := &entities.Video{
+		ID:        "video-123",
+		UserID:    "user-123",
+		UserEmail: "user@example.com",
+		Status:    entities.VideoStatusPending,
+	}
+
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return video, nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			cancel()
+			return nil, ctx.Err()
+		},
+	}
+	notificationService := &mocks.MockNotificationService{
+		SendVideoFailedNotificationFunc: func(ctx context.Context, email, videoID, originalName, errorMessage string) error {
+			t.Error("expected no failure notification for an interrupted video")
+			return nil
+		},
+	}
+
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+	err := usecase.Execute(ctx, dto.VideoProcessMessage{VideoID: video.ID, UserID: video.UserID, UserEmail: video.UserEmail}, DefaultRetryPolicy().MaxAttempts)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if video.Status == entities.VideoStatusFailed {
+		t.Error("expected interrupted video not to be marked as failed")
+	}
+}
+
+func TestProcessVideoUsecase_Execute_StopsWhenDeleted(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		deletedBefore bool
+	}{
+		{name: "should acknowledge a message for a deleted video", deletedBefore: true},
+		{name: "should abort and remove outputs when deleted while processing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := tt.deletedBefore
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					if deleted {
+						return nil, ports.ErrVideoNotFound
+					}
+					return &entities.Video{ID: id, UserID: "user-123", Status: entities.VideoStatusPending, Version: 1}, nil
+				},
+				UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+					// The video is deleted between loading and the first update
+					deleted = true
+					return &ports.VersionConflictError{VideoID: v.ID, Version: v.Version}
+				},
+			}
+
+			var removed []string
+			storageService := &mocks.MockStorageService{
+				DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					t.Error("expected deleted video not to be downloaded")
+					return nil, errors.New("unexpected download")
+				},
+				DeleteFunc: func(ctx context.Context, key string) error {
+					removed = append(removed, key)
+					return nil
+				},
+			}
+
+			usecase := NewProcessVideoUsecase(videoRepo, storageService, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+			err := usecase.Execute(ctx, dto.VideoProcessMessage{VideoID: "video-123", UserID: "user-123"}, 1)
+
+			if err != nil {
+				t.Errorf("expected message to be acknowledged, got %v", err)
+			}
+			if len(removed) != 1 || removed[0] != "processed/user-123/video-123.zip" {
+				t.Errorf("expected outputs of the deleted video to be removed, got %v", removed)
+			}
+		})
+	}
+}
+
+func TestProcessVideoUsecase_Execute_SkipsTerminalVideos(t *testing.T) {
+	ctx := context.Background()
+
+	for _, status := range []entities.VideoStatus{entities.VideoStatusCompleted, entities.VideoStatusFailed} {
+		t.Run(string(status), func(t *testing.T) {
+			video := &entities.Video{
+				ID:     "video-123",
+				UserID: "user-123",
+				Status: status,
+			}
+
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return video, nil
+				},
+				UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+					t.Error("expected terminal video not to be updated")
+					return nil
+				},
+			}
+			storageService := &mocks.MockStorageService{
+				DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					t.Error("expected terminal video not to be downloaded")
+					return nil, errors.New("unexpected download")
+				},
+			}
+
+			usecase := NewProcessVideoUsecase(videoRepo, storageService, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+			err := usecase.Execute(ctx, dto.VideoProcessMessage{VideoID: video.ID, UserID: video.UserID}, 1)
+
+			if err != nil {
+				t.Errorf("expected duplicate message to be acknowledged, got %v", err)
+			}
+		})
+	}
+}
+
+func TestProcessVideoUsecase_Execute_StopsWhenCancelled(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name             string
+		cancelledBefore  bool
+		expectDownloaded bool
+	}{
+		{name: "should acknowledge a message for a cancelled video", cancelledBefore: true},
+		{name: "should stop between stages and remove outputs when cancelled while processing", expectDownloaded: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := &entities.Video{ID: "video-123", UserID: "user-123", OriginalName: "test.mp4", Status: entities.VideoStatusPending, Version: 1}
+			if tt.cancelledBefore {
+				stored.Status = entities.VideoStatusCancelled
+			}
+
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					video := *stored
+					return &video, nil
+				},
+				UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+					if v.ProgressPercent < extractionStartPercent {
+						stored.Version++
+						return nil
+					}
+					// The user cancels while the video is downloaded
+					stored.Status = entities.VideoStatusCancelled
+					return &ports.VersionConflictError{VideoID: v.ID, Version: v.Version}
+				},
+			}
+
+			downloaded := false
+			var removed []string
+			storageService := &mocks.MockStorageService{
+				DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+					downloaded = true
+					return io.NopCloser(strings.NewReader("video")), nil
+				},
+				DeleteFunc: func(ctx context.Context, key string) error {
+					removed = append(removed, key)
+					return nil
+				},
+			}
+			videoProber := &mocks.MockVideoProber{
+				ProbeFunc: func(ctx context.Context, source string) (*entities.MediaInfo, error) {
+					t.Error("expected cancelled video not to be probed")
+					return nil, errors.New("unexpected probe")
+				},
+			}
+
+			usecase := NewProcessVideoUsecase(videoRepo, storageService, &mocks.MockNotificationService{}, videoProber, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+			err := usecase.Execute(ctx, dto.VideoProcessMessage{VideoID: "video-123", UserID: "user-123"}, 1)
+
+			if err != nil {
+				t.Errorf("expected message to be acknowledged, got %v", err)
+			}
+			if downloaded != tt.expectDownloaded {
+				t.Errorf("expected downloaded: %v, got %v", tt.expectDownloaded, downloaded)
+			}
+			if len(removed) != 1 || removed[0] != "processed/user-123/video-123.zip" {
+				t.Errorf("expected outputs of the cancelled video to be removed, got %v", removed)
+			}
+		})
+	}
+}
+
+func TestProcessVideoUsecase_Execute_StopsExtractionWithoutProgress(t *testing.T) {
+	// ffmpeg that never reports progress, as for a video of unknown length
+	binDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(binDir, "ffmpeg"), []byte("#!/bin/sh\nexec sleep 30\n"), 0755); err != nil {
+		t.Fatalf("failed to write fake ffmpeg: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var finds atomic.Int32
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			video := &entities.Video{ID: id, UserID: "user-123", OriginalName: "test.mp4", Status: entities.VideoStatusProcessing, Version: 1}
+			if finds.Add(1) > 1 {
+				// The user cancels once extraction has started
+				video.Status = entities.VideoStatusCancelled
+			}
+			return video, nil
+		},
+	}
+	var removed []string
+	storageService := &mocks.MockStorageService{
+		DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("video")), nil
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			removed = append(removed, key)
+			return nil
+		},
+	}
+	videoProber := &mocks.MockVideoProber{
+		ProbeFunc: func(ctx context.Context, source string) (*entities.MediaInfo, error) {
+			return nil, errors.New("ffprobe not available")
+		},
+	}
+
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, &mocks.MockNotificationService{}, videoProber, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+	usecase.progressInterval = 10 * time.Millisecond
+
+	start := time.Now()
+	err := usecase.Execute(context.Background(), dto.VideoProcessMessage{VideoID: "video-123", UserID: "user-123"}, 1)
+
+	if err != nil {
+		t.Errorf("expected message to be acknowledged, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected ffmpeg to be stopped once the video was cancelled, took %v", elapsed)
+	}
+	if len(removed) != 1 || removed[0] != "processed/user-123/video-123.zip" {
+		t.Errorf("expected outputs of the cancelled video to be removed, got %v", removed)
+	}
+}
+
+func TestProcessVideoUsecase_Execute_StopsWhenFinishedConcurrently(t *testing.T) {
+	ctx := context.Background()
+
+	stored := &entities.Video{
+		ID:           "video-123",
+		UserID:       "user-123",
+		UserEmail:    "user@example.com",
+		OriginalName: "test.mp4",
+		Status:       entities.VideoStatusPending,
+		Version:      1,
+	}
+
+	notified := false
+	downloaded := false
+
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			video := *stored
+			return &video, nil
+		},
+		UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+			// Another worker completed the video after it was loaded
+			stored.Status = entities.VideoStatusCompleted
+			stored.Version = 5
+			return &ports.VersionConflictError{VideoID: v.ID, Version: v.Version}
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
+			downloaded = true
+			return nil, errors.New("download failed")
+		},
+	}
+	notificationService := &mocks.MockNotificationService{
+		SendVideoFailedNotificationFunc: func(ctx context.Context, email, videoID, originalName, errorMessage string) error {
+			notified = true
+			return nil
+		},
+	}
+
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+	err := usecase.Execute(ctx, dto.VideoProcessMessage{VideoID: stored.ID, UserID: stored.UserID, UserEmail: stored.UserEmail}, 1)
+
+	if err != nil {
+		t.Errorf("expected processing to stop without error, got %v", err)
+	}
+
+	if downloaded {
+		t.Error("expected processing to stop before downloading")
+	}
+
+	if notified {
+		t.Error("expected no failure notification for a video finished elsewhere")
+	}
+}
+
+func TestProcessVideoUsecase_UpdateVideo_RetriesOnNonTerminalConflict(t *testing.T) {
+	ctx := context.Background()
+
+	video := &entities.Video{ID: "video-123", Status: entities.VideoStatusProcessing, ProgressPercent: 45, Version: 2}
+
+	var savedVersions []int64
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return &entities.Video{ID: id, Status: entities.VideoStatusProcessing, ProgressPercent: 30, Version: 3}, nil
+		},
+		UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+			savedVersions = append(savedVersions, v.Version)
+			if v.Version != 3 {
+				return &ports.VersionConflictError{VideoID: v.ID, Version: v.Version}
+			}
+			return nil
+		},
+	}
+
+	usecase := NewProcessVideoUsecase(videoRepo, &mocks.MockStorageService{}, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+	if err := usecase.updateVideo(ctx, video); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+
+	if len(savedVersions) != 2 || savedVersions[1] != 3 {
+		t.Errorf("expected a retry against version 3, got %v", savedVersions)
+	}
+
+	if video.ProgressPercent != 45 {
+		t.Errorf("expected worker progress to be kept, got %d", video.ProgressPercent)
+	}
+}
+
 func TestProcessVideoUsecase_Execute_ExtractFramesFails(t *testing.T) {
 	// Note: This test verifies error handling when frame extraction fails
 	// Actual ffmpeg execution is skipped as it requires complex setup
@@ -126,17 +712,18 @@ func TestProcessVideoUsecase_CreateZipFile(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	notificationService := &mocks.MockNotificationService{}
 
-	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService)
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
 
 	originalName := "test-video.mp4"
-	videoData := []byte("fake video content")
-	frames := [][]byte{
+	videoPath, frames := writeTestVideoFiles(t, []byte("fake video content"), [][]byte{
 		[]byte("frame1 data"),
 		[]byte("frame2 data"),
 		[]byte("frame3 data"),
-	}
+	})
 
-	zipData, err := usecase.createZipFile(originalName, videoData, frames)
+	buf := new(bytes.Buffer)
+	err := usecase.createZipFile(buf, originalName, videoPath, frames, entities.DefaultExtractionOptions(), nil)
+	zipData := buf.Bytes()
 
 	if err != nil {
 		t.Fatalf("expected no error creating zip file, got %v", err)
@@ -157,18 +744,311 @@ func TestProcessVideoUsecase_CreateZipFile(t *testing.T) {
 	}
 }
 
+func TestProcessVideoUsecase_CreateZipFile_UsesFrameFormat(t *testing.T) {
+	usecase := NewProcessVideoUsecase(&mocks.MockVideoRepository{}, &mocks.MockStorageService{}, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+	options := entities.ExtractionOptions{IntervalSeconds: 10, Format: entities.FrameFormatPNG}.WithDefaults()
+	videoPath, frames := writeTestVideoFiles(t, []byte("fake video content"), [][]byte{[]byte("frame1 data")})
+
+	buf := new(bytes.Buffer)
+	err := usecase.createZipFile(buf, "test-video.mp4", videoPath, frames, options, nil)
+	zipData := buf.Bytes()
+
+	if err != nil {
+		t.Fatalf("expected no error creating zip file, got %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(zipData), int64(len(zipData)))
+	if err != nil {
+		t.Fatalf("failed to read zip file: %v", err)
+	}
+
+	names := map[string]bool{}
+	for _, file := range reader.File {
+		names[file.Name] = true
+	}
+
+	if !names["frames/frame_0001.png"] {
+		t.Errorf("expected PNG frame in zip, got %v", names)
+	}
+}
+
+func TestProcessVideoUsecase_CreateZipFile_WritesMetadataJSON(t *testing.T) {
+	usecase := NewProcessVideoUsecase(&mocks.MockVideoRepository{}, &mocks.MockStorageService{}, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+	videoPath, frames := writeTestVideoFiles(t, []byte("fake video content"), [][]byte{[]byte("frame1 data"), []byte("frame2 data")})
+
+	tests := []struct {
+		name     string
+		metadata *entities.VideoMetadata
+	}{
+		{
+			name:     "should describe the probed video",
+			metadata: &entities.VideoMetadata{DurationSeconds: 12.5, Container: "mov,mp4,m4a,3gp,3g2,mj2", VideoCodec: "h264", AudioCodec: "aac", Width: 1920, Height: 1080, FrameRate: 29.97, BitRate: 2500000, Rotation: 90},
+		},
+		{
+			name: "should write null when the video could not be probed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := usecase.createZipFile(buf, "test-video.mp4", videoPath, frames, entities.DefaultExtractionOptions(), tt.metadata); err != nil {
+				t.Fatalf("expected no error creating zip file, got %v", err)
+			}
+
+			reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatalf("failed to read zip file: %v", err)
+			}
+
+			var written zipMetadata
+			found := false
+			for _, file := range reader.File {
+				if file.Name != "metadata.json" {
+					continue
+				}
+				found = true
+				entry, err := file.Open()
+				if err != nil {
+					t.Fatalf("failed to open metadata.json: %v", err)
+				}
+				if err := json.NewDecoder(entry).Decode(&written); err != nil {
+					t.Fatalf("expected metadata.json to be valid JSON, got %v", err)
+				}
+				entry.Close()
+			}
+
+			if !found {
+				t.Fatal("expected metadata.json in zip")
+			}
+
+			if written.OriginalFile != "test-video.mp4" || written.FramesExtracted != 2 || written.SizeBytes != int64(len("fake video content")) {
+				t.Errorf("expected the file, frames and size described, got %+v", written)
+			}
+
+			if (written.Video == nil) != (tt.metadata == nil) || (tt.metadata != nil && *written.Video != *tt.metadata) {
+				t.Errorf("expected video metadata %+v, got %+v", tt.metadata, written.Video)
+			}
+		})
+	}
+}
+
+func TestProcessVideoUsecase_UploadZipFile(t *testing.T) {
+	var uploadedKey string
+	uploaded := new(bytes.Buffer)
+
+	storageService := &mocks.MockStorageService{
+		UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+			uploadedKey = key
+			return io.Copy(uploaded, body)
+		},
+	}
+
+	usecase := NewProcessVideoUsecase(&mocks.MockVideoRepository{}, storageService, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+	videoPath, frames := writeTestVideoFiles(t, []byte("fake video content"), [][]byte{[]byte("frame1 data"), []byte("frame2 data")})
+
+	size, err := usecase.uploadZipFile(context.Background(), "processed/user-123/video-123.zip", "test-video.mp4", videoPath, frames, entities.DefaultExtractionOptions(), nil)
+
+	if err != nil {
+		t.Fatalf("expected no error uploading zip file, got %v", err)
+	}
+
+	if size != int64(uploaded.Len()) {
+		t.Errorf("expected the zip size %d to be returned, got %d", uploaded.Len(), size)
+	}
+
+	if uploadedKey != "processed/user-123/video-123.zip" {
+		t.Errorf("expected zip to be uploaded to processed key, got '%s'", uploadedKey)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(uploaded.Bytes()), int64(uploaded.Len()))
+	if err != nil {
+		t.Fatalf("failed to read uploaded zip file: %v", err)
+	}
+
+	if len(reader.File) != 6 {
+		t.Errorf("expected video, 2 frames, metadata, metadata.json and readme in zip, got %d files", len(reader.File))
+	}
+}
+
+func TestProcessVideoUsecase_UploadZipFile_Errors(t *testing.T) {
+	t.Run("should return the upload error without leaking the writer", func(t *testing.T) {
+		storageService := &mocks.MockStorageService{
+			UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+				// Give up after the first read, like a failed part upload
+				body.Read(make([]byte, 16))
+				return 0, errors.New("upload failed")
+			},
+		}
+		usecase := NewProcessVideoUsecase(&mocks.MockVideoRepository{}, storageService, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+		videoPath, frames := writeTestVideoFiles(t, bytes.Repeat([]byte("video"), 100000), nil)
+
+		_, err := usecase.uploadZipFile(context.Background(), "processed/video.zip", "test-video.mp4", videoPath, frames, entities.DefaultExtractionOptions(), nil)
+
+		if err == nil || err.Error() != "upload failed" {
+			t.Errorf("expected upload error, got %v", err)
+		}
+	})
+
+	t.Run("should fail the upload when a frame is missing", func(t *testing.T) {
+		usecase := NewProcessVideoUsecase(&mocks.MockVideoRepository{}, &mocks.MockStorageService{}, &mocks.MockNotificationService{}, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
+
+		videoPath, _ := writeTestVideoFiles(t, []byte("fake video content"), nil)
+		frames := []string{filepath.Join(t.TempDir(), "missing.jpg")}
+
+		_, err := usecase.uploadZipFile(context.Background(), "processed/video.zip", "test-video.mp4", videoPath, frames, entities.DefaultExtractionOptions(), nil)
+
+		if err == nil {
+			t.Error("expected error when a frame cannot be read, got nil")
+		}
+	})
+}
+
+// writeTestVideoFiles writes a video and its frames to a temp dir the way
+// the worker lays them out, and returns their paths.
+func writeTestVideoFiles(t *testing.T, video []byte, frames [][]byte) (string, []string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	videoPath := filepath.Join(dir, "input.mp4")
+	if err := os.WriteFile(videoPath, video, 0644); err != nil {
+		t.Fatalf("failed to write video: %v", err)
+	}
+
+	framePaths := make([]string, len(frames))
+	for i, frame := range frames {
+		framePaths[i] = filepath.Join(dir, fmt.Sprintf("frame_%04d.jpg", i+1))
+		if err := os.WriteFile(framePaths[i], frame, 0644); err != nil {
+			t.Fatalf("failed to write frame: %v", err)
+		}
+	}
+
+	return videoPath, framePaths
+}
+
+func TestBuildExtractionCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  entities.ExtractionOptions
+		expected []string
+	}{
+		{
+			name:     "should sample one JPEG per second at best quality by default",
+			options:  entities.DefaultExtractionOptions(),
+			expected: []string{"-i input.mp4", "fps=1", "-q:v 2", "frame_%04d.jpg", "-progress pipe:1"},
+		},
+		{
+			name:     "should sample by interval",
+			options:  entities.ExtractionOptions{IntervalSeconds: 30}.WithDefaults(),
+			expected: []string{"fps=1/30"},
+		},
+		{
+			name:     "should limit the time range",
+			options:  entities.ExtractionOptions{StartSeconds: 12.5, EndSeconds: 60}.WithDefaults(),
+			expected: []string{"-ss 12.5", "-to 60", "-i input.mp4"},
+		},
+		{
+			name:     "should scale down to max dimensions",
+			options:  entities.ExtractionOptions{MaxWidth: 640}.WithDefaults(),
+			expected: []string{`scale=force_original_aspect_ratio=decrease:h=ih:w=min(iw\,640)`},
+		},
+		{
+			name:     "should select frames on scene change",
+			options:  entities.ExtractionOptions{Mode: entities.ExtractionModeScene, SceneThreshold: 0.4}.WithDefaults(),
+			expected: []string{`select=gt(scene\,0.4)`, "-fps_mode vfr"},
+		},
+		{
+			name:     "should decode keyframes only",
+			options:  entities.ExtractionOptions{Mode: entities.ExtractionModeKeyframes}.WithDefaults(),
+			expected: []string{"-skip_frame nokey -i input.mp4", "-fps_mode vfr"},
+		},
+		{
+			name:     "should encode webp with quality",
+			options:  entities.ExtractionOptions{Format: entities.FrameFormatWEBP, Quality: 75}.WithDefaults(),
+			expected: []string{"-c:v libwebp", "-quality 75", "frame_%04d.webp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputPattern := "frame_%04d." + string(tt.options.Format)
+			args := strings.Join(buildExtractionCommand(context.Background(), "input.mp4", outputPattern, tt.options).GetArgs(), " ")
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(args, expected) {
+					t.Errorf("expected ffmpeg args to contain %q, got %q", expected, args)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFrameTimestamps(t *testing.T) {
+	ffmpegLog := `[Parsed_showinfo_1 @ 0x5581] config in time_base: 1/30, frame_rate: 30/1
+[Parsed_showinfo_1 @ 0x5581] n:   0 pts:      0 pts_time:0       duration:1
+[Parsed_showinfo_1 @ 0x5581] n:   1 pts:    125 pts_time:4.16667 duration:1
+[Parsed_showinfo_1 @ 0x5581] n:   2 pts:    400 pts_time:13.3333 duration:1`
+
+	t.Run("should read showinfo timestamps and add the start offset", func(t *testing.T) {
+		options := entities.ExtractionOptions{Mode: entities.ExtractionModeScene, StartSeconds: 10}.WithDefaults()
+
+		timestamps := parseFrameTimestamps(ffmpegLog, 3, options)
+
+		expected := []float64{10, 14.16667, 23.3333}
+		for i := range expected {
+			if timestamps[i] != expected[i] {
+				t.Errorf("expected timestamp %v at %d, got %v", expected[i], i, timestamps[i])
+			}
+		}
+	})
+
+	t.Run("should read timestamps split across writes", func(t *testing.T) {
+		recorder := newFrameTimestampRecorder()
+		for i := 0; i < len(ffmpegLog); i += 7 {
+			recorder.Write([]byte(ffmpegLog[i:min(i+7, len(ffmpegLog))]))
+		}
+
+		timestamps := recorder.Timestamps(3, entities.DefaultExtractionOptions())
+
+		expected := []float64{0, 4.16667, 13.3333}
+		for i := range expected {
+			if timestamps[i] != expected[i] {
+				t.Errorf("expected timestamp %v at %d, got %v", expected[i], i, timestamps[i])
+			}
+		}
+	})
+
+	t.Run("should derive timestamps from rate when log does not match frames", func(t *testing.T) {
+		options := entities.ExtractionOptions{IntervalSeconds: 5}.WithDefaults()
+
+		timestamps := parseFrameTimestamps("", 3, options)
+
+		expected := []float64{0, 5, 10}
+		for i := range expected {
+			if timestamps[i] != expected[i] {
+				t.Errorf("expected timestamp %v at %d, got %v", expected[i], i, timestamps[i])
+			}
+		}
+	})
+}
+
 func TestProcessVideoUsecase_CreateZipFile_EmptyFrames(t *testing.T) {
 	videoRepo := &mocks.MockVideoRepository{}
 	storageService := &mocks.MockStorageService{}
 	notificationService := &mocks.MockNotificationService{}
 
-	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService)
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
 
 	originalName := "test-video.mp4"
-	videoData := []byte("fake video content")
-	frames := [][]byte{}
+	videoPath, frames := writeTestVideoFiles(t, []byte("fake video content"), [][]byte{})
 
-	zipData, err := usecase.createZipFile(originalName, videoData, frames)
+	buf := new(bytes.Buffer)
+	err := usecase.createZipFile(buf, originalName, videoPath, frames, entities.DefaultExtractionOptions(), nil)
+	zipData := buf.Bytes()
 
 	if err != nil {
 		t.Fatalf("expected no error creating zip file with empty frames, got %v", err)
@@ -184,18 +1064,20 @@ func TestProcessVideoUsecase_CreateZipFile_LargeFrames(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	notificationService := &mocks.MockNotificationService{}
 
-	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService)
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
 
 	originalName := "test-video.mp4"
-	videoData := []byte("fake video content")
 	
 	// Create 100 frames
-	frames := make([][]byte, 100)
+	frameData := make([][]byte, 100)
 	for i := 0; i < 100; i++ {
-		frames[i] = bytes.Repeat([]byte("frame"), 1000) // ~5KB per frame
+		frameData[i] = bytes.Repeat([]byte("frame"), 1000) // ~5KB per frame
 	}
+	videoPath, frames := writeTestVideoFiles(t, []byte("fake video content"), frameData)
 
-	zipData, err := usecase.createZipFile(originalName, videoData, frames)
+	buf := new(bytes.Buffer)
+	err := usecase.createZipFile(buf, originalName, videoPath, frames, entities.DefaultExtractionOptions(), nil)
+	zipData := buf.Bytes()
 
 	if err != nil {
 		t.Fatalf("expected no error creating zip file with many frames, got %v", err)
@@ -236,7 +1118,7 @@ func TestProcessVideoUsecase_NotificationSkippedWhenNoEmail(t *testing.T) {
 	}
 
 	storageService := &mocks.MockStorageService{
-		DownloadFunc: func(ctx context.Context, key string) ([]byte, error) {
+		DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
 			return nil, errors.New("download failed")
 		},
 	}
@@ -248,7 +1130,7 @@ func TestProcessVideoUsecase_NotificationSkippedWhenNoEmail(t *testing.T) {
 		},
 	}
 
-	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService)
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
 
 	message := dto.VideoProcessMessage{
 		VideoID:   videoID,
@@ -257,7 +1139,7 @@ func TestProcessVideoUsecase_NotificationSkippedWhenNoEmail(t *testing.T) {
 		RawS3Key:  "raw/user-123/test.mp4",
 	}
 
-	usecase.Execute(ctx, message)
+	usecase.Execute(ctx, message, DefaultRetryPolicy().MaxAttempts)
 
 	if notificationCalled {
 		t.Error("expected notification not to be sent when email is empty")
@@ -290,7 +1172,7 @@ func TestProcessVideoUsecase_UpdateProgressCalled(t *testing.T) {
 	}
 
 	storageService := &mocks.MockStorageService{
-		DownloadFunc: func(ctx context.Context, key string) ([]byte, error) {
+		DownloadStreamFunc: func(ctx context.Context, key string) (io.ReadCloser, error) {
 			return nil, errors.New("download failed")
 		},
 	}
@@ -301,7 +1183,7 @@ func TestProcessVideoUsecase_UpdateProgressCalled(t *testing.T) {
 		},
 	}
 
-	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService)
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
 
 	message := dto.VideoProcessMessage{
 		VideoID:   videoID,
@@ -310,7 +1192,7 @@ func TestProcessVideoUsecase_UpdateProgressCalled(t *testing.T) {
 		RawS3Key:  "raw/user-123/test.mp4",
 	}
 
-	usecase.Execute(ctx, message)
+	usecase.Execute(ctx, message, 1)
 
 	// Should have called update at least once for initial progress
 	if len(progressUpdates) == 0 {
@@ -328,7 +1210,7 @@ func TestNewProcessVideoUsecase(t *testing.T) {
 	storageService := &mocks.MockStorageService{}
 	notificationService := &mocks.MockNotificationService{}
 
-	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService)
+	usecase := NewProcessVideoUsecase(videoRepo, storageService, notificationService, &mocks.MockVideoProber{}, entities.DefaultHLSLadder(), DefaultRetryPolicy(), newTestWebhookPublisher(), &mocks.MockUsageRepository{})
 
 	if usecase == nil {
 		t.Fatal("expected usecase to be created, got nil")
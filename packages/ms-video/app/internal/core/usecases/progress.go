@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+)
+
+// ProgressUpdateInterval is the minimum time between two progress writes to
+// the repository while ffmpeg is running.
+const ProgressUpdateInterval = 5 * time.Second
+
+// Frame extraction is reported between these percentages.
+const (
+	extractionStartPercent = 30
+	extractionEndPercent   = 60
+)
+
+// progressReporter saves the progress of a video, skipping writes that come
+// less than interval after the previous one or would not move it forward.
+type progressReporter struct {
+	update     func(ctx context.Context, video *entities.Video) error
+	video      *entities.Video
+	interval   time.Duration
+	lastUpdate time.Time
+}
+
+func newProgressReporter(update func(ctx context.Context, video *entities.Video) error, video *entities.Video, interval time.Duration) *progressReporter {
+	return &progressReporter{
+		update:     update,
+		video:      video,
+		interval:   interval,
+		lastUpdate: time.Now(),
+	}
+}
+
+func (r *progressReporter) Report(ctx context.Context, percent int) {
+	if percent <= r.video.ProgressPercent || time.Since(r.lastUpdate) < r.interval {
+		return
+	}
+
+	r.video.UpdateProgress(percent, entities.VideoStatusProcessing)
+	r.lastUpdate = time.Now()
+	if err := r.update(ctx, r.video); err != nil {
+		log.Printf("Failed to update progress of video %s: %v", r.video.ID, err)
+	}
+}
+
+// Phase returns a callback that maps the completed fraction of a phase onto
+// the percentages between from and to.
+func (r *progressReporter) Phase(ctx context.Context, from, to int) func(fraction float64) {
+	return func(fraction float64) {
+		r.Report(ctx, from+int(fraction*float64(to-from)))
+	}
+}
+
+// ffmpegProgressWriter reports progress from the lines ffmpeg writes with
+// -progress.
+type ffmpegProgressWriter struct {
+	lines      lineWriter
+	duration   time.Duration
+	onProgress func(fraction float64)
+}
+
+// newFFmpegProgressWriter returns a writer that reports nothing when the
+// duration is unknown.
+func newFFmpegProgressWriter(duration time.Duration, onProgress func(fraction float64)) *ffmpegProgressWriter {
+	w := &ffmpegProgressWriter{duration: duration, onProgress: onProgress}
+	w.lines.onLine = w.handleLine
+	return w
+}
+
+func (w *ffmpegProgressWriter) Write(p []byte) (int, error) {
+	return w.lines.Write(p)
+}
+
+func (w *ffmpegProgressWriter) handleLine(line string) {
+	if w.duration <= 0 {
+		return
+	}
+
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		return
+	}
+
+	switch key {
+	// out_time_ms is in microseconds too, despite its name
+	case "out_time_us", "out_time_ms":
+		microseconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || microseconds < 0 {
+			return
+		}
+		w.onProgress(min(float64(microseconds)/float64(w.duration.Microseconds()), 1))
+	case "progress":
+		if value == "end" {
+			w.onProgress(1)
+		}
+	}
+}
+
+// extractionDuration is how much of the video extraction goes through, or 0
+// when the length of the video is unknown.
+func extractionDuration(info *entities.MediaInfo, options entities.ExtractionOptions) time.Duration {
+	if info == nil || info.Duration <= 0 {
+		return 0
+	}
+
+	end := info.Duration
+	if options.EndSeconds > 0 {
+		end = min(end, time.Duration(options.EndSeconds*float64(time.Second)))
+	}
+	return max(end-time.Duration(options.StartSeconds*float64(time.Second)), 0)
+}
+
+// lineWriter splits what is written to it into lines.
+type lineWriter struct {
+	line   []byte
+	onLine func(line string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		if c != '\n' && c != '\r' {
+			w.line = append(w.line, c)
+			continue
+		}
+		w.Flush()
+	}
+	return len(p), nil
+}
+
+// Flush hands over a last line that did not end with a newline.
+func (w *lineWriter) Flush() {
+	if len(w.line) > 0 {
+		w.onLine(strings.TrimSpace(string(w.line)))
+	}
+	w.line = w.line[:0]
+}
@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+)
+
+func TestFFmpegProgressWriter(t *testing.T) {
+	progressLog := "frame=120\nfps=30.0\nout_time_us=15000000\nout_time=00:00:15.000000\nprogress=continue\n" +
+		"frame=240\nout_time_us=30000000\nprogress=continue\n" +
+		"out_time_us=60000000\nprogress=end\n"
+
+	t.Run("should report the fraction of the duration processed", func(t *testing.T) {
+		var fractions []float64
+		writer := newFFmpegProgressWriter(time.Minute, func(fraction float64) {
+			fractions = append(fractions, fraction)
+		})
+
+		// Write in small chunks, the way a pipe may deliver it
+		for i := 0; i < len(progressLog); i += 5 {
+			writer.Write([]byte(progressLog[i:min(i+5, len(progressLog))]))
+		}
+
+		expected := []float64{0.25, 0.5, 1, 1}
+		if len(fractions) != len(expected) {
+			t.Fatalf("expected %d progress reports, got %v", len(expected), fractions)
+		}
+		for i := range expected {
+			if fractions[i] != expected[i] {
+				t.Errorf("expected fraction %v at %d, got %v", expected[i], i, fractions[i])
+			}
+		}
+	})
+
+	t.Run("should report nothing when the duration is unknown", func(t *testing.T) {
+		reported := false
+		writer := newFFmpegProgressWriter(0, func(fraction float64) {
+			reported = true
+		})
+
+		writer.Write([]byte(progressLog))
+
+		if reported {
+			t.Error("expected no progress to be reported")
+		}
+	})
+}
+
+func TestExtractionDuration(t *testing.T) {
+	info := &entities.MediaInfo{Duration: 10 * time.Minute}
+
+	tests := []struct {
+		name     string
+		info     *entities.MediaInfo
+		options  entities.ExtractionOptions
+		expected time.Duration
+	}{
+		{
+			name:     "should use the whole video by default",
+			info:     info,
+			expected: 10 * time.Minute,
+		},
+		{
+			name:     "should only count the extracted range",
+			info:     info,
+			options:  entities.ExtractionOptions{StartSeconds: 60, EndSeconds: 180},
+			expected: 2 * time.Minute,
+		},
+		{
+			name:     "should cap the range at the end of the video",
+			info:     info,
+			options:  entities.ExtractionOptions{StartSeconds: 300, EndSeconds: 3600},
+			expected: 5 * time.Minute,
+		},
+		{
+			name:     "should be unknown without media info",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractionDuration(tt.info, tt.options); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestProgressReporter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should map a phase onto its percentages", func(t *testing.T) {
+		video := &entities.Video{ID: "video-123", ProgressPercent: 30}
+		var updates []int
+		videoRepo := &mocks.MockVideoRepository{
+			UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+				updates = append(updates, v.ProgressPercent)
+				return nil
+			},
+		}
+
+		report := newProgressReporter(videoRepo.Update, video, 0).Phase(ctx, 30, 60)
+		report(0.5)
+		report(0.5)
+		report(1)
+
+		expected := []int{45, 60}
+		if len(updates) != len(expected) || updates[0] != expected[0] || updates[1] != expected[1] {
+			t.Errorf("expected updates %v, got %v", expected, updates)
+		}
+	})
+
+	t.Run("should throttle updates", func(t *testing.T) {
+		video := &entities.Video{ID: "video-123", ProgressPercent: 30}
+		updates := 0
+		videoRepo := &mocks.MockVideoRepository{
+			UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+				updates++
+				return nil
+			},
+		}
+
+		reporter := newProgressReporter(videoRepo.Update, video, time.Hour)
+		reporter.Report(ctx, 40)
+		reporter.Report(ctx, 50)
+
+		if updates != 0 {
+			t.Errorf("expected no updates within the interval, got %d", updates)
+		}
+
+		reporter.lastUpdate = time.Now().Add(-time.Hour)
+		reporter.Report(ctx, 50)
+
+		if updates != 1 || video.ProgressPercent != 50 {
+			t.Errorf("expected one update to 50%%, got %d updates at %d%%", updates, video.ProgressPercent)
+		}
+	})
+}
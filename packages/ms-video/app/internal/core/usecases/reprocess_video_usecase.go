@@ -0,0 +1,118 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// DefaultMaxProcessingAttempts caps how often a video may be queued in
+// total, the upload included.
+const DefaultMaxProcessingAttempts = 5
+
+func MaxProcessingAttemptsFromEnv() int {
+	return max(utils.GetEnvInt("VIDEO_MAX_PROCESSING_ATTEMPTS", DefaultMaxProcessingAttempts), 1)
+}
+
+type ReprocessVideoUsecase struct {
+	videoRepository ports.VideoRepository
+	storageService  ports.StorageService
+	videoQueue      ports.VideoQueue
+	videoValidator  *VideoValidator
+	usageRepository ports.UsageRepository
+	maxAttempts     int
+}
+
+func NewReprocessVideoUsecase(
+	videoRepository ports.VideoRepository,
+	storageService ports.StorageService,
+	videoQueue ports.VideoQueue,
+	videoValidator *VideoValidator,
+	usageRepository ports.UsageRepository,
+	maxAttempts int,
+) *ReprocessVideoUsecase {
+	return &ReprocessVideoUsecase{
+		videoRepository: videoRepository,
+		storageService:  storageService,
+		videoQueue:      videoQueue,
+		videoValidator:  videoValidator,
+		usageRepository: usageRepository,
+		maxAttempts:     maxAttempts,
+	}
+}
+
+// Execute queues a completed, failed, cancelled or expired video again from
+// its raw upload.
+func (u *ReprocessVideoUsecase) Execute(ctx context.Context, input dto.ReprocessVideoInput) (*dto.UploadVideoOutput, error) {
+	video, err := u.videoRepository.FindByID(ctx, input.VideoID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("video not found")
+	}
+
+	if video.UserID != input.UserID {
+		return nil, utils.NewUnauthorizedError("you don't have permission to reprocess this video")
+	}
+
+	if !video.IsTerminal() {
+		return nil, utils.NewConflictError(fmt.Sprintf("video is %s, only completed, failed, cancelled or expired videos can be reprocessed", video.Status))
+	}
+
+	if video.RawDeletedAt != nil {
+		return nil, utils.NewHttpError(http.StatusGone, "the raw upload of the video was deleted after its retention period, it can no longer be reprocessed")
+	}
+
+	if video.ProcessingAttempts >= u.maxAttempts {
+		return nil, utils.NewHttpError(http.StatusTooManyRequests,
+			fmt.Sprintf("video has already been processed %d times, the maximum allowed", video.ProcessingAttempts))
+	}
+
+	options := video.ExtractionOptions
+	if input.Options != nil {
+		options = *input.Options
+		if err := u.videoValidator.ValidateOptions(options); err != nil {
+			return nil, err
+		}
+		if _, err := u.videoValidator.Validate(ctx, video.RawS3Key, options); err != nil {
+			return nil, err
+		}
+	}
+
+	previousOutputBytes := video.OutputBytes
+	video.Reprocess(options)
+	var conflict *ports.VersionConflictError
+	if err := u.videoRepository.Update(ctx, video); errors.As(err, &conflict) {
+		return nil, utils.NewConflictError("video is being updated, try again")
+	} else if err != nil {
+		return nil, utils.NewInternalServerError("failed to save video metadata")
+	}
+
+	// The next run counts its own outputs once it completes
+	if previousOutputBytes > 0 {
+		if err := u.usageRepository.Adjust(ctx, video.UserID, -previousOutputBytes, 0); err != nil {
+			log.Printf("Failed to release previous outputs of video %s from usage: %v", video.ID, err)
+		}
+	}
+
+	if err := deleteVideoOutputs(ctx, u.storageService, video.UserID, video.ID); err != nil {
+		log.Printf("Failed to delete previous outputs of video %s: %v", video.ID, err)
+	}
+
+	if err := enqueueVideo(ctx, u.videoQueue, video); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Video %s queued for reprocessing, attempt %d", video.ID, video.ProcessingAttempts)
+
+	return &dto.UploadVideoOutput{
+		VideoID:      video.ID,
+		OriginalName: video.OriginalName,
+		Status:       string(video.Status),
+		Message:      "Video queued for reprocessing",
+	}, nil
+}
@@ -0,0 +1,247 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func newFinishedVideo(status entities.VideoStatus) *entities.Video {
+	return &entities.Video{
+		ID:                 "video-123",
+		UserID:             "user-123",
+		UserEmail:          "user@example.com",
+		OriginalName:       "test.mp4",
+		RawS3Key:           "raw/user-123/video-123/test.mp4",
+		ProcessedS3Key:     "processed/user-123/video-123.zip",
+		OutputBytes:        2048,
+		Status:             status,
+		ProgressPercent:    100,
+		ErrorMessage:       "ffmpeg exited with status 1",
+		ExtractionOptions:  entities.ExtractionOptions{FPS: 2},
+		ProcessingAttempts: 1,
+	}
+}
+
+func TestReprocessVideoUsecase_Execute_Success(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		options         *entities.ExtractionOptions
+		expectedOptions entities.ExtractionOptions
+	}{
+		{
+			name:            "should keep the previous options when none are given",
+			expectedOptions: entities.ExtractionOptions{FPS: 2},
+		},
+		{
+			name:            "should replace the options when new ones are given",
+			options:         &entities.ExtractionOptions{Mode: entities.ExtractionModeScene},
+			expectedOptions: entities.ExtractionOptions{Mode: entities.ExtractionModeScene},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := newFinishedVideo(entities.VideoStatusFailed)
+
+			var savedVideo entities.Video
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					return video, nil
+				},
+				UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+					savedVideo = *v
+					return nil
+				},
+			}
+
+			var deleted []string
+			storageService := &mocks.MockStorageService{
+				DeleteFunc: func(ctx context.Context, key string) error {
+					deleted = append(deleted, key)
+					return nil
+				},
+			}
+
+			var queuedMessage dto.VideoProcessMessage
+			videoQueue := &mocks.MockVideoQueue{
+				SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+					queuedMessage = message
+					return nil
+				},
+			}
+
+			var releasedBytes int64
+			usageRepo := &mocks.MockUsageRepository{
+				AdjustFunc: func(ctx context.Context, userID string, bytes int64, videos int) error {
+					releasedBytes = bytes
+					return nil
+				},
+			}
+
+			usecase := NewReprocessVideoUsecase(videoRepo, storageService, videoQueue, newTestVideoValidator(storageService), usageRepo, DefaultMaxProcessingAttempts)
+
+			output, err := usecase.Execute(ctx, dto.ReprocessVideoInput{VideoID: video.ID, UserID: video.UserID, Options: tt.options})
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if releasedBytes != -2048 {
+				t.Errorf("expected the previous outputs to be released, got %d bytes", releasedBytes)
+			}
+			if output.Status != string(entities.VideoStatusPending) {
+				t.Errorf("expected status pending, got %s", output.Status)
+			}
+
+			if savedVideo.Status != entities.VideoStatusPending || savedVideo.ErrorMessage != "" || savedVideo.ProcessedS3Key != "" || savedVideo.OutputBytes != 0 {
+				t.Errorf("expected video reset before saving, got %+v", savedVideo)
+			}
+			if savedVideo.ProcessingAttempts != 2 {
+				t.Errorf("expected 2 processing attempts, got %d", savedVideo.ProcessingAttempts)
+			}
+			if !reflect.DeepEqual(savedVideo.ExtractionOptions, tt.expectedOptions) {
+				t.Errorf("expected options %+v, got %+v", tt.expectedOptions, savedVideo.ExtractionOptions)
+			}
+
+			if queuedMessage.RawS3Key != video.RawS3Key {
+				t.Errorf("expected queued raw key '%s', got '%s'", video.RawS3Key, queuedMessage.RawS3Key)
+			}
+			if !reflect.DeepEqual(queuedMessage.Options, tt.expectedOptions) {
+				t.Errorf("expected queued options %+v, got %+v", tt.expectedOptions, queuedMessage.Options)
+			}
+
+			for _, key := range deleted {
+				if key == video.RawS3Key {
+					t.Error("expected raw video to be kept")
+				}
+			}
+			if !reflect.DeepEqual(deleted, []string{"processed/user-123/video-123.zip"}) {
+				t.Errorf("expected previous archive deleted, got %v", deleted)
+			}
+		})
+	}
+}
+
+func TestReprocessVideoUsecase_Execute_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		userID       string
+		findErr      error
+		prepare      func(video *entities.Video)
+		options      *entities.ExtractionOptions
+		updateErr    error
+		expectedCode int
+		expectQueued bool
+	}{
+		{
+			name:         "should return 404 when video not found",
+			userID:       "user-123",
+			findErr:      ports.ErrVideoNotFound,
+			expectedCode: 404,
+		},
+		{
+			name:         "should return 401 for another user's video",
+			userID:       "other-user",
+			expectedCode: 401,
+		},
+		{
+			name:   "should return 409 while the video is processing",
+			userID: "user-123",
+			prepare: func(video *entities.Video) {
+				video.Status = entities.VideoStatusProcessing
+			},
+			expectedCode: 409,
+		},
+		{
+			name:   "should return 429 once the attempts are used up",
+			userID: "user-123",
+			prepare: func(video *entities.Video) {
+				video.ProcessingAttempts = 3
+			},
+			expectedCode: 429,
+		},
+		{
+			name:   "should return 410 once retention deleted the raw upload",
+			userID: "user-123",
+			prepare: func(video *entities.Video) {
+				deletedAt := time.Now()
+				video.RawDeletedAt = &deletedAt
+			},
+			expectedCode: 410,
+		},
+		{
+			name:         "should return 400 for invalid options",
+			userID:       "user-123",
+			options:      &entities.ExtractionOptions{FPS: -1},
+			expectedCode: 400,
+		},
+		{
+			name:         "should return 409 when the video changed concurrently",
+			userID:       "user-123",
+			updateErr:    &ports.VersionConflictError{VideoID: "video-123", Version: 1},
+			expectedCode: 409,
+		},
+		{
+			name:         "should return 500 when the video cannot be saved",
+			userID:       "user-123",
+			updateErr:    errors.New("dynamodb unavailable"),
+			expectedCode: 500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := newFinishedVideo(entities.VideoStatusCompleted)
+			if tt.prepare != nil {
+				tt.prepare(video)
+			}
+
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					if tt.findErr != nil {
+						return nil, tt.findErr
+					}
+					return video, nil
+				},
+				UpdateFunc: func(ctx context.Context, v *entities.Video) error {
+					return tt.updateErr
+				},
+			}
+			storageService := &mocks.MockStorageService{}
+			queued := false
+			videoQueue := &mocks.MockVideoQueue{
+				SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+					queued = true
+					return nil
+				},
+			}
+
+			usecase := NewReprocessVideoUsecase(videoRepo, storageService, videoQueue, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, 3)
+
+			_, err := usecase.Execute(ctx, dto.ReprocessVideoInput{VideoID: "video-123", UserID: tt.userID, Options: tt.options})
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+			if httpErr.StatusCode != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+			}
+			if queued {
+				t.Error("expected video not to be queued")
+			}
+		})
+	}
+}
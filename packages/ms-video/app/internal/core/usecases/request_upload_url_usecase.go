@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+type RequestUploadURLUsecase struct {
+	videoRepository ports.VideoRepository
+	storageService  ports.StorageService
+	videoValidator  *VideoValidator
+	usageRepository ports.UsageRepository
+	usageLimits     entities.UsageLimits
+}
+
+func NewRequestUploadURLUsecase(
+	videoRepository ports.VideoRepository,
+	storageService ports.StorageService,
+	videoValidator *VideoValidator,
+	usageRepository ports.UsageRepository,
+	usageLimits entities.UsageLimits,
+) *RequestUploadURLUsecase {
+	return &RequestUploadURLUsecase{
+		videoRepository: videoRepository,
+		storageService:  storageService,
+		videoValidator:  videoValidator,
+		usageRepository: usageRepository,
+		usageLimits:     usageLimits,
+	}
+}
+
+func (u *RequestUploadURLUsecase) Execute(ctx context.Context, input dto.RequestUploadURLInput) (*dto.RequestUploadURLOutput, error) {
+	if input.FileName == "" {
+		return nil, utils.NewValidationError("file_name")
+	}
+
+	if input.FileSize <= 0 {
+		return nil, utils.NewValidationError("file_size")
+	}
+
+	input.FileName = filepath.Base(input.FileName)
+	if err := validateVideoSize(input.FileSize); err != nil {
+		return nil, err
+	}
+
+	if err := u.videoValidator.ValidateOptions(input.Options); err != nil {
+		return nil, err
+	}
+
+	contentType := input.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	video := entities.NewVideo(input.UserID, input.UserEmail, input.FileName, "", input.FileSize)
+	video.RawS3Key = fmt.Sprintf("raw/%s/%s/%s", input.UserID, video.ID, input.FileName)
+	video.AwaitingUpload = true
+	video.ExtractionOptions = input.Options
+
+	expirationMinutes := 60
+	uploadURL, err := u.storageService.GetPresignedUploadURL(ctx, video.RawS3Key, contentType, input.FileSize, expirationMinutes)
+	if err != nil {
+		return nil, utils.NewInternalServerError("failed to generate upload URL")
+	}
+
+	if err := reserveUpload(ctx, u.usageRepository, u.usageLimits, input.UserID, input.FileSize); err != nil {
+		return nil, err
+	}
+
+	if err := u.videoRepository.Save(ctx, video); err != nil {
+		releaseVideoUsage(ctx, u.usageRepository, video)
+		return nil, utils.NewInternalServerError("failed to save video metadata")
+	}
+
+	return &dto.RequestUploadURLOutput{
+		VideoID:   video.ID,
+		UploadURL: uploadURL,
+		Method:    "PUT",
+		Headers: map[string]string{
+			"Content-Type": contentType,
+		},
+		ExpiresIn: expirationMinutes * 60,
+	}, nil
+}
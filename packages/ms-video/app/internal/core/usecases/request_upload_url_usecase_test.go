@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func TestRequestUploadURLUsecase_Execute_Success(t *testing.T) {
+	ctx := context.Background()
+
+	var savedVideo *entities.Video
+	var presignedKey string
+	var presignedLength int64
+
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			savedVideo = video
+			return nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		GetPresignedUploadURLFunc: func(ctx context.Context, key, contentType string, contentLength int64, expirationMinutes int) (string, error) {
+			presignedKey = key
+			presignedLength = contentLength
+			return "https://s3.amazonaws.com/upload", nil
+		},
+	}
+
+	usecase := NewRequestUploadURLUsecase(videoRepo, storageService, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, DefaultUsageLimits())
+
+	output, err := usecase.Execute(ctx, dto.RequestUploadURLInput{
+		FileName:    "test.mp4",
+		ContentType: "video/mp4",
+		FileSize:    4096,
+		UserID:      "user-123",
+		UserEmail:   "user@example.com",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if savedVideo == nil {
+		t.Fatal("expected video to be saved")
+	}
+
+	if savedVideo.Status != entities.VideoStatusPending || !savedVideo.AwaitingUpload {
+		t.Errorf("expected pending video awaiting upload, got status '%s'", savedVideo.Status)
+	}
+
+	if presignedKey != savedVideo.RawS3Key || !strings.Contains(presignedKey, savedVideo.ID) {
+		t.Errorf("expected presigned key to be the reserved raw key, got '%s'", presignedKey)
+	}
+
+	if presignedLength != 4096 {
+		t.Errorf("expected presigned content length 4096, got %d", presignedLength)
+	}
+
+	if output.Method != "PUT" || output.Headers["Content-Type"] != "video/mp4" {
+		t.Errorf("expected PUT with Content-Type header, got %s %v", output.Method, output.Headers)
+	}
+}
+
+func TestRequestUploadURLUsecase_Execute_Validation(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		input dto.RequestUploadURLInput
+	}{
+		{
+			name:  "should reject missing file name",
+			input: dto.RequestUploadURLInput{FileSize: 1024},
+		},
+		{
+			name:  "should reject missing file size",
+			input: dto.RequestUploadURLInput{FileName: "test.mp4"},
+		},
+		{
+			name:  "should reject oversized files",
+			input: dto.RequestUploadURLInput{FileName: "test.mp4", FileSize: MaxVideoSize + 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usecase := NewRequestUploadURLUsecase(&mocks.MockVideoRepository{}, &mocks.MockStorageService{}, newTestVideoValidator(&mocks.MockStorageService{}), &mocks.MockUsageRepository{}, DefaultUsageLimits())
+
+			_, err := usecase.Execute(ctx, tt.input)
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+
+			if httpErr.StatusCode != 400 {
+				t.Errorf("expected status code 400, got %d", httpErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestRequestUploadURLUsecase_Execute_QuotaExceeded(t *testing.T) {
+	ctx := context.Background()
+
+	saveCalled := false
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			saveCalled = true
+			return nil
+		},
+	}
+	var reserved int64
+	usageRepo := &mocks.MockUsageRepository{
+		ReserveFunc: func(ctx context.Context, userID string, bytes int64, limits entities.UsageLimits, now time.Time) error {
+			reserved = bytes
+			return &ports.QuotaExceededError{Quota: entities.QuotaStorage}
+		},
+	}
+
+	usecase := NewRequestUploadURLUsecase(videoRepo, &mocks.MockStorageService{}, newTestVideoValidator(&mocks.MockStorageService{}), usageRepo, DefaultUsageLimits())
+
+	_, err := usecase.Execute(ctx, dto.RequestUploadURLInput{FileName: "test.mp4", FileSize: 1024, UserID: "user-123"})
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+	if httpErr.StatusCode != 413 {
+		t.Errorf("expected status code 413, got %d", httpErr.StatusCode)
+	}
+	if reserved != 1024 {
+		t.Errorf("expected the declared size to be reserved, got %d", reserved)
+	}
+	if saveCalled {
+		t.Error("expected video not to be saved")
+	}
+}
@@ -0,0 +1,329 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// ResumablePartSize is the size of the S3 parts chunks are assembled into.
+const ResumablePartSize = 8 * 1024 * 1024
+
+type ResumableUploadUsecase struct {
+	videoRepository   ports.VideoRepository
+	sessionRepository ports.UploadSessionRepository
+	storageService    ports.StorageService
+	videoQueue        ports.VideoQueue
+	videoValidator    *VideoValidator
+	usageRepository   ports.UsageRepository
+	usageLimits       entities.UsageLimits
+	deduplicator      *VideoDeduplicator
+	partSize          int
+}
+
+func NewResumableUploadUsecase(
+	videoRepository ports.VideoRepository,
+	sessionRepository ports.UploadSessionRepository,
+	storageService ports.StorageService,
+	videoQueue ports.VideoQueue,
+	videoValidator *VideoValidator,
+	usageRepository ports.UsageRepository,
+	usageLimits entities.UsageLimits,
+	deduplicator *VideoDeduplicator,
+) *ResumableUploadUsecase {
+	return &ResumableUploadUsecase{
+		videoRepository:   videoRepository,
+		sessionRepository: sessionRepository,
+		storageService:    storageService,
+		videoQueue:        videoQueue,
+		videoValidator:    videoValidator,
+		usageRepository:   usageRepository,
+		usageLimits:       usageLimits,
+		deduplicator:      deduplicator,
+		partSize:          ResumablePartSize,
+	}
+}
+
+func (u *ResumableUploadUsecase) Create(ctx context.Context, input dto.CreateResumableUploadInput) (*dto.ResumableUploadOutput, error) {
+	if input.FileName == "" {
+		return nil, utils.NewBadRequestError("missing filename in upload metadata")
+	}
+
+	if input.Length <= 0 {
+		return nil, utils.NewValidationError("Upload-Length")
+	}
+
+	input.FileName = filepath.Base(input.FileName)
+	if err := validateVideoSize(input.Length); err != nil {
+		return nil, err
+	}
+
+	if err := u.videoValidator.ValidateOptions(input.Options); err != nil {
+		return nil, err
+	}
+
+	contentType := input.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	video := entities.NewVideo(input.UserID, input.UserEmail, input.FileName, "", input.Length)
+	video.RawS3Key = fmt.Sprintf("raw/%s/%s/%s", input.UserID, video.ID, input.FileName)
+	video.AwaitingUpload = true
+	video.ExtractionOptions = input.Options
+
+	uploadID, err := u.storageService.CreateMultipartUpload(ctx, video.RawS3Key, contentType)
+	if err != nil {
+		return nil, utils.NewInternalServerError("failed to start upload")
+	}
+
+	session := entities.NewUploadSession(video.ID, input.UserID, video.RawS3Key, uploadID, input.Length)
+	video.UploadSessionID = session.ID
+
+	// Upload-Length is counted up front; the session never accepts more
+	if err := reserveUpload(ctx, u.usageRepository, u.usageLimits, input.UserID, input.Length); err != nil {
+		_ = u.storageService.AbortMultipartUpload(ctx, video.RawS3Key, uploadID)
+		return nil, err
+	}
+
+	if err := u.videoRepository.Save(ctx, video); err != nil {
+		_ = u.storageService.AbortMultipartUpload(ctx, video.RawS3Key, uploadID)
+		releaseVideoUsage(ctx, u.usageRepository, video)
+		return nil, utils.NewInternalServerError("failed to save video metadata")
+	}
+
+	if err := u.sessionRepository.Save(ctx, session); err != nil {
+		_ = u.storageService.AbortMultipartUpload(ctx, video.RawS3Key, uploadID)
+		if err := u.videoRepository.Delete(ctx, video); err != nil {
+			log.Printf("Failed to delete video %s of unsaved upload session: %v", video.ID, err)
+		}
+		releaseVideoUsage(ctx, u.usageRepository, video)
+		return nil, utils.NewInternalServerError("failed to save upload session")
+	}
+
+	return toResumableUploadOutput(session), nil
+}
+
+func (u *ResumableUploadUsecase) GetOffset(ctx context.Context, sessionID, userID string) (*dto.ResumableUploadOutput, error) {
+	session, err := u.findSession(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toResumableUploadOutput(session), nil
+}
+
+// AppendChunk writes a chunk starting at offset and completes the upload
+// once the last byte arrives.
+func (u *ResumableUploadUsecase) AppendChunk(ctx context.Context, sessionID, userID string, offset int64, chunk io.Reader) (*dto.ResumableUploadOutput, error) {
+	session, err := u.findSession(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset != session.Offset {
+		return nil, utils.NewConflictError(fmt.Sprintf("upload offset mismatch: expected %d, got %d", session.Offset, offset))
+	}
+
+	// Claim the session before writing any part
+	if err := u.updateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, u.partSize)
+	filled := 0
+	if session.TailSize > 0 {
+		tail, err := u.storageService.Download(ctx, session.TailKey())
+		if err != nil {
+			return nil, utils.NewInternalServerError("failed to load upload state")
+		}
+		filled = copy(buf, tail)
+	}
+
+	// Persist progress even when the client goes away mid-chunk
+	persistCtx := context.WithoutCancel(ctx)
+	body := io.LimitReader(chunk, session.Length-session.Offset)
+
+	var readErr error
+	for {
+		n, err := io.ReadFull(body, buf[filled:])
+		filled += n
+
+		if filled == len(buf) {
+			if err := u.flushPart(persistCtx, session, buf); err != nil {
+				return nil, err
+			}
+			filled = 0
+		}
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+				readErr = err
+			}
+			break
+		}
+	}
+
+	if session.FlushedBytes()+int64(filled) == session.Length {
+		// tus rejects chunks that run past Upload-Length
+		if n, _ := io.ReadFull(chunk, make([]byte, 1)); n > 0 {
+			return nil, utils.NewHttpError(http.StatusRequestEntityTooLarge, fmt.Sprintf("chunk exceeds the upload length of %d bytes", session.Length))
+		}
+		if filled > 0 {
+			if err := u.flushPart(persistCtx, session, buf[:filled]); err != nil {
+				return nil, err
+			}
+		}
+		if err := u.complete(persistCtx, session); err != nil {
+			return nil, err
+		}
+		return toResumableUploadOutput(session), nil
+	}
+
+	if filled > 0 {
+		if err := u.storageService.Upload(persistCtx, session.TailKey(), buf[:filled], "application/octet-stream"); err != nil {
+			return nil, utils.NewInternalServerError("failed to save upload state")
+		}
+		session.SetTail(int64(filled))
+		if err := u.updateSession(persistCtx, session); err != nil {
+			return nil, err
+		}
+	}
+
+	if readErr != nil {
+		log.Printf("Resumable upload %s interrupted at offset %d: %v", session.ID, session.Offset, readErr)
+	}
+
+	return toResumableUploadOutput(session), nil
+}
+
+func (u *ResumableUploadUsecase) Terminate(ctx context.Context, sessionID, userID string) error {
+	session, err := u.findSession(ctx, sessionID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.storageService.AbortMultipartUpload(ctx, session.RawS3Key, session.UploadID); err != nil {
+		log.Printf("Failed to abort multipart upload for session %s: %v", session.ID, err)
+	}
+	_ = u.storageService.Delete(ctx, session.TailKey())
+
+	if err := u.sessionRepository.Delete(ctx, session.ID); err != nil {
+		return utils.NewInternalServerError("failed to delete upload session")
+	}
+
+	video, err := u.videoRepository.FindByID(ctx, session.VideoID)
+	if err == nil {
+		video.MarkAsFailed("upload was terminated by the client")
+		if err := u.videoRepository.Update(ctx, video); err != nil {
+			log.Printf("Failed to mark video %s as terminated: %v", video.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (u *ResumableUploadUsecase) findSession(ctx context.Context, sessionID, userID string) (*entities.UploadSession, error) {
+	session, err := u.sessionRepository.FindByID(ctx, sessionID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("upload not found")
+	}
+
+	if session.UserID != userID {
+		return nil, utils.NewUnauthorizedError("you don't have permission to access this upload")
+	}
+
+	if time.Now().Unix() > session.ExpiresAt {
+		return nil, utils.NewHttpError(410, "upload has expired")
+	}
+
+	return session, nil
+}
+
+func (u *ResumableUploadUsecase) flushPart(ctx context.Context, session *entities.UploadSession, data []byte) error {
+	partNumber := session.NextPartNumber()
+	etag, err := u.storageService.UploadPart(ctx, session.RawS3Key, session.UploadID, partNumber, data)
+	if err != nil {
+		return utils.NewInternalServerError("failed to store upload chunk")
+	}
+
+	if err := session.HashPart(data); err != nil {
+		log.Printf("Failed to hash upload %s, it will not be deduplicated: %v", session.ID, err)
+		session.HashState = nil
+	}
+	session.AddPart(entities.UploadPart{
+		PartNumber: partNumber,
+		ETag:       etag,
+		Size:       int64(len(data)),
+	})
+	return u.updateSession(ctx, session)
+}
+
+func (u *ResumableUploadUsecase) updateSession(ctx context.Context, session *entities.UploadSession) error {
+	err := u.sessionRepository.Update(ctx, session)
+	if errors.Is(err, ports.ErrUploadSessionConflict) {
+		return utils.NewConflictError("upload is being written by another request")
+	} else if err != nil {
+		return utils.NewInternalServerError("failed to save upload session")
+	}
+	return nil
+}
+
+func (u *ResumableUploadUsecase) complete(ctx context.Context, session *entities.UploadSession) error {
+	if err := u.storageService.CompleteMultipartUpload(ctx, session.RawS3Key, session.UploadID, session.Parts); err != nil {
+		return utils.NewInternalServerError("failed to complete upload")
+	}
+	_ = u.storageService.Delete(ctx, session.TailKey())
+
+	video, err := u.videoRepository.FindByID(ctx, session.VideoID)
+	if err != nil {
+		return utils.NewInternalServerError("failed to load video metadata")
+	}
+
+	if _, err := u.videoValidator.Validate(ctx, session.RawS3Key, video.ExtractionOptions); isBadRequest(err) {
+		discardRejectedUpload(ctx, u.videoRepository, u.storageService, u.usageRepository, video, err)
+		if err := u.sessionRepository.Delete(ctx, session.ID); err != nil {
+			log.Printf("Failed to delete rejected upload session %s: %v", session.ID, err)
+		}
+		return err
+	} else if err != nil {
+		log.Printf("Could not validate resumable upload %s, queuing it anyway: %v", session.ID, err)
+	}
+
+	video.ConfirmUpload(session.Length)
+	video.ContentHash = session.ContentHash()
+	u.deduplicator.MarkDuplicate(ctx, video)
+	if err := u.videoRepository.Update(ctx, video); err != nil {
+		return utils.NewInternalServerError("failed to save video metadata")
+	}
+
+	if err := enqueueVideo(ctx, u.videoQueue, video); err != nil {
+		return err
+	}
+
+	if err := u.sessionRepository.Delete(ctx, session.ID); err != nil {
+		log.Printf("Failed to delete completed upload session %s: %v", session.ID, err)
+	}
+
+	return nil
+}
+
+func toResumableUploadOutput(session *entities.UploadSession) *dto.ResumableUploadOutput {
+	return &dto.ResumableUploadOutput{
+		UploadID:  session.ID,
+		VideoID:   session.VideoID,
+		Offset:    session.Offset,
+		Length:    session.Length,
+		ExpiresAt: time.Unix(session.ExpiresAt, 0).UTC(),
+	}
+}
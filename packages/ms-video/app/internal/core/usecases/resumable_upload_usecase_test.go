@@ -0,0 +1,365 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// resumableUploadFixture keeps the state the mocks write so a whole upload
+// can be followed across several chunks.
+type resumableUploadFixture struct {
+	video    *entities.Video
+	session  *entities.UploadSession
+	objects  map[string][]byte
+	parts    map[int32]string
+	queued   []dto.VideoProcessMessage
+	complete []entities.UploadPart
+}
+
+func newResumableUploadFixture(t *testing.T) (*resumableUploadFixture, *ResumableUploadUsecase) {
+	f := &resumableUploadFixture{
+		objects: map[string][]byte{},
+		parts:   map[int32]string{},
+	}
+
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			f.video = video
+			return nil
+		},
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return f.video, nil
+		},
+	}
+	sessionRepo := &mocks.MockUploadSessionRepository{
+		SaveFunc: func(ctx context.Context, session *entities.UploadSession) error {
+			f.session = session
+			return nil
+		},
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.UploadSession, error) {
+			return f.session, nil
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			f.session = nil
+			return nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		CreateMultipartUploadFunc: func(ctx context.Context, key, contentType string) (string, error) {
+			return "multipart-1", nil
+		},
+		UploadPartFunc: func(ctx context.Context, key, uploadID string, partNumber int32, data []byte) (string, error) {
+			f.parts[partNumber] = string(data)
+			return "etag", nil
+		},
+		CompleteMultipartUploadFunc: func(ctx context.Context, key, uploadID string, parts []entities.UploadPart) error {
+			f.complete = parts
+			return nil
+		},
+		UploadFunc: func(ctx context.Context, key string, data []byte, contentType string) error {
+			f.objects[key] = append([]byte(nil), data...)
+			return nil
+		},
+		DownloadFunc: func(ctx context.Context, key string) ([]byte, error) {
+			return f.objects[key], nil
+		},
+	}
+	videoQueue := &mocks.MockVideoQueue{
+		SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+			f.queued = append(f.queued, message)
+			return nil
+		},
+	}
+
+	usecase := NewResumableUploadUsecase(videoRepo, sessionRepo, storageService, videoQueue, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, DefaultUsageLimits(), newTestDeduplicator())
+	usecase.partSize = 4
+
+	return f, usecase
+}
+
+func TestResumableUploadUsecase_Create(t *testing.T) {
+	ctx := context.Background()
+	f, usecase := newResumableUploadFixture(t)
+
+	output, err := usecase.Create(ctx, dto.CreateResumableUploadInput{
+		FileName:  "../test.mp4",
+		Length:    10,
+		UserID:    "user-123",
+		UserEmail: "user@example.com",
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if output.Offset != 0 || output.Length != 10 {
+		t.Errorf("expected offset 0 and length 10, got %d and %d", output.Offset, output.Length)
+	}
+
+	if !f.video.AwaitingUpload {
+		t.Error("expected video to be awaiting upload")
+	}
+
+	if f.video.OriginalName != "test.mp4" {
+		t.Errorf("expected sanitized name 'test.mp4', got '%s'", f.video.OriginalName)
+	}
+
+	if f.session.UploadID != "multipart-1" || f.session.VideoID != f.video.ID || f.video.UploadSessionID != f.session.ID {
+		t.Errorf("expected session linked to video and multipart upload, got %+v", f.session)
+	}
+}
+
+func TestResumableUploadUsecase_Create_SessionSaveFails(t *testing.T) {
+	ctx := context.Background()
+
+	var deletedVideo *entities.Video
+	videoRepo := &mocks.MockVideoRepository{
+		DeleteFunc: func(ctx context.Context, video *entities.Video) error {
+			deletedVideo = video
+			return nil
+		},
+	}
+	sessionRepo := &mocks.MockUploadSessionRepository{
+		SaveFunc: func(ctx context.Context, session *entities.UploadSession) error {
+			return errors.New("dynamodb unavailable")
+		},
+	}
+	aborted := false
+	storageService := &mocks.MockStorageService{
+		CreateMultipartUploadFunc: func(ctx context.Context, key, contentType string) (string, error) {
+			return "multipart-1", nil
+		},
+		AbortMultipartUploadFunc: func(ctx context.Context, key, uploadID string) error {
+			aborted = true
+			return nil
+		},
+	}
+	var usage int64
+	var videos int
+	usageRepo := &mocks.MockUsageRepository{
+		ReserveFunc: func(ctx context.Context, userID string, bytes int64, limits entities.UsageLimits, now time.Time) error {
+			usage, videos = usage+bytes, videos+1
+			return nil
+		},
+		AdjustFunc: func(ctx context.Context, userID string, bytes int64, count int) error {
+			usage, videos = usage+bytes, videos+count
+			return nil
+		},
+	}
+
+	usecase := NewResumableUploadUsecase(videoRepo, sessionRepo, storageService, &mocks.MockVideoQueue{}, newTestVideoValidator(storageService), usageRepo, DefaultUsageLimits(), newTestDeduplicator())
+
+	_, err := usecase.Create(ctx, dto.CreateResumableUploadInput{FileName: "test.mp4", Length: 10, UserID: "user-123"})
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok || httpErr.StatusCode != 500 {
+		t.Fatalf("expected a 500 error, got %v", err)
+	}
+	if !aborted {
+		t.Error("expected the multipart upload to be aborted")
+	}
+	if deletedVideo == nil {
+		t.Error("expected the saved video to be deleted")
+	}
+	if usage != 0 || videos != 0 {
+		t.Errorf("expected the reserved usage to be released, got %d bytes and %d videos", usage, videos)
+	}
+}
+
+func TestResumableUploadUsecase_AppendChunk_AssemblesParts(t *testing.T) {
+	ctx := context.Background()
+	f, usecase := newResumableUploadFixture(t)
+
+	created, err := usecase.Create(ctx, dto.CreateResumableUploadInput{
+		FileName: "test.mp4",
+		Length:   10,
+		UserID:   "user-123",
+	})
+	if err != nil {
+		t.Fatalf("expected no error creating upload, got %v", err)
+	}
+
+	output, err := usecase.AppendChunk(ctx, created.UploadID, "user-123", 0, strings.NewReader("abcdef"))
+	if err != nil {
+		t.Fatalf("expected no error on first chunk, got %v", err)
+	}
+
+	if output.Offset != 6 {
+		t.Errorf("expected offset 6 after first chunk, got %d", output.Offset)
+	}
+
+	if f.parts[1] != "abcd" {
+		t.Errorf("expected first part 'abcd', got '%s'", f.parts[1])
+	}
+
+	if len(f.queued) != 0 {
+		t.Error("expected video not to be queued before the upload completes")
+	}
+
+	output, err = usecase.AppendChunk(ctx, created.UploadID, "user-123", 6, strings.NewReader("ghij"))
+	if err != nil {
+		t.Fatalf("expected no error on second chunk, got %v", err)
+	}
+
+	if output.Offset != 10 {
+		t.Errorf("expected offset 10 after second chunk, got %d", output.Offset)
+	}
+
+	if f.parts[2] != "efgh" || f.parts[3] != "ij" {
+		t.Errorf("expected parts 'efgh' and 'ij', got '%s' and '%s'", f.parts[2], f.parts[3])
+	}
+
+	if len(f.complete) != 3 {
+		t.Errorf("expected multipart upload completed with 3 parts, got %d", len(f.complete))
+	}
+
+	if f.video.AwaitingUpload || f.video.FileSize != 10 {
+		t.Errorf("expected video confirmed with size 10, got awaiting=%v size=%d", f.video.AwaitingUpload, f.video.FileSize)
+	}
+
+	if len(f.queued) != 1 || f.queued[0].VideoID != f.video.ID {
+		t.Errorf("expected video to be queued once, got %v", f.queued)
+	}
+
+	if f.session != nil {
+		t.Error("expected completed upload session to be deleted")
+	}
+}
+
+func TestResumableUploadUsecase_AppendChunk_OffsetMismatch(t *testing.T) {
+	ctx := context.Background()
+	_, usecase := newResumableUploadFixture(t)
+
+	created, _ := usecase.Create(ctx, dto.CreateResumableUploadInput{
+		FileName: "test.mp4",
+		Length:   10,
+		UserID:   "user-123",
+	})
+
+	_, err := usecase.AppendChunk(ctx, created.UploadID, "user-123", 5, strings.NewReader("abc"))
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != 409 {
+		t.Errorf("expected status code 409, got %d", httpErr.StatusCode)
+	}
+}
+
+func TestResumableUploadUsecase_AppendChunk_ExceedsLength(t *testing.T) {
+	ctx := context.Background()
+	f, usecase := newResumableUploadFixture(t)
+
+	created, _ := usecase.Create(ctx, dto.CreateResumableUploadInput{
+		FileName: "test.mp4",
+		Length:   6,
+		UserID:   "user-123",
+	})
+
+	_, err := usecase.AppendChunk(ctx, created.UploadID, "user-123", 0, strings.NewReader("abcdefgh"))
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok || httpErr.StatusCode != 413 {
+		t.Fatalf("expected 413, got %v", err)
+	}
+
+	if f.complete != nil || len(f.queued) != 0 {
+		t.Error("expected the upload not to be completed with a truncated chunk")
+	}
+
+	if f.session.Offset != 4 {
+		t.Errorf("expected offset to stay at the flushed part, got %d", f.session.Offset)
+	}
+}
+
+func TestResumableUploadUsecase_AppendChunk_ConcurrentChunk(t *testing.T) {
+	ctx := context.Background()
+	f, usecase := newResumableUploadFixture(t)
+
+	created, _ := usecase.Create(ctx, dto.CreateResumableUploadInput{
+		FileName: "test.mp4",
+		Length:   10,
+		UserID:   "user-123",
+	})
+	// Another request at the same offset claimed the session first
+	usecase.sessionRepository = &mocks.MockUploadSessionRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.UploadSession, error) {
+			return f.session, nil
+		},
+		UpdateFunc: func(ctx context.Context, session *entities.UploadSession) error {
+			return ports.ErrUploadSessionConflict
+		},
+	}
+
+	_, err := usecase.AppendChunk(ctx, created.UploadID, "user-123", 0, strings.NewReader("abcdef"))
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok || httpErr.StatusCode != 409 {
+		t.Fatalf("expected 409 conflict, got %v", err)
+	}
+
+	if len(f.parts) != 0 {
+		t.Errorf("expected no part written, got %v", f.parts)
+	}
+}
+
+func TestResumableUploadUsecase_AppendChunk_Expired(t *testing.T) {
+	ctx := context.Background()
+	f, usecase := newResumableUploadFixture(t)
+
+	created, _ := usecase.Create(ctx, dto.CreateResumableUploadInput{
+		FileName: "test.mp4",
+		Length:   10,
+		UserID:   "user-123",
+	})
+	f.session.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+
+	_, err := usecase.AppendChunk(ctx, created.UploadID, "user-123", 0, strings.NewReader("abc"))
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != 410 {
+		t.Errorf("expected status code 410, got %d", httpErr.StatusCode)
+	}
+}
+
+func TestResumableUploadUsecase_Terminate(t *testing.T) {
+	ctx := context.Background()
+	f, usecase := newResumableUploadFixture(t)
+
+	created, _ := usecase.Create(ctx, dto.CreateResumableUploadInput{
+		FileName: "test.mp4",
+		Length:   10,
+		UserID:   "user-123",
+	})
+
+	if err := usecase.Terminate(ctx, created.UploadID, "other-user"); err == nil {
+		t.Fatal("expected error when another user terminates the upload")
+	}
+
+	if err := usecase.Terminate(ctx, created.UploadID, "user-123"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if f.session != nil {
+		t.Error("expected upload session to be deleted")
+	}
+
+	if f.video.Status != entities.VideoStatusFailed {
+		t.Errorf("expected video status '%s', got '%s'", entities.VideoStatusFailed, f.video.Status)
+	}
+}
@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"errors"
+	"os/exec"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// IsPermanent reports whether a processing failure will happen again on
+// retry.
+func IsPermanent(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.Is(err, ports.ErrInvalidVideo) ||
+		errors.Is(err, ports.ErrObjectNotFound) ||
+		errors.As(err, &exitErr) && exitErr.ExitCode() > 0
+}
+
+// RetryError is returned by ProcessVideoUsecase.Execute when processing
+// failed for a transient reason and the message should be delivered again
+// after Delay.
+type RetryError struct {
+	Err   error
+	Delay time.Duration
+}
+
+func (e *RetryError) Error() string { return e.Err.Error() }
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// RetryPolicy decides how often and how soon a transient failure is retried.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    15 * time.Minute,
+	}
+}
+
+// RetryPolicyFromEnv reads the retry policy from the environment, falling
+// back to the defaults for anything that is not set.
+func RetryPolicyFromEnv() RetryPolicy {
+	policy := DefaultRetryPolicy()
+	return RetryPolicy{
+		MaxAttempts: max(utils.GetEnvInt("PROCESS_MAX_ATTEMPTS", policy.MaxAttempts), 1),
+		BaseDelay:   time.Duration(utils.GetEnvInt("PROCESS_RETRY_BASE_DELAY_SECONDS", int(policy.BaseDelay.Seconds()))) * time.Second,
+		MaxDelay:    time.Duration(utils.GetEnvInt("PROCESS_RETRY_MAX_DELAY_SECONDS", int(policy.MaxDelay.Seconds()))) * time.Second,
+	}
+}
+
+// Exhausted reports whether attempt, counting from 1, was the last one.
+func (p RetryPolicy) Exhausted(attempt int) bool {
+	return attempt >= p.MaxAttempts
+}
+
+// Backoff is how long to wait after a failed attempt, doubling with each
+// attempt up to MaxDelay.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
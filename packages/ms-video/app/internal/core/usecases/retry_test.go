@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 30 * time.Second, MaxDelay: 5 * time.Minute}
+
+	expected := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute}
+	for i, delay := range expected {
+		attempt := i + 1
+		if got := policy.Backoff(attempt); got != delay {
+			t.Errorf("expected backoff %v after attempt %d, got %v", delay, attempt, got)
+		}
+	}
+}
+
+func TestRetryPolicy_Exhausted(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+
+	if policy.Exhausted(2) {
+		t.Error("expected attempts to remain after attempt 2")
+	}
+
+	if !policy.Exhausted(3) {
+		t.Error("expected attempt 3 to be the last")
+	}
+}
+
+func TestIsPermanent(t *testing.T) {
+	exited := runExitError(t, "exit 1")
+	// Like ffmpeg killed by the OOM killer
+	killed := runExitError(t, "kill -KILL $$")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "should treat undecodable videos as permanent",
+			err:      fmt.Errorf("failed to probe: %w", ports.ErrInvalidVideo),
+			expected: true,
+		},
+		{
+			name:     "should treat missing raw objects as permanent",
+			err:      ports.ErrObjectNotFound,
+			expected: true,
+		},
+		{
+			name:     "should treat ffmpeg exiting with an error as permanent",
+			err:      fmt.Errorf("failed to extract frames with ffmpeg: %w", exited),
+			expected: true,
+		},
+		{
+			name:     "should treat ffmpeg killed by a signal as transient",
+			err:      fmt.Errorf("failed to extract frames with ffmpeg: %w", killed),
+			expected: false,
+		},
+		{
+			name:     "should treat timeouts as transient",
+			err:      fmt.Errorf("failed to download: %w", context.DeadlineExceeded),
+			expected: false,
+		},
+		{
+			name:     "should treat unknown errors as transient",
+			err:      errors.New("ThrottlingException: rate exceeded"),
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPermanent(tt.err); got != tt.expected {
+				t.Errorf("expected IsPermanent %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// runExitError runs script in a shell and returns the error it fails with.
+func runExitError(t *testing.T, script string) *exec.ExitError {
+	t.Helper()
+
+	var exitErr *exec.ExitError
+	if err := exec.Command("sh", "-c", script).Run(); !errors.As(err, &exitErr) {
+		t.Fatalf("expected %q to fail with an exit error, got %v", script, err)
+	}
+	return exitErr
+}
@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// retentionScanPageSize is how many videos a sweep reads from the table at
+// a time.
+const retentionScanPageSize = 100
+
+// RetentionPolicyFromEnv reads the retention periods in days.
+func RetentionPolicyFromEnv() entities.RetentionPolicy {
+	return entities.RetentionPolicy{
+		RawDays:       max(utils.GetEnvInt("RETENTION_RAW_DAYS", 0), 0),
+		ProcessedDays: max(utils.GetEnvInt("RETENTION_PROCESSED_DAYS", 0), 0),
+	}
+}
+
+type SweepRetentionUsecase struct {
+	videoRepository ports.VideoRepository
+	storageService  ports.StorageService
+	usageRepository ports.UsageRepository
+	policy          entities.RetentionPolicy
+}
+
+func NewSweepRetentionUsecase(
+	videoRepository ports.VideoRepository,
+	storageService ports.StorageService,
+	usageRepository ports.UsageRepository,
+	policy entities.RetentionPolicy,
+) *SweepRetentionUsecase {
+	return &SweepRetentionUsecase{
+		videoRepository: videoRepository,
+		storageService:  storageService,
+		usageRepository: usageRepository,
+		policy:          policy,
+	}
+}
+
+// Execute applies the retention policy to every processed video.
+func (u *SweepRetentionUsecase) Execute(ctx context.Context, dryRun bool) (*dto.RetentionSweepOutput, error) {
+	output := &dto.RetentionSweepOutput{DryRun: dryRun}
+	if u.policy == (entities.RetentionPolicy{}) {
+		log.Println("No retention period is set, nothing to sweep")
+		return output, nil
+	}
+
+	cursor := ""
+	for {
+		page, err := u.videoRepository.FindProcessed(ctx, cursor, retentionScanPageSize)
+		if err != nil {
+			return output, fmt.Errorf("failed to scan videos: %w", err)
+		}
+
+		for _, video := range page.Videos {
+			output.Scanned++
+			u.sweep(ctx, video, dryRun, output)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	return output, nil
+}
+
+func (u *SweepRetentionUsecase) sweep(ctx context.Context, video *entities.Video, dryRun bool, output *dto.RetentionSweepOutput) {
+	now := time.Now()
+	expire := u.policy.ProcessedExpired(video, now)
+	deleteRaw := u.policy.RawExpired(video, now)
+	if !expire && !deleteRaw {
+		return
+	}
+
+	// Objects are deleted before the video is saved, so a failed delete is
+	// retried by the next sweep
+	if !dryRun {
+		failed := false
+		if expire {
+			if err := deleteVideoOutputs(ctx, u.storageService, video.UserID, video.ID); err != nil {
+				log.Printf("Failed to delete expired outputs of video %s: %v", video.ID, err)
+				expire, failed = false, true
+			}
+		}
+		if deleteRaw {
+			if err := u.storageService.Delete(ctx, video.RawS3Key); err != nil {
+				log.Printf("Failed to delete raw upload %s of video %s: %v", video.RawS3Key, video.ID, err)
+				deleteRaw, failed = false, true
+			}
+		}
+		if failed {
+			output.Failed++
+		}
+		if !expire && !deleteRaw {
+			return
+		}
+	}
+
+	freed := video.StoredBytes()
+	if expire {
+		video.Expire(fmt.Sprintf("processed outputs were deleted %d days after processing, pin a video to keep them", u.policy.ProcessedDays), now)
+	}
+	if deleteRaw {
+		video.DeleteRaw(now)
+	}
+	freed -= video.StoredBytes()
+
+	if dryRun {
+		log.Printf("Would apply retention to video %s: expire outputs %v, delete raw upload %v, freeing %d bytes", video.ID, expire, deleteRaw, freed)
+		output.Expired += boolCount(expire)
+		output.RawDeleted += boolCount(deleteRaw)
+		output.FreedBytes += freed
+		return
+	}
+
+	var conflict *ports.VersionConflictError
+	if err := u.videoRepository.Update(ctx, video); errors.As(err, &conflict) {
+		log.Printf("Video %s changed since it was scanned, leaving it to the next sweep", video.ID)
+		output.Skipped++
+		return
+	} else if err != nil {
+		log.Printf("Failed to save retention of video %s: %v", video.ID, err)
+		output.Failed++
+		return
+	}
+
+	output.Expired += boolCount(expire)
+	output.RawDeleted += boolCount(deleteRaw)
+	if freed > 0 {
+		if err := u.usageRepository.Adjust(ctx, video.UserID, -freed, 0); err != nil {
+			log.Printf("Failed to release storage of video %s from usage: %v", video.ID, err)
+		}
+		output.FreedBytes += freed
+	}
+}
+
+func boolCount(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,175 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+func TestSweepRetentionUsecase_Execute(t *testing.T) {
+	ctx := context.Background()
+	policy := entities.RetentionPolicy{RawDays: 7, ProcessedDays: 30}
+	daysAgo := func(n int) *time.Time {
+		at := time.Now().Add(-time.Duration(n) * 24 * time.Hour)
+		return &at
+	}
+
+	// Completed 40 days ago: both expire. 10 days ago: only the raw upload.
+	// Pinned 40 days ago: only the raw upload. 1 day ago: nothing. All four
+	// were uploaded under the same file name.
+	newVideos := func() []*entities.Video {
+		return []*entities.Video{
+			{ID: "old", UserID: "user-123", RawS3Key: "raw/user-123/old/video.mp4", Status: entities.VideoStatusCompleted, CompletedAt: daysAgo(40), FileSize: 100, OutputBytes: 50, ProcessedS3Key: "processed/user-123/old.zip"},
+			{ID: "recent", UserID: "user-123", RawS3Key: "raw/user-123/recent/video.mp4", Status: entities.VideoStatusCompleted, CompletedAt: daysAgo(10), FileSize: 100, OutputBytes: 50},
+			{ID: "pinned", UserID: "user-123", RawS3Key: "raw/user-123/pinned/video.mp4", Status: entities.VideoStatusCompleted, CompletedAt: daysAgo(40), FileSize: 100, OutputBytes: 50, Pinned: true},
+			{ID: "new", UserID: "user-123", RawS3Key: "raw/user-123/new/video.mp4", Status: entities.VideoStatusCompleted, CompletedAt: daysAgo(1), FileSize: 100, OutputBytes: 50},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		dryRun          bool
+		conflictID      string
+		deleteErrKey    string
+		expectedDeleted []string
+		expectedUpdated []string
+		expectedExpired int
+		expectedRaw     int
+		expectedSkipped int
+		expectedFailed  int
+		expectedFreed   int64
+	}{
+		{
+			name:            "should expire outputs and delete raw uploads past their periods",
+			expectedDeleted: []string{"processed/user-123/old.zip", "raw/user-123/old/video.mp4", "raw/user-123/pinned/video.mp4", "raw/user-123/recent/video.mp4"},
+			expectedUpdated: []string{"old", "pinned", "recent"},
+			expectedExpired: 1,
+			expectedRaw:     3,
+			expectedFreed:   350,
+		},
+		{
+			name:            "should only report what would be deleted in a dry run",
+			dryRun:          true,
+			expectedExpired: 1,
+			expectedRaw:     3,
+			expectedFreed:   350,
+		},
+		{
+			name:            "should skip videos that changed since they were scanned",
+			conflictID:      "old",
+			expectedDeleted: []string{"processed/user-123/old.zip", "raw/user-123/old/video.mp4", "raw/user-123/pinned/video.mp4", "raw/user-123/recent/video.mp4"},
+			expectedUpdated: []string{"pinned", "recent"},
+			expectedRaw:     2,
+			expectedSkipped: 1,
+			expectedFreed:   200,
+		},
+		{
+			name:            "should leave a video whose objects could not be deleted to the next sweep",
+			deleteErrKey:    "raw/user-123/recent/video.mp4",
+			expectedDeleted: []string{"processed/user-123/old.zip", "raw/user-123/old/video.mp4", "raw/user-123/pinned/video.mp4"},
+			expectedUpdated: []string{"old", "pinned"},
+			expectedExpired: 1,
+			expectedRaw:     2,
+			expectedFailed:  1,
+			expectedFreed:   250,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videos := newVideos()
+			var updated, deleted []string
+			var adjusted int64
+
+			videoRepo := &mocks.MockVideoRepository{
+				FindProcessedFunc: func(ctx context.Context, cursor string, limit int) (*ports.VideoPage, error) {
+					if cursor == "" {
+						return &ports.VideoPage{Videos: videos[:2], NextCursor: "page-2"}, nil
+					}
+					return &ports.VideoPage{Videos: videos[2:]}, nil
+				},
+				UpdateFunc: func(ctx context.Context, video *entities.Video) error {
+					if video.ID == tt.conflictID {
+						return &ports.VersionConflictError{VideoID: video.ID}
+					}
+					updated = append(updated, video.ID)
+					return nil
+				},
+			}
+			storageService := &mocks.MockStorageService{
+				DeleteFunc: func(ctx context.Context, key string) error {
+					if key == tt.deleteErrKey {
+						return errors.New("s3 unavailable")
+					}
+					deleted = append(deleted, key)
+					return nil
+				},
+			}
+			usageRepo := &mocks.MockUsageRepository{
+				AdjustFunc: func(ctx context.Context, userID string, bytes int64, videos int) error {
+					adjusted += bytes
+					return nil
+				},
+			}
+
+			output, err := NewSweepRetentionUsecase(videoRepo, storageService, usageRepo, policy).Execute(ctx, tt.dryRun)
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if output.Scanned != 4 {
+				t.Errorf("expected 4 videos scanned, got %d", output.Scanned)
+			}
+			if output.Expired != tt.expectedExpired || output.RawDeleted != tt.expectedRaw || output.Skipped != tt.expectedSkipped || output.Failed != tt.expectedFailed {
+				t.Errorf("expected %d expired, %d raw deleted, %d skipped and %d failed, got %+v", tt.expectedExpired, tt.expectedRaw, tt.expectedSkipped, tt.expectedFailed, output)
+			}
+			if output.FreedBytes != tt.expectedFreed {
+				t.Errorf("expected %d bytes freed, got %d", tt.expectedFreed, output.FreedBytes)
+			}
+
+			sort.Strings(updated)
+			sort.Strings(deleted)
+			if !reflect.DeepEqual(updated, tt.expectedUpdated) {
+				t.Errorf("expected videos %v updated, got %v", tt.expectedUpdated, updated)
+			}
+			if !reflect.DeepEqual(deleted, tt.expectedDeleted) {
+				t.Errorf("expected objects %v deleted, got %v", tt.expectedDeleted, deleted)
+			}
+			if tt.dryRun && adjusted != 0 {
+				t.Errorf("expected usage untouched in a dry run, got %d", adjusted)
+			}
+			if !tt.dryRun && adjusted != -tt.expectedFreed {
+				t.Errorf("expected %d bytes released from usage, got %d", tt.expectedFreed, -adjusted)
+			}
+		})
+	}
+}
+
+func TestSweepRetentionUsecase_Execute_MarksExpiredVideos(t *testing.T) {
+	completedAt := time.Now().Add(-40 * 24 * time.Hour)
+	video := &entities.Video{ID: "old", UserID: "user-123", Status: entities.VideoStatusCompleted, CompletedAt: &completedAt, ProcessedS3Key: "processed/user-123/old.zip"}
+	videoRepo := &mocks.MockVideoRepository{
+		FindProcessedFunc: func(ctx context.Context, cursor string, limit int) (*ports.VideoPage, error) {
+			return &ports.VideoPage{Videos: []*entities.Video{video}}, nil
+		},
+	}
+
+	_, err := NewSweepRetentionUsecase(videoRepo, &mocks.MockStorageService{}, &mocks.MockUsageRepository{}, entities.RetentionPolicy{ProcessedDays: 30}).Execute(context.Background(), false)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if video.Status != entities.VideoStatusExpired || video.ExpirationReason == "" {
+		t.Errorf("expected video expired with a reason, got '%s' and '%s'", video.Status, video.ExpirationReason)
+	}
+	if video.RawDeletedAt != nil {
+		t.Error("expected the raw upload to be kept without a raw retention period")
+	}
+}
@@ -2,9 +2,9 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"path/filepath"
 
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
@@ -16,79 +16,158 @@ const (
 	MaxVideoSize = 500 * 1024 * 1024 // 500MB
 )
 
+var ErrVideoTooLarge = errors.New("video exceeds maximum allowed size")
+
 type UploadVideoUsecase struct {
 	videoRepository ports.VideoRepository
 	storageService  ports.StorageService
 	videoQueue      ports.VideoQueue
+	videoValidator  *VideoValidator
+	usageRepository ports.UsageRepository
+	usageLimits     entities.UsageLimits
+	deduplicator    *VideoDeduplicator
 }
 
 func NewUploadVideoUsecase(
 	videoRepository ports.VideoRepository,
 	storageService ports.StorageService,
 	videoQueue ports.VideoQueue,
+	videoValidator *VideoValidator,
+	usageRepository ports.UsageRepository,
+	usageLimits entities.UsageLimits,
+	deduplicator *VideoDeduplicator,
 ) *UploadVideoUsecase {
 	return &UploadVideoUsecase{
 		videoRepository: videoRepository,
 		storageService:  storageService,
 		videoQueue:      videoQueue,
+		videoValidator:  videoValidator,
+		usageRepository: usageRepository,
+		usageLimits:     usageLimits,
+		deduplicator:    deduplicator,
 	}
 }
 
 func (u *UploadVideoUsecase) Execute(ctx context.Context, input dto.UploadVideoInput) (*dto.UploadVideoOutput, error) {
-	if input.File.Size > MaxVideoSize {
-		return nil, utils.NewBadRequestError(fmt.Sprintf("file size exceeds maximum allowed size of %dMB", MaxVideoSize/(1024*1024)))
+	if err := u.videoValidator.ValidateOptions(input.Options); err != nil {
+		return nil, err
 	}
 
-	ext := filepath.Ext(input.File.Filename)
-	allowedExtensions := map[string]bool{
-		".mp4":  true,
-		".avi":  true,
-		".mov":  true,
-		".mkv":  true,
-		".webm": true,
-	}
-	if !allowedExtensions[ext] {
-		return nil, utils.NewBadRequestError("invalid video format. Allowed formats: mp4, avi, mov, mkv, webm")
+	remainingBytes, err := checkQuota(ctx, u.usageRepository, u.usageLimits, input.UserID, 0)
+	if err != nil {
+		return nil, err
 	}
 
-	file, err := input.File.Open()
-	if err != nil {
-		return nil, utils.NewInternalServerError("failed to open uploaded file")
+	video := entities.NewVideo(input.UserID, input.UserEmail, input.FileName, "", 0)
+	video.RawS3Key = fmt.Sprintf("raw/%s/%s/%s", input.UserID, video.ID, input.FileName)
+	video.ExtractionOptions = input.Options
+	rawS3Key := video.RawS3Key
+
+	maxSize := int64(MaxVideoSize)
+	cappedByQuota := remainingBytes >= 0 && remainingBytes < maxSize
+	if cappedByQuota {
+		maxSize = remainingBytes
 	}
-	defer file.Close()
 
-	fileContent, err := io.ReadAll(file)
+	hashed := newHashingReader(input.File)
+	body := &maxSizeReader{reader: hashed, remaining: maxSize}
+	fileSize, err := u.storageService.UploadStream(ctx, rawS3Key, body, input.ContentType)
 	if err != nil {
-		return nil, utils.NewInternalServerError("failed to read uploaded file")
+		if errors.Is(err, ErrVideoTooLarge) && cappedByQuota {
+			return nil, quotaError(entities.QuotaStorage, u.usageLimits)
+		}
+		if errors.Is(err, ErrVideoTooLarge) {
+			return nil, utils.NewBadRequestError(fmt.Sprintf("file size exceeds maximum allowed size of %dMB", MaxVideoSize/(1024*1024)))
+		}
+		fmt.Printf("failed to upload video to storage: %v\n", err)
+		return nil, utils.NewInternalServerError("failed to upload video to storage: " + err.Error())
 	}
 
-	rawS3Key := fmt.Sprintf("raw/%s/%s", input.UserID, input.File.Filename)
-	video := entities.NewVideo(input.UserID, input.UserEmail, input.File.Filename, rawS3Key, input.File.Size)
+	if _, err := u.videoValidator.Validate(ctx, rawS3Key, input.Options); err != nil {
+		_ = u.storageService.Delete(ctx, rawS3Key)
+		return nil, err
+	}
 
-	if err := u.storageService.Upload(ctx, rawS3Key, fileContent, input.File.Header.Get("Content-Type")); err != nil {
-		fmt.Printf("failed to upload video to storage: %v\n", err)
-		return nil, utils.NewInternalServerError("failed to upload video to storage: " + err.Error())
+	if err := reserveUpload(ctx, u.usageRepository, u.usageLimits, input.UserID, fileSize); err != nil {
+		_ = u.storageService.Delete(ctx, rawS3Key)
+		return nil, err
 	}
 
+	video.FileSize = fileSize
+	video.ContentHash = hashed.Sum()
+	source := u.deduplicator.MarkDuplicate(ctx, video)
+
 	if err := u.videoRepository.Save(ctx, video); err != nil {
 		_ = u.storageService.Delete(ctx, rawS3Key)
+		releaseVideoUsage(ctx, u.usageRepository, video)
 		return nil, utils.NewInternalServerError("failed to save video metadata")
 	}
 
+	if err := enqueueVideo(ctx, u.videoQueue, video); err != nil {
+		return nil, err
+	}
+
+	output := &dto.UploadVideoOutput{
+		VideoID:      video.ID,
+		OriginalName: video.OriginalName,
+		Status:       string(video.Status),
+		Message:      "Video uploaded successfully and queued for processing",
+		ContentHash:  video.ContentHash,
+	}
+	if source != nil {
+		output.Message = "Video uploaded successfully; identical content was already processed and its outputs will be reused"
+		output.Deduplicated = true
+		if source.UserID == video.UserID {
+			output.DuplicateOf = source.ID
+		}
+	}
+
+	return output, nil
+}
+
+// validateVideoSize checks the declared size up front.
+func validateVideoSize(fileSize int64) error {
+	if fileSize > MaxVideoSize {
+		return utils.NewBadRequestError(fmt.Sprintf("file size exceeds maximum allowed size of %dMB", MaxVideoSize/(1024*1024)))
+	}
+
+	return nil
+}
+
+// enqueueVideo sends a stored video to the processing queue.
+func enqueueVideo(ctx context.Context, videoQueue ports.VideoQueue, video *entities.Video) error {
 	queueMessage := dto.VideoProcessMessage{
 		VideoID:   video.ID,
 		UserID:    video.UserID,
 		UserEmail: video.UserEmail,
 		RawS3Key:  video.RawS3Key,
+		Options:   video.ExtractionOptions,
 	}
-	if err := u.videoQueue.Send(ctx, queueMessage); err != nil {
-		return nil, utils.NewInternalServerError("failed to queue video for processing")
+	if err := videoQueue.Send(ctx, queueMessage); err != nil {
+		return utils.NewInternalServerError("failed to queue video for processing")
 	}
 
-	return &dto.UploadVideoOutput{
-		VideoID:      video.ID,
-		OriginalName: video.OriginalName,
-		Status:       string(video.Status),
-		Message:      "Video uploaded successfully and queued for processing",
-	}, nil
+	return nil
+}
+
+// maxSizeReader fails with ErrVideoTooLarge once more than remaining bytes
+// have been read.
+type maxSizeReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *maxSizeReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, ErrVideoTooLarge
+	}
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return n, ErrVideoTooLarge
+	}
+	return n, err
 }
@@ -1,50 +1,244 @@
 package usecases
 
 import (
-	"bytes"
 	"context"
-	"mime/multipart"
-	"net/textproto"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
 	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
 	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
 )
 
-// Note: Tests that require opening the multipart file are skipped here
-// as they require complex mocking. The controller tests cover the full flow.
-// These tests focus on validation logic which is the core of the usecase.
+func TestUploadVideoUsecase_Execute_InvalidVideoContent(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		filename string
+		probe    func(ctx context.Context, source string) (*entities.MediaInfo, error)
+	}{
+		{
+			name:     "should reject renamed text file",
+			filename: "document.mp4",
+			probe: func(ctx context.Context, source string) (*entities.MediaInfo, error) {
+				return nil, ports.ErrInvalidVideo
+			},
+		},
+		{
+			name:     "should reject file without video stream",
+			filename: "song.mkv",
+			probe: func(ctx context.Context, source string) (*entities.MediaInfo, error) {
+				return &entities.MediaInfo{
+					Streams: []entities.MediaStream{{CodecType: entities.StreamTypeAudio, CodecName: "mp3"}},
+				}, nil
+			},
+		},
+	}
 
-func TestUploadVideoUsecase_Execute_FileSizeExceedsLimit(t *testing.T) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadedKey, deletedKey := "", ""
+			saveCalled := false
+			videoRepo := &mocks.MockVideoRepository{
+				SaveFunc: func(ctx context.Context, video *entities.Video) error {
+					saveCalled = true
+					return nil
+				},
+			}
+			storageService := &mocks.MockStorageService{
+				UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+					uploadedKey = key
+					return io.Copy(io.Discard, body)
+				},
+				DeleteFunc: func(ctx context.Context, key string) error {
+					deletedKey = key
+					return nil
+				},
+			}
+			videoQueue := &mocks.MockVideoQueue{}
+			validator := NewVideoValidator(&mocks.MockVideoProber{ProbeFunc: tt.probe}, storageService, DefaultVideoLimits())
+
+			usecase := NewUploadVideoUsecase(videoRepo, storageService, videoQueue, validator, &mocks.MockUsageRepository{}, DefaultUsageLimits(), newTestDeduplicator())
+
+			input := newUploadInput("fake content")
+			input.FileName = tt.filename
+
+			output, err := usecase.Execute(ctx, input)
+
+			if output != nil {
+				t.Errorf("expected nil output, got %v", output)
+			}
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+
+			if httpErr.StatusCode != 400 {
+				t.Errorf("expected status code 400, got %d", httpErr.StatusCode)
+			}
+
+			if saveCalled {
+				t.Error("expected video not to be saved")
+			}
+
+			if deletedKey == "" || deletedKey != uploadedKey {
+				t.Errorf("expected rejected object to be deleted, got '%s'", deletedKey)
+			}
+		})
+	}
+}
+
+func TestUploadVideoUsecase_Execute_RejectedUploadKeepsSameNamedVideo(t *testing.T) {
+	ctx := context.Background()
+
+	stored := map[string]bool{}
+	storageService := &mocks.MockStorageService{
+		UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+			stored[key] = true
+			return io.Copy(io.Discard, body)
+		},
+		DeleteFunc: func(ctx context.Context, key string) error {
+			delete(stored, key)
+			return nil
+		},
+	}
+
+	prober := &mocks.MockVideoProber{}
+	validator := NewVideoValidator(prober, storageService, DefaultVideoLimits())
+	var savedVideo *entities.Video
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			savedVideo = video
+			return nil
+		},
+	}
+
+	usecase := NewUploadVideoUsecase(videoRepo, storageService, &mocks.MockVideoQueue{}, validator, &mocks.MockUsageRepository{}, DefaultUsageLimits(), newTestDeduplicator())
+
+	if _, err := usecase.Execute(ctx, newUploadInput("real video")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	prober.ProbeFunc = func(ctx context.Context, source string) (*entities.MediaInfo, error) {
+		return nil, ports.ErrInvalidVideo
+	}
+	if _, err := usecase.Execute(ctx, newUploadInput("not a video")); err == nil {
+		t.Fatal("expected the second upload to be rejected")
+	}
+
+	if len(stored) != 1 || !stored[savedVideo.RawS3Key] {
+		t.Errorf("expected only the accepted upload '%s' to be stored, got %v", savedVideo.RawS3Key, stored)
+	}
+}
+
+func TestUploadVideoUsecase_Execute_AcceptsAnyExtension(t *testing.T) {
+	// Content decides whether a file is a video, not its extension
 	ctx := context.Background()
-	userID := "user-123"
-	userEmail := "user@example.com"
-	filename := "huge-video.mp4"
-	fileSize := int64(MaxVideoSize + 1) // Exceeds limit
 
-	videoRepo := &mocks.MockVideoRepository{}
 	storageService := &mocks.MockStorageService{}
-	videoQueue := &mocks.MockVideoQueue{}
+	usecase := NewUploadVideoUsecase(&mocks.MockVideoRepository{}, storageService, &mocks.MockVideoQueue{}, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, DefaultUsageLimits(), newTestDeduplicator())
+
+	input := newUploadInput("fake content")
+	input.FileName = "recording.ts"
+
+	if _, err := usecase.Execute(ctx, input); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func newUploadInput(content string) dto.UploadVideoInput {
+	return dto.UploadVideoInput{
+		File:        strings.NewReader(content),
+		FileName:    "test-video.mp4",
+		ContentType: "video/mp4",
+		UserID:      "user-123",
+		UserEmail:   "user@example.com",
+	}
+}
+
+func TestUploadVideoUsecase_Execute_Success(t *testing.T) {
+	ctx := context.Background()
+
+	var uploadedKey string
+	var savedVideo *entities.Video
+	var queuedMessage dto.VideoProcessMessage
+
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			savedVideo = video
+			return nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+			uploadedKey = key
+			return io.Copy(io.Discard, body)
+		},
+	}
+	videoQueue := &mocks.MockVideoQueue{
+		SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+			queuedMessage = message
+			return nil
+		},
+	}
+
+	usecase := NewUploadVideoUsecase(videoRepo, storageService, videoQueue, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, DefaultUsageLimits(), newTestDeduplicator())
 
-	usecase := NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
+	output, err := usecase.Execute(ctx, newUploadInput("fake video content"))
 
-	fileHeader := &multipart.FileHeader{
-		Filename: filename,
-		Size:     fileSize,
-		Header:   make(textproto.MIMEHeader),
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
 	}
 
-	input := dto.UploadVideoInput{
-		File:      fileHeader,
-		UserID:    userID,
-		UserEmail: userEmail,
+	if savedVideo == nil {
+		t.Fatal("expected video to be saved")
 	}
 
+	expectedKey := "raw/user-123/" + output.VideoID + "/test-video.mp4"
+	if uploadedKey != expectedKey || savedVideo.RawS3Key != expectedKey {
+		t.Errorf("expected raw key '%s', got '%s' and '%s'", expectedKey, uploadedKey, savedVideo.RawS3Key)
+	}
+
+	if savedVideo.FileSize != int64(len("fake video content")) {
+		t.Errorf("expected file size %d, got %d", len("fake video content"), savedVideo.FileSize)
+	}
+
+	if queuedMessage.VideoID != output.VideoID {
+		t.Errorf("expected queued video ID '%s', got '%s'", output.VideoID, queuedMessage.VideoID)
+	}
+}
+
+func TestUploadVideoUsecase_Execute_StreamExceedsLimit(t *testing.T) {
+	ctx := context.Background()
+
+	saveCalled := false
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			saveCalled = true
+			return nil
+		},
+	}
+	storageService := &mocks.MockStorageService{}
+	videoQueue := &mocks.MockVideoQueue{}
+
+	usecase := NewUploadVideoUsecase(videoRepo, storageService, videoQueue, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, DefaultUsageLimits(), newTestDeduplicator())
+
+	input := newUploadInput("")
+	input.File = io.LimitReader(zeroReader{}, MaxVideoSize+1)
+
 	output, err := usecase.Execute(ctx, input)
 
 	if err == nil {
-		t.Fatal("expected error for file size exceeding limit, got nil")
+		t.Fatal("expected error for stream exceeding limit, got nil")
 	}
 
 	if output != nil {
@@ -59,112 +253,269 @@ func TestUploadVideoUsecase_Execute_FileSizeExceedsLimit(t *testing.T) {
 	if httpErr.StatusCode != 400 {
 		t.Errorf("expected status code 400, got %d", httpErr.StatusCode)
 	}
+
+	if saveCalled {
+		t.Error("expected video not to be saved")
+	}
 }
 
-func TestUploadVideoUsecase_Execute_InvalidFileFormat(t *testing.T) {
+func TestUploadVideoUsecase_Execute_StorageUploadFails(t *testing.T) {
 	ctx := context.Background()
-	userID := "user-123"
-	userEmail := "user@example.com"
+
+	videoRepo := &mocks.MockVideoRepository{}
+	storageService := &mocks.MockStorageService{
+		UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+			return 0, errors.New("s3 unavailable")
+		},
+	}
+	videoQueue := &mocks.MockVideoQueue{}
+
+	usecase := NewUploadVideoUsecase(videoRepo, storageService, videoQueue, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, DefaultUsageLimits(), newTestDeduplicator())
+
+	_, err := usecase.Execute(ctx, newUploadInput("fake video content"))
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != 500 {
+		t.Errorf("expected status code 500, got %d", httpErr.StatusCode)
+	}
+}
+
+func TestUploadVideoUsecase_Execute_RepositorySaveFails(t *testing.T) {
+	ctx := context.Background()
+
+	rawS3Key, deletedKey := "", ""
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			rawS3Key = video.RawS3Key
+			return errors.New("dynamo unavailable")
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		DeleteFunc: func(ctx context.Context, key string) error {
+			deletedKey = key
+			return nil
+		},
+	}
+	videoQueue := &mocks.MockVideoQueue{}
+
+	usecase := NewUploadVideoUsecase(videoRepo, storageService, videoQueue, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, DefaultUsageLimits(), newTestDeduplicator())
+
+	_, err := usecase.Execute(ctx, newUploadInput("fake video content"))
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != 500 {
+		t.Errorf("expected status code 500, got %d", httpErr.StatusCode)
+	}
+
+	if deletedKey == "" || deletedKey != rawS3Key {
+		t.Errorf("expected uploaded object to be cleaned up, got '%s'", deletedKey)
+	}
+}
+
+func TestUploadVideoUsecase_Execute_QueueSendFails(t *testing.T) {
+	ctx := context.Background()
+
+	videoRepo := &mocks.MockVideoRepository{}
+	storageService := &mocks.MockStorageService{}
+	videoQueue := &mocks.MockVideoQueue{
+		SendFunc: func(ctx context.Context, message dto.VideoProcessMessage) error {
+			return errors.New("sqs unavailable")
+		},
+	}
+
+	usecase := NewUploadVideoUsecase(videoRepo, storageService, videoQueue, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, DefaultUsageLimits(), newTestDeduplicator())
+
+	_, err := usecase.Execute(ctx, newUploadInput("fake video content"))
+
+	httpErr, ok := err.(*utils.HttpError)
+	if !ok {
+		t.Fatalf("expected HttpError, got %T", err)
+	}
+
+	if httpErr.StatusCode != 500 {
+		t.Errorf("expected status code 500, got %d", httpErr.StatusCode)
+	}
+}
+
+func TestUploadVideoUsecase_Execute_QuotaExceeded(t *testing.T) {
+	ctx := context.Background()
+	limits := entities.UsageLimits{MaxStoredBytes: 1000, MaxVideos: 10, MaxUploadsPerHour: 5}
 
 	tests := []struct {
-		name     string
-		filename string
+		name          string
+		usage         *entities.Usage
+		content       string
+		reserveErr    error
+		expectedCode  int
+		expectDeleted bool
 	}{
 		{
-			name:     "should reject .txt file",
-			filename: "document.txt",
+			name:         "should return 429 when the hourly uploads are used up",
+			usage:        &entities.Usage{UploadWindow: entities.UploadWindowStart(time.Now()), WindowUploads: 5},
+			content:      "fake video content",
+			expectedCode: 429,
 		},
 		{
-			name:     "should reject .pdf file",
-			filename: "document.pdf",
+			name:         "should return 413 when the stream exceeds the storage left",
+			usage:        &entities.Usage{StoredBytes: 990},
+			content:      "fake video content",
+			expectedCode: 413,
 		},
 		{
-			name:     "should reject .jpg file",
-			filename: "image.jpg",
+			name:          "should return 429 and delete the upload when a concurrent upload took the last video",
+			usage:         &entities.Usage{},
+			content:       "fake video content",
+			reserveErr:    &ports.QuotaExceededError{Quota: entities.QuotaVideos},
+			expectedCode:  429,
+			expectDeleted: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			videoRepo := &mocks.MockVideoRepository{}
-			storageService := &mocks.MockStorageService{}
-			videoQueue := &mocks.MockVideoQueue{}
-
-			usecase := NewUploadVideoUsecase(videoRepo, storageService, videoQueue)
-
-			fileHeader := &multipart.FileHeader{
-				Filename: tt.filename,
-				Size:     1024,
-				Header:   make(textproto.MIMEHeader),
+			saveCalled := false
+			videoRepo := &mocks.MockVideoRepository{
+				SaveFunc: func(ctx context.Context, video *entities.Video) error {
+					saveCalled = true
+					return nil
+				},
 			}
-
-			input := dto.UploadVideoInput{
-				File:      fileHeader,
-				UserID:    userID,
-				UserEmail: userEmail,
+			deletedKey := ""
+			storageService := &mocks.MockStorageService{
+				UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+					return io.Copy(io.Discard, body)
+				},
+				DeleteFunc: func(ctx context.Context, key string) error {
+					deletedKey = key
+					return nil
+				},
 			}
-
-			output, err := usecase.Execute(ctx, input)
-
-			if err == nil {
-				t.Fatal("expected error for invalid file format, got nil")
+			usageRepo := &mocks.MockUsageRepository{
+				GetFunc: func(ctx context.Context, userID string) (*entities.Usage, error) {
+					return tt.usage, nil
+				},
+				ReserveFunc: func(ctx context.Context, userID string, bytes int64, limits entities.UsageLimits, now time.Time) error {
+					return tt.reserveErr
+				},
 			}
 
-			if output != nil {
-				t.Errorf("expected nil output, got %v", output)
-			}
+			usecase := NewUploadVideoUsecase(videoRepo, storageService, &mocks.MockVideoQueue{}, newTestVideoValidator(storageService), usageRepo, limits, newTestDeduplicator())
+
+			_, err := usecase.Execute(ctx, newUploadInput(tt.content))
 
 			httpErr, ok := err.(*utils.HttpError)
 			if !ok {
 				t.Fatalf("expected HttpError, got %T", err)
 			}
-
-			if httpErr.StatusCode != 400 {
-				t.Errorf("expected status code 400, got %d", httpErr.StatusCode)
+			if httpErr.StatusCode != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+			}
+			if saveCalled {
+				t.Error("expected video not to be saved")
+			}
+			if tt.expectDeleted != (deletedKey != "") {
+				t.Errorf("expected deleted %v, got '%s'", tt.expectDeleted, deletedKey)
 			}
 		})
 	}
 }
 
-func TestUploadVideoUsecase_Execute_ValidFormats(t *testing.T) {
-	// Note: This test only verifies format validation logic
-	// Full integration is tested in controller tests
-	validFormats := []string{".mp4", ".avi", ".mov", ".mkv", ".webm"}
-
-	for _, ext := range validFormats {
-		t.Run("format "+ext+" should pass validation", func(t *testing.T) {
-			// This test verifies the format is in the allowed list
-			allowedExtensions := map[string]bool{
-				".mp4":  true,
-				".avi":  true,
-				".mov":  true,
-				".mkv":  true,
-				".webm": true,
-			}
-			
-			if !allowedExtensions[ext] {
-				t.Errorf("format %s should be allowed", ext)
+func TestUploadVideoUsecase_Execute_ReleasesUsageWhenSaveFails(t *testing.T) {
+	ctx := context.Background()
+
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			return errors.New("dynamodb error")
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+			return io.Copy(io.Discard, body)
+		},
+	}
+	var reserved, released int64
+	usageRepo := &mocks.MockUsageRepository{
+		ReserveFunc: func(ctx context.Context, userID string, bytes int64, limits entities.UsageLimits, now time.Time) error {
+			reserved = bytes
+			return nil
+		},
+		AdjustFunc: func(ctx context.Context, userID string, bytes int64, videos int) error {
+			if videos != -1 {
+				t.Errorf("expected the video to be released, got %d", videos)
 			}
-		})
+			released = -bytes
+			return nil
+		},
 	}
-}
 
-func TestUploadVideoUsecase_Execute_StorageUploadFails(t *testing.T) {
-	// Note: Testing storage failure requires file opening which is complex to mock
-	// This test validates the error handling pattern
-	t.Skip("Skipping test that requires file opening - covered by controller tests")
-}
+	usecase := NewUploadVideoUsecase(videoRepo, storageService, &mocks.MockVideoQueue{}, newTestVideoValidator(storageService), usageRepo, DefaultUsageLimits(), newTestDeduplicator())
 
-func TestUploadVideoUsecase_Execute_RepositorySaveFails(t *testing.T) {
-	// Note: Testing repository failure requires file opening which is complex to mock
-	// This test validates the error handling and cleanup pattern
-	t.Skip("Skipping test that requires file opening - covered by controller tests")
+	if _, err := usecase.Execute(ctx, newUploadInput("fake video content")); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if reserved != int64(len("fake video content")) || released != reserved {
+		t.Errorf("expected the %d reserved bytes to be released, got %d", reserved, released)
+	}
 }
 
-func TestUploadVideoUsecase_Execute_QueueSendFails(t *testing.T) {
-	// Note: Testing queue failure requires file opening which is complex to mock
-	// This test validates the error handling pattern
-	t.Skip("Skipping test that requires file opening - covered by controller tests")
+func TestUploadVideoUsecase_Execute_ReportsDuplicate(t *testing.T) {
+	ctx := context.Background()
+	sum := sha256.Sum256([]byte("fake video content"))
+	contentHash := hex.EncodeToString(sum[:])
+
+	var savedVideo *entities.Video
+	videoRepo := &mocks.MockVideoRepository{
+		SaveFunc: func(ctx context.Context, video *entities.Video) error {
+			savedVideo = video
+			return nil
+		},
+		FindByContentHashFunc: func(ctx context.Context, hash string, limit int) ([]*entities.Video, error) {
+			if hash != contentHash {
+				return nil, nil
+			}
+			return []*entities.Video{{
+				ID:             "previous-video",
+				UserID:         "user-123",
+				Status:         entities.VideoStatusCompleted,
+				ProcessedS3Key: "processed/user-123/previous-video.zip",
+				ContentHash:    contentHash,
+			}}, nil
+		},
+	}
+	storageService := &mocks.MockStorageService{
+		UploadStreamFunc: func(ctx context.Context, key string, body io.Reader, contentType string) (int64, error) {
+			return io.Copy(io.Discard, body)
+		},
+	}
+
+	usecase := NewUploadVideoUsecase(videoRepo, storageService, &mocks.MockVideoQueue{}, newTestVideoValidator(storageService), &mocks.MockUsageRepository{}, DefaultUsageLimits(), NewVideoDeduplicator(videoRepo, false))
+
+	output, err := usecase.Execute(ctx, newUploadInput("fake video content"))
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if savedVideo.ContentHash != contentHash || output.ContentHash != contentHash {
+		t.Errorf("expected content hash %s, got %s and %s", contentHash, savedVideo.ContentHash, output.ContentHash)
+	}
+
+	if savedVideo.DuplicateOf != "previous-video" {
+		t.Errorf("expected video saved as a duplicate of previous-video, got '%s'", savedVideo.DuplicateOf)
+	}
+
+	if !output.Deduplicated || output.DuplicateOf != "previous-video" {
+		t.Errorf("expected duplicate of previous-video to be reported, got %+v", output)
+	}
 }
 
 // Test constants and validation logic
@@ -175,16 +526,10 @@ func TestUploadVideoUsecase_MaxVideoSizeConstant(t *testing.T) {
 	}
 }
 
-// Helper to make mockFileHeader work properly with file reading
-type mockFile struct {
-	*bytes.Reader
-}
-
-func (m *mockFile) Close() error {
-	return nil
-}
+// zeroReader produces an endless stream of zero bytes
+type zeroReader struct{}
 
-func init() {
-	// Override the multipart.FileHeader.Open method behavior
-	// This is a workaround for testing since we can't easily create a real multipart file
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
 }
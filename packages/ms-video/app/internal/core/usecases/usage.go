@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func DefaultUsageLimits() entities.UsageLimits {
+	return entities.UsageLimits{
+		MaxStoredBytes:    10 * 1024 * 1024 * 1024, // 10GB
+		MaxVideos:         100,
+		MaxUploadsPerHour: 20,
+	}
+}
+
+// UsageLimitsFromEnv reads the per-user quotas from the environment.
+func UsageLimitsFromEnv() entities.UsageLimits {
+	defaults := DefaultUsageLimits()
+	return entities.UsageLimits{
+		MaxStoredBytes:    int64(utils.GetEnvInt("USER_QUOTA_STORAGE_MB", int(defaults.MaxStoredBytes/(1024*1024)))) * 1024 * 1024,
+		MaxVideos:         utils.GetEnvInt("USER_QUOTA_VIDEOS", defaults.MaxVideos),
+		MaxUploadsPerHour: utils.GetEnvInt("USER_QUOTA_UPLOADS_PER_HOUR", defaults.MaxUploadsPerHour),
+	}
+}
+
+// checkQuota rejects an upload that would exceed a quota and returns the
+// bytes the user has left, or -1 without a storage limit.
+func checkQuota(ctx context.Context, usageRepository ports.UsageRepository, limits entities.UsageLimits, userID string, bytes int64) (int64, error) {
+	usage, err := usageRepository.Get(ctx, userID)
+	if err != nil {
+		return 0, utils.NewInternalServerError("failed to load usage")
+	}
+
+	if quota := usage.Exceeded(limits, bytes, time.Now()); quota != "" {
+		return 0, quotaError(quota, limits)
+	}
+
+	return usage.RemainingBytes(limits), nil
+}
+
+// reserveUpload atomically counts a new video of size bytes against the
+// user's quotas.
+func reserveUpload(ctx context.Context, usageRepository ports.UsageRepository, limits entities.UsageLimits, userID string, bytes int64) error {
+	err := usageRepository.Reserve(ctx, userID, bytes, limits, time.Now())
+	var exceeded *ports.QuotaExceededError
+	if errors.As(err, &exceeded) {
+		return quotaError(exceeded.Quota, limits)
+	}
+	if err != nil {
+		return utils.NewInternalServerError("failed to update usage")
+	}
+
+	return nil
+}
+
+// releaseVideoUsage stops counting a video that no longer exists against the
+// quotas of its owner.
+func releaseVideoUsage(ctx context.Context, usageRepository ports.UsageRepository, video *entities.Video) {
+	if err := usageRepository.Adjust(ctx, video.UserID, -video.StoredBytes(), -1); err != nil {
+		log.Printf("Failed to release usage of video %s: %v", video.ID, err)
+	}
+}
+
+// quotaError is 413 when the video does not fit in the storage left, and 429
+// for the quotas that free up over time or by deleting videos.
+func quotaError(quota entities.Quota, limits entities.UsageLimits) error {
+	switch quota {
+	case entities.QuotaStorage:
+		return utils.NewHttpError(http.StatusRequestEntityTooLarge, fmt.Sprintf("storage quota of %dMB exceeded", limits.MaxStoredBytes/(1024*1024)))
+	case entities.QuotaVideos:
+		return utils.NewHttpError(http.StatusTooManyRequests, fmt.Sprintf("video quota of %d videos exceeded", limits.MaxVideos))
+	default:
+		return utils.NewHttpError(http.StatusTooManyRequests, fmt.Sprintf("upload quota of %d uploads per hour exceeded", limits.MaxUploadsPerHour))
+	}
+}
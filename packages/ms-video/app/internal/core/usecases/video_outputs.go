@@ -0,0 +1,52 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// Storage keys of everything processing produces for a video.
+
+func videoProcessedKey(userID, videoID string) string {
+	return fmt.Sprintf("processed/%s/%s.zip", userID, videoID)
+}
+
+// videoFramesPrefix keeps the frames next to the ZIP they are also in.
+func videoFramesPrefix(userID, videoID string) string {
+	return fmt.Sprintf("processed/%s/%s/frames/", userID, videoID)
+}
+
+func videoPreviewsPrefix(userID, videoID string) string {
+	return fmt.Sprintf("previews/%s/%s/", userID, videoID)
+}
+
+func videoHLSPrefix(userID, videoID string) string {
+	return fmt.Sprintf("hls/%s/%s/", userID, videoID)
+}
+
+// deleteVideoOutputs removes the processed ZIP, frames, previews and HLS
+// renditions of the video.
+func deleteVideoOutputs(ctx context.Context, storageService ports.StorageService, userID, videoID string) error {
+	if err := storageService.Delete(ctx, videoProcessedKey(userID, videoID)); err != nil {
+		return fmt.Errorf("failed to delete processed video: %w", err)
+	}
+
+	for _, prefix := range []string{videoFramesPrefix(userID, videoID), videoPreviewsPrefix(userID, videoID), videoHLSPrefix(userID, videoID)} {
+		if err := storageService.DeletePrefix(ctx, prefix); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", prefix, err)
+		}
+	}
+
+	return nil
+}
+
+// expiredError answers requests for the outputs of a video that retention
+// deleted, with the reason recorded on the video.
+func expiredError(video *entities.Video) error {
+	return utils.NewHttpError(http.StatusGone, "video outputs have expired: "+video.ExpirationReason)
+}
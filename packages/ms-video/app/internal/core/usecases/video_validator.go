@@ -0,0 +1,198 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// VideoLimits bounds what an upload may contain; zero disables a check.
+type VideoLimits struct {
+	MaxDuration time.Duration
+	MaxWidth    int
+	MaxHeight   int
+	MaxStreams  int
+	MaxFPS      float64
+	MaxFrames   int
+}
+
+func DefaultVideoLimits() VideoLimits {
+	return VideoLimits{
+		MaxDuration: 2 * time.Hour,
+		MaxWidth:    3840,
+		MaxHeight:   2160,
+		MaxStreams:  8,
+		MaxFPS:      30,
+		MaxFrames:   20000,
+	}
+}
+
+func VideoLimitsFromEnv() VideoLimits {
+	defaults := DefaultVideoLimits()
+	return VideoLimits{
+		MaxDuration: time.Duration(utils.GetEnvInt("VIDEO_MAX_DURATION_SECONDS", int(defaults.MaxDuration/time.Second))) * time.Second,
+		MaxWidth:    utils.GetEnvInt("VIDEO_MAX_WIDTH", defaults.MaxWidth),
+		MaxHeight:   utils.GetEnvInt("VIDEO_MAX_HEIGHT", defaults.MaxHeight),
+		MaxStreams:  utils.GetEnvInt("VIDEO_MAX_STREAMS", defaults.MaxStreams),
+		MaxFPS:      utils.GetEnvFloat("EXTRACTION_MAX_FPS", defaults.MaxFPS),
+		MaxFrames:   utils.GetEnvInt("EXTRACTION_MAX_FRAMES", defaults.MaxFrames),
+	}
+}
+
+// VideoValidator checks the content of an uploaded raw object.
+type VideoValidator struct {
+	prober         ports.VideoProber
+	storageService ports.StorageService
+	limits         VideoLimits
+}
+
+func NewVideoValidator(
+	prober ports.VideoProber,
+	storageService ports.StorageService,
+	limits VideoLimits,
+) *VideoValidator {
+	return &VideoValidator{
+		prober:         prober,
+		storageService: storageService,
+		limits:         limits,
+	}
+}
+
+// ValidateOptions checks extraction options before anything is uploaded.
+func (v *VideoValidator) ValidateOptions(options entities.ExtractionOptions) error {
+	if err := options.Validate(); err != nil {
+		return utils.NewBadRequestError("invalid extraction options: " + err.Error())
+	}
+
+	if v.limits.MaxFPS > 0 && options.IsFixedRate() && options.FrameRate() > v.limits.MaxFPS {
+		return utils.NewBadRequestError(fmt.Sprintf("invalid extraction options: frame rate exceeds maximum of %g fps", v.limits.MaxFPS))
+	}
+
+	return nil
+}
+
+// Validate probes the stored object at rawS3Key and checks it against the
+// limits.
+func (v *VideoValidator) Validate(ctx context.Context, rawS3Key string, options entities.ExtractionOptions) (*entities.MediaInfo, error) {
+	sourceURL, err := v.storageService.GetPresignedURL(ctx, rawS3Key, 15)
+	if err != nil {
+		return nil, utils.NewInternalServerError("failed to access uploaded video")
+	}
+
+	info, err := v.prober.Probe(ctx, sourceURL)
+	if errors.Is(err, ports.ErrInvalidVideo) {
+		return nil, utils.NewBadRequestError("file is not a valid video: its container could not be decoded")
+	}
+	if err != nil {
+		log.Printf("Failed to probe video %s: %v", rawS3Key, err)
+		return nil, utils.NewInternalServerError("failed to inspect uploaded video")
+	}
+
+	if err := v.checkLimits(info); err != nil {
+		return nil, err
+	}
+
+	if err := v.checkFrameBudget(info, options); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func (v *VideoValidator) checkLimits(info *entities.MediaInfo) error {
+	stream := info.VideoStream()
+	if stream == nil || stream.CodecName == "" {
+		return utils.NewBadRequestError("file does not contain a decodable video stream")
+	}
+
+	if v.limits.MaxDuration > 0 && info.Duration > v.limits.MaxDuration {
+		return utils.NewBadRequestError(fmt.Sprintf("video duration %s exceeds maximum allowed duration of %s",
+			info.Duration.Round(time.Second), v.limits.MaxDuration))
+	}
+
+	longSide, shortSide := max(stream.Width, stream.Height), min(stream.Width, stream.Height)
+	maxLong, maxShort := max(v.limits.MaxWidth, v.limits.MaxHeight), min(v.limits.MaxWidth, v.limits.MaxHeight)
+	if maxShort > 0 && (longSide > maxLong || shortSide > maxShort) {
+		return utils.NewBadRequestError(fmt.Sprintf("video resolution %dx%d exceeds maximum allowed resolution of %dx%d",
+			stream.Width, stream.Height, v.limits.MaxWidth, v.limits.MaxHeight))
+	}
+
+	if v.limits.MaxStreams > 0 && len(info.Streams) > v.limits.MaxStreams {
+		return utils.NewBadRequestError(fmt.Sprintf("video has %d streams, maximum allowed is %d",
+			len(info.Streams), v.limits.MaxStreams))
+	}
+
+	return nil
+}
+
+// checkFrameBudget estimates how many frames the options produce for this
+// video. A fixed rate over a video of unknown length needs an end_seconds to
+// be estimated.
+func (v *VideoValidator) checkFrameBudget(info *entities.MediaInfo, options entities.ExtractionOptions) error {
+	end := options.EndSeconds
+	if info.Duration > 0 {
+		duration := info.Duration.Seconds()
+		if options.StartSeconds >= duration {
+			return utils.NewBadRequestError(fmt.Sprintf("invalid extraction options: start_seconds is past the end of the %.0fs video", duration))
+		}
+		if end <= 0 || end > duration {
+			end = duration
+		}
+	}
+
+	if !options.IsFixedRate() || v.limits.MaxFrames <= 0 {
+		return nil
+	}
+
+	if end <= 0 {
+		return utils.NewBadRequestError("invalid extraction options: the length of the video is unknown, so a fixed rate needs end_seconds. Set end_seconds or use scene or keyframes mode")
+	}
+
+	frames := int((end - options.StartSeconds) * options.FrameRate())
+	if frames > v.limits.MaxFrames {
+		return utils.NewBadRequestError(fmt.Sprintf("invalid extraction options: about %d frames would be extracted, maximum is %d. Use a lower fps, a longer interval or a shorter time range",
+			frames, v.limits.MaxFrames))
+	}
+
+	return nil
+}
+
+// discardRejectedUpload removes a raw object that failed validation, records
+// why on the video and stops counting the object against the storage quota.
+func discardRejectedUpload(ctx context.Context, videoRepository ports.VideoRepository, storageService ports.StorageService, usageRepository ports.UsageRepository, video *entities.Video, reason error) {
+	rawDeleted := true
+	if err := storageService.Delete(ctx, video.RawS3Key); err != nil {
+		log.Printf("Failed to delete rejected upload %s: %v", video.RawS3Key, err)
+		rawDeleted = false
+	}
+
+	freed := video.StoredBytes()
+	video.MarkAsFailed(reason.Error())
+	if rawDeleted {
+		video.DeleteRaw(time.Now())
+	}
+	freed -= video.StoredBytes()
+
+	if err := videoRepository.Update(ctx, video); err != nil {
+		log.Printf("Failed to mark video %s as rejected: %v", video.ID, err)
+		return
+	}
+	if freed > 0 {
+		if err := usageRepository.Adjust(ctx, video.UserID, -freed, 0); err != nil {
+			log.Printf("Failed to release rejected upload of video %s from usage: %v", video.ID, err)
+		}
+	}
+}
+
+// isBadRequest reports whether err is a client error, as opposed to a
+// failure on our side that the client can retry.
+func isBadRequest(err error) bool {
+	var httpErr *utils.HttpError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == 400
+}
@@ -0,0 +1,270 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+// newTestVideoValidator accepts every upload as a short 720p video
+func newTestVideoValidator(storageService ports.StorageService) *VideoValidator {
+	return NewVideoValidator(&mocks.MockVideoProber{}, storageService, DefaultVideoLimits())
+}
+
+func newMediaInfo(duration time.Duration, width, height, streams int) *entities.MediaInfo {
+	info := &entities.MediaInfo{
+		FormatName: "matroska,webm",
+		Duration:   duration,
+		Streams: []entities.MediaStream{
+			{Index: 0, CodecType: entities.StreamTypeVideo, CodecName: "vp9", Width: width, Height: height},
+		},
+	}
+	for i := 1; i < streams; i++ {
+		info.Streams = append(info.Streams, entities.MediaStream{Index: i, CodecType: entities.StreamTypeAudio, CodecName: "opus"})
+	}
+	return info
+}
+
+func TestVideoValidator_Validate(t *testing.T) {
+	ctx := context.Background()
+
+	limits := VideoLimits{
+		MaxDuration: time.Hour,
+		MaxWidth:    1920,
+		MaxHeight:   1080,
+		MaxStreams:  3,
+		MaxFrames:   1000,
+	}
+
+	tests := []struct {
+		name           string
+		info           *entities.MediaInfo
+		probeErr       error
+		options        entities.ExtractionOptions
+		limits         VideoLimits
+		expectedStatus int
+	}{
+		{
+			name:   "should accept video within limits",
+			info:   newMediaInfo(10*time.Minute, 1920, 1080, 2),
+			limits: limits,
+		},
+		{
+			name:   "should accept portrait video within limits",
+			info:   newMediaInfo(time.Minute, 1080, 1920, 1),
+			limits: limits,
+		},
+		{
+			name:           "should reject undecodable file",
+			probeErr:       ports.ErrInvalidVideo,
+			limits:         limits,
+			expectedStatus: 400,
+		},
+		{
+			name:           "should return internal error when probe fails",
+			probeErr:       errors.New("ffprobe not found"),
+			limits:         limits,
+			expectedStatus: 500,
+		},
+		{
+			name:           "should reject video stream without codec",
+			info:           &entities.MediaInfo{Streams: []entities.MediaStream{{CodecType: entities.StreamTypeVideo}}},
+			limits:         limits,
+			expectedStatus: 400,
+		},
+		{
+			name:           "should reject video that is too long",
+			info:           newMediaInfo(2*time.Hour, 1280, 720, 1),
+			limits:         limits,
+			expectedStatus: 400,
+		},
+		{
+			name:           "should reject video with too high resolution",
+			info:           newMediaInfo(time.Minute, 3840, 2160, 1),
+			limits:         limits,
+			expectedStatus: 400,
+		},
+		{
+			name:           "should reject video with too many streams",
+			info:           newMediaInfo(time.Minute, 1280, 720, 4),
+			limits:         limits,
+			expectedStatus: 400,
+		},
+		{
+			name:           "should reject options that extract too many frames",
+			info:           newMediaInfo(30*time.Minute, 1280, 720, 1),
+			options:        entities.ExtractionOptions{FPS: 1},
+			limits:         limits,
+			expectedStatus: 400,
+		},
+		{
+			name:    "should count frames only within the time range",
+			info:    newMediaInfo(30*time.Minute, 1280, 720, 1),
+			options: entities.ExtractionOptions{FPS: 1, StartSeconds: 60, EndSeconds: 600},
+			limits:  limits,
+		},
+		{
+			name:    "should accept sparse sampling of long video",
+			info:    newMediaInfo(30*time.Minute, 1280, 720, 1),
+			options: entities.ExtractionOptions{IntervalSeconds: 10},
+			limits:  limits,
+		},
+		{
+			name:    "should not estimate frames in scene mode",
+			info:    newMediaInfo(30*time.Minute, 1280, 720, 1),
+			options: entities.ExtractionOptions{Mode: entities.ExtractionModeScene},
+			limits:  limits,
+		},
+		{
+			name:           "should reject a fixed rate over a video of unknown length",
+			info:           newMediaInfo(0, 1280, 720, 1),
+			options:        entities.ExtractionOptions{FPS: 1},
+			limits:         limits,
+			expectedStatus: 400,
+		},
+		{
+			name:           "should count frames up to end_seconds of a video of unknown length",
+			info:           newMediaInfo(0, 1280, 720, 1),
+			options:        entities.ExtractionOptions{FPS: 1, EndSeconds: 2000},
+			limits:         limits,
+			expectedStatus: 400,
+		},
+		{
+			name:    "should accept a time range within the budget of a video of unknown length",
+			info:    newMediaInfo(0, 1280, 720, 1),
+			options: entities.ExtractionOptions{FPS: 1, EndSeconds: 600},
+			limits:  limits,
+		},
+		{
+			name:    "should not estimate frames in scene mode of a video of unknown length",
+			info:    newMediaInfo(0, 1280, 720, 1),
+			options: entities.ExtractionOptions{Mode: entities.ExtractionModeScene},
+			limits:  limits,
+		},
+		{
+			name:           "should reject start after the end of the video",
+			info:           newMediaInfo(time.Minute, 1280, 720, 1),
+			options:        entities.ExtractionOptions{StartSeconds: 90},
+			limits:         limits,
+			expectedStatus: 400,
+		},
+		{
+			name:   "should skip checks disabled by zero limits",
+			info:   newMediaInfo(10*time.Hour, 7680, 4320, 20),
+			limits: VideoLimits{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prober := &mocks.MockVideoProber{
+				ProbeFunc: func(ctx context.Context, source string) (*entities.MediaInfo, error) {
+					return tt.info, tt.probeErr
+				},
+			}
+			validator := NewVideoValidator(prober, &mocks.MockStorageService{}, tt.limits)
+
+			info, err := validator.Validate(ctx, "raw/user-123/video.webm", tt.options)
+
+			if tt.expectedStatus == 0 {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if info != tt.info {
+					t.Error("expected probed media info to be returned")
+				}
+				return
+			}
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+
+			if httpErr.StatusCode != tt.expectedStatus {
+				t.Errorf("expected status code %d, got %d", tt.expectedStatus, httpErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestVideoValidator_ValidateOptions(t *testing.T) {
+	validator := NewVideoValidator(&mocks.MockVideoProber{}, &mocks.MockStorageService{}, VideoLimits{MaxFPS: 10})
+
+	tests := []struct {
+		name    string
+		options entities.ExtractionOptions
+		wantErr bool
+	}{
+		{
+			name:    "should accept default options",
+			options: entities.ExtractionOptions{},
+		},
+		{
+			name:    "should accept rate within limit",
+			options: entities.ExtractionOptions{FPS: 10, Format: entities.FrameFormatPNG},
+		},
+		{
+			name:    "should reject rate above limit",
+			options: entities.ExtractionOptions{FPS: 24},
+			wantErr: true,
+		},
+		{
+			name:    "should reject inconsistent options",
+			options: entities.ExtractionOptions{Format: "bmp"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateOptions(tt.options)
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+
+			if httpErr.StatusCode != 400 {
+				t.Errorf("expected status code 400, got %d", httpErr.StatusCode)
+			}
+		})
+	}
+}
+
+func TestVideoLimitsFromEnv(t *testing.T) {
+	t.Setenv("VIDEO_MAX_DURATION_SECONDS", "600")
+	t.Setenv("VIDEO_MAX_STREAMS", "2")
+	t.Setenv("EXTRACTION_MAX_FPS", "0.5")
+
+	limits := VideoLimitsFromEnv()
+
+	if limits.MaxDuration != 10*time.Minute {
+		t.Errorf("expected max duration 10m, got %s", limits.MaxDuration)
+	}
+
+	if limits.MaxStreams != 2 {
+		t.Errorf("expected max streams 2, got %d", limits.MaxStreams)
+	}
+
+	if limits.MaxFPS != 0.5 {
+		t.Errorf("expected max fps 0.5, got %v", limits.MaxFPS)
+	}
+
+	if limits.MaxWidth != DefaultVideoLimits().MaxWidth {
+		t.Errorf("expected default max width, got %d", limits.MaxWidth)
+	}
+}
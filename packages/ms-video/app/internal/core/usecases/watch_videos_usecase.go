@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+type WatchVideosUsecase struct {
+	videoRepository ports.VideoRepository
+	subscriber      ports.VideoEventSubscriber
+}
+
+func NewWatchVideosUsecase(videoRepository ports.VideoRepository, subscriber ports.VideoEventSubscriber) *WatchVideosUsecase {
+	return &WatchVideosUsecase{
+		videoRepository: videoRepository,
+		subscriber:      subscriber,
+	}
+}
+
+// WatchVideo delivers the current state of the video, then each newer one,
+// until ctx is done.
+func (u *WatchVideosUsecase) WatchVideo(ctx context.Context, videoID, userID string) (<-chan dto.VideoOutput, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	// Subscribe first so no change is missed before the read
+	updates := u.subscriber.Subscribe(ctx, userID)
+
+	video, err := u.videoRepository.FindByID(ctx, videoID)
+	if err != nil {
+		cancel()
+		return nil, utils.NewNotFoundError("video not found")
+	}
+
+	if video.UserID != userID {
+		cancel()
+		return nil, utils.NewUnauthorizedError("you don't have permission to watch this video")
+	}
+
+	outputs := make(chan dto.VideoOutput)
+	go func() {
+		defer close(outputs)
+		defer cancel()
+
+		if !sendVideoOutput(ctx, outputs, video) {
+			return
+		}
+
+		version := video.Version
+		for update := range updates {
+			if update.ID != videoID || update.Version <= version {
+				continue
+			}
+			version = update.Version
+			if !sendVideoOutput(ctx, outputs, update) {
+				return
+			}
+		}
+	}()
+
+	return outputs, nil
+}
+
+// WatchUser delivers each new state of any of the user's videos until ctx
+// is done.
+func (u *WatchVideosUsecase) WatchUser(ctx context.Context, userID string) <-chan dto.VideoOutput {
+	updates := u.subscriber.Subscribe(ctx, userID)
+
+	outputs := make(chan dto.VideoOutput)
+	go func() {
+		defer close(outputs)
+
+		for update := range updates {
+			if !sendVideoOutput(ctx, outputs, update) {
+				return
+			}
+		}
+	}()
+
+	return outputs
+}
+
+// sendVideoOutput reports false when ctx is done before the video is taken.
+func sendVideoOutput(ctx context.Context, outputs chan<- dto.VideoOutput, video *entities.Video) bool {
+	select {
+	case outputs <- newVideoOutput(video):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
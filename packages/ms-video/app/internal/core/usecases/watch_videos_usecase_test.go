@@ -0,0 +1,155 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func newUpdatesSubscriber(updates ...*entities.Video) *mocks.MockVideoEventSubscriber {
+	return &mocks.MockVideoEventSubscriber{
+		SubscribeFunc: func(ctx context.Context, userID string) <-chan *entities.Video {
+			ch := make(chan *entities.Video, len(updates))
+			for _, update := range updates {
+				ch <- update
+			}
+			close(ch)
+			return ch
+		},
+	}
+}
+
+func TestWatchVideosUsecase_WatchVideo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	videoRepo := &mocks.MockVideoRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+			return &entities.Video{ID: id, UserID: "user-123", Status: entities.VideoStatusProcessing, ProgressPercent: 30, Version: 3}, nil
+		},
+	}
+	subscriber := newUpdatesSubscriber(
+		// Saved before the video was read
+		&entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusProcessing, ProgressPercent: 10, Version: 2},
+		&entities.Video{ID: "other-video", UserID: "user-123", Status: entities.VideoStatusPending, Version: 7},
+		&entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusProcessing, ProgressPercent: 45, Version: 4},
+		&entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusCompleted, ProgressPercent: 100, Version: 5},
+	)
+
+	outputs, err := NewWatchVideosUsecase(videoRepo, subscriber).WatchVideo(ctx, "video-123", "user-123")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var progress []int
+	for output := range outputs {
+		if output.ID != "video-123" {
+			t.Errorf("expected only video-123, got %s", output.ID)
+		}
+		progress = append(progress, output.ProgressPercent)
+	}
+
+	expected := []int{30, 45, 100}
+	if len(progress) != len(expected) {
+		t.Fatalf("expected progress %v, got %v", expected, progress)
+	}
+	for i := range expected {
+		if progress[i] != expected[i] {
+			t.Errorf("expected progress %v, got %v", expected, progress)
+			break
+		}
+	}
+}
+
+func TestWatchVideosUsecase_WatchVideo_Errors(t *testing.T) {
+	tests := []struct {
+		name         string
+		userID       string
+		findErr      error
+		expectedCode int
+	}{
+		{
+			name:         "should return 404 when video not found",
+			userID:       "user-123",
+			findErr:      ports.ErrVideoNotFound,
+			expectedCode: 404,
+		},
+		{
+			name:         "should return 401 for another user's video",
+			userID:       "other-user",
+			expectedCode: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoRepo := &mocks.MockVideoRepository{
+				FindByIDFunc: func(ctx context.Context, id string) (*entities.Video, error) {
+					if tt.findErr != nil {
+						return nil, tt.findErr
+					}
+					return &entities.Video{ID: id, UserID: "user-123"}, nil
+				},
+			}
+
+			subscriptionEnded := make(chan struct{})
+			subscriber := &mocks.MockVideoEventSubscriber{
+				SubscribeFunc: func(ctx context.Context, userID string) <-chan *entities.Video {
+					context.AfterFunc(ctx, func() { close(subscriptionEnded) })
+					return make(chan *entities.Video)
+				},
+			}
+
+			_, err := NewWatchVideosUsecase(videoRepo, subscriber).WatchVideo(context.Background(), "video-123", tt.userID)
+
+			httpErr, ok := err.(*utils.HttpError)
+			if !ok {
+				t.Fatalf("expected HttpError, got %T", err)
+			}
+			if httpErr.StatusCode != tt.expectedCode {
+				t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+			}
+
+			select {
+			case <-subscriptionEnded:
+			case <-time.After(time.Second):
+				t.Error("expected the subscription to end with the error")
+			}
+		})
+	}
+}
+
+func TestWatchVideosUsecase_WatchUser(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var subscribedUser string
+	subscriber := newUpdatesSubscriber(
+		&entities.Video{ID: "video-1", UserID: "user-123", Status: entities.VideoStatusProcessing},
+		&entities.Video{ID: "video-2", UserID: "user-123", Status: entities.VideoStatusFailed},
+	)
+	subscribe := subscriber.SubscribeFunc
+	subscriber.SubscribeFunc = func(ctx context.Context, userID string) <-chan *entities.Video {
+		subscribedUser = userID
+		return subscribe(ctx, userID)
+	}
+
+	outputs := NewWatchVideosUsecase(&mocks.MockVideoRepository{}, subscriber).WatchUser(ctx, "user-123")
+
+	var ids []string
+	for output := range outputs {
+		ids = append(ids, output.ID)
+	}
+
+	if subscribedUser != "user-123" {
+		t.Errorf("expected subscription for user-123, got %s", subscribedUser)
+	}
+	if len(ids) != 2 || ids[0] != "video-1" || ids[1] != "video-2" {
+		t.Errorf("expected both videos in order, got %v", ids)
+	}
+}
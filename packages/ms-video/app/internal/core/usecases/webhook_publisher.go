@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+// WebhookPublisher records an event for every webhook of the video's owner
+// that subscribes to it and queues the deliveries.
+type WebhookPublisher struct {
+	webhookRepository  ports.WebhookRepository
+	deliveryRepository ports.WebhookDeliveryRepository
+	webhookQueue       ports.WebhookQueue
+}
+
+func NewWebhookPublisher(
+	webhookRepository ports.WebhookRepository,
+	deliveryRepository ports.WebhookDeliveryRepository,
+	webhookQueue ports.WebhookQueue,
+) *WebhookPublisher {
+	return &WebhookPublisher{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+		webhookQueue:       webhookQueue,
+	}
+}
+
+// Publish queues event about video; failures are logged, not returned.
+// Publishing it again queues the deliveries that could not be queued before.
+func (p *WebhookPublisher) Publish(ctx context.Context, event entities.WebhookEvent, video *entities.Video) {
+	webhooks, err := p.webhookRepository.FindByUserID(ctx, video.UserID)
+	if err != nil {
+		log.Printf("Failed to find webhooks for video %s: %v", video.ID, err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(event) {
+			continue
+		}
+		if err := p.publishTo(ctx, webhook, event, video); err != nil {
+			log.Printf("Failed to queue %s webhook %s for video %s: %v", event, webhook.ID, video.ID, err)
+		}
+	}
+}
+
+func (p *WebhookPublisher) publishTo(ctx context.Context, webhook *entities.Webhook, event entities.WebhookEvent, video *entities.Video) error {
+	// The ID is only known once the delivery exists, and goes in the payload
+	delivery := entities.NewWebhookDelivery(webhook, video, event, "")
+	payload, err := json.Marshal(dto.WebhookPayload{
+		ID:        delivery.ID,
+		Event:     string(event),
+		CreatedAt: delivery.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		Video:     newVideoOutput(video),
+	})
+	if err != nil {
+		return err
+	}
+	delivery.Payload = string(payload)
+
+	if err := p.deliveryRepository.Save(ctx, delivery); errors.Is(err, ports.ErrWebhookDeliveryExists) {
+		// Queueing may have failed when the event was first published
+		existing, err := p.deliveryRepository.FindByID(ctx, delivery.ID)
+		if err != nil {
+			return err
+		}
+		if existing.IsFinished() || existing.Attempts > 0 {
+			return nil
+		}
+	} else if err != nil {
+		return err
+	}
+
+	return p.webhookQueue.Send(ctx, dto.WebhookDeliveryMessage{DeliveryID: delivery.ID})
+}
@@ -0,0 +1,197 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+)
+
+// newTestWebhookPublisher publishes to a user without webhooks.
+func newTestWebhookPublisher() *WebhookPublisher {
+	return NewWebhookPublisher(&mocks.MockWebhookRepository{}, &mocks.MockWebhookDeliveryRepository{}, &mocks.MockWebhookQueue{})
+}
+
+func TestWebhookPublisher_Publish(t *testing.T) {
+	ctx := context.Background()
+	video := &entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusCompleted, Version: 7}
+
+	webhooks := []*entities.Webhook{
+		{ID: "hook-completed", UserID: "user-123", Events: []entities.WebhookEvent{entities.WebhookEventVideoCompleted}},
+		{ID: "hook-failed", UserID: "user-123", Events: []entities.WebhookEvent{entities.WebhookEventVideoFailed}},
+		{ID: "hook-both", UserID: "user-123", Events: []entities.WebhookEvent{entities.WebhookEventVideoFailed, entities.WebhookEventVideoCompleted}},
+	}
+
+	tests := []struct {
+		name          string
+		saveErr       error
+		existing      *entities.WebhookDelivery
+		expectedSaved []string
+		expectedSent  int
+	}{
+		{
+			name:          "should queue a delivery for each subscribed webhook",
+			expectedSaved: []string{"hook-completed", "hook-both"},
+			expectedSent:  2,
+		},
+		{
+			name:          "should not queue deliveries that were already attempted",
+			saveErr:       ports.ErrWebhookDeliveryExists,
+			existing:      &entities.WebhookDelivery{Status: entities.WebhookDeliveryPending, Attempts: 1},
+			expectedSaved: []string{"hook-completed", "hook-both"},
+			expectedSent:  0,
+		},
+		{
+			name:          "should not queue deliveries that already finished",
+			saveErr:       ports.ErrWebhookDeliveryExists,
+			existing:      &entities.WebhookDelivery{Status: entities.WebhookDeliverySucceeded, Attempts: 1},
+			expectedSaved: []string{"hook-completed", "hook-both"},
+			expectedSent:  0,
+		},
+		{
+			name:          "should queue recorded deliveries that were never attempted",
+			saveErr:       ports.ErrWebhookDeliveryExists,
+			existing:      &entities.WebhookDelivery{Status: entities.WebhookDeliveryPending},
+			expectedSaved: []string{"hook-completed", "hook-both"},
+			expectedSent:  2,
+		},
+		{
+			name:          "should go on with the other webhooks when one cannot be recorded",
+			saveErr:       errors.New("dynamodb unavailable"),
+			expectedSaved: []string{"hook-completed", "hook-both"},
+			expectedSent:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved []*entities.WebhookDelivery
+			deliveryRepo := &mocks.MockWebhookDeliveryRepository{
+				SaveFunc: func(ctx context.Context, delivery *entities.WebhookDelivery) error {
+					saved = append(saved, delivery)
+					return tt.saveErr
+				},
+				FindByIDFunc: func(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error) {
+					return tt.existing, nil
+				},
+			}
+			var sent []dto.WebhookDeliveryMessage
+			queue := &mocks.MockWebhookQueue{
+				SendFunc: func(ctx context.Context, message dto.WebhookDeliveryMessage) error {
+					sent = append(sent, message)
+					return nil
+				},
+			}
+			webhookRepo := &mocks.MockWebhookRepository{
+				FindByUserIDFunc: func(ctx context.Context, userID string) ([]*entities.Webhook, error) {
+					return webhooks, nil
+				},
+			}
+
+			NewWebhookPublisher(webhookRepo, deliveryRepo, queue).Publish(ctx, entities.WebhookEventVideoCompleted, video)
+
+			if len(saved) != len(tt.expectedSaved) {
+				t.Fatalf("expected %d deliveries saved, got %d", len(tt.expectedSaved), len(saved))
+			}
+			for i, delivery := range saved {
+				if delivery.WebhookID != tt.expectedSaved[i] {
+					t.Errorf("expected delivery to %s, got %s", tt.expectedSaved[i], delivery.WebhookID)
+				}
+			}
+			if len(sent) != tt.expectedSent {
+				t.Fatalf("expected %d messages sent, got %d", tt.expectedSent, len(sent))
+			}
+			for i, message := range sent {
+				if message.DeliveryID != saved[i].ID {
+					t.Errorf("expected message for delivery %s, got %s", saved[i].ID, message.DeliveryID)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookPublisher_Publish_RetriesFailedQueueing(t *testing.T) {
+	ctx := context.Background()
+	video := &entities.Video{ID: "video-123", UserID: "user-123", Status: entities.VideoStatusCompleted, Version: 7}
+	webhookRepo := &mocks.MockWebhookRepository{
+		FindByUserIDFunc: func(ctx context.Context, userID string) ([]*entities.Webhook, error) {
+			return []*entities.Webhook{{ID: "hook-1", UserID: userID, Events: []entities.WebhookEvent{entities.WebhookEventVideoCompleted}}}, nil
+		},
+	}
+
+	deliveries := map[string]*entities.WebhookDelivery{}
+	deliveryRepo := &mocks.MockWebhookDeliveryRepository{
+		SaveFunc: func(ctx context.Context, delivery *entities.WebhookDelivery) error {
+			if _, ok := deliveries[delivery.ID]; ok {
+				return ports.ErrWebhookDeliveryExists
+			}
+			deliveries[delivery.ID] = delivery
+			return nil
+		},
+		FindByIDFunc: func(ctx context.Context, deliveryID string) (*entities.WebhookDelivery, error) {
+			return deliveries[deliveryID], nil
+		},
+	}
+
+	sendErr := errors.New("sqs unavailable")
+	var sent []dto.WebhookDeliveryMessage
+	queue := &mocks.MockWebhookQueue{
+		SendFunc: func(ctx context.Context, message dto.WebhookDeliveryMessage) error {
+			if sendErr != nil {
+				return sendErr
+			}
+			sent = append(sent, message)
+			return nil
+		},
+	}
+	publisher := NewWebhookPublisher(webhookRepo, deliveryRepo, queue)
+
+	publisher.Publish(ctx, entities.WebhookEventVideoCompleted, video)
+	sendErr = nil
+	publisher.Publish(ctx, entities.WebhookEventVideoCompleted, video)
+
+	if len(deliveries) != 1 {
+		t.Fatalf("expected one delivery recorded, got %d", len(deliveries))
+	}
+	if len(sent) != 1 || deliveries[sent[0].DeliveryID] == nil {
+		t.Errorf("expected the recorded delivery to be queued on retry, got %v", sent)
+	}
+}
+
+func TestWebhookPublisher_Publish_Payload(t *testing.T) {
+	video := &entities.Video{ID: "video-123", UserID: "user-123", OriginalName: "clip.mp4", Status: entities.VideoStatusFailed, ErrorMessage: "failed to read video"}
+	webhookRepo := &mocks.MockWebhookRepository{
+		FindByUserIDFunc: func(ctx context.Context, userID string) ([]*entities.Webhook, error) {
+			return []*entities.Webhook{{ID: "hook-1", UserID: userID, Events: []entities.WebhookEvent{entities.WebhookEventVideoFailed}}}, nil
+		},
+	}
+	var saved *entities.WebhookDelivery
+	deliveryRepo := &mocks.MockWebhookDeliveryRepository{
+		SaveFunc: func(ctx context.Context, delivery *entities.WebhookDelivery) error {
+			saved = delivery
+			return nil
+		},
+	}
+
+	NewWebhookPublisher(webhookRepo, deliveryRepo, &mocks.MockWebhookQueue{}).Publish(context.Background(), entities.WebhookEventVideoFailed, video)
+
+	if saved == nil {
+		t.Fatal("expected a delivery to be saved")
+	}
+
+	var payload dto.WebhookPayload
+	if err := json.Unmarshal([]byte(saved.Payload), &payload); err != nil {
+		t.Fatalf("expected a JSON payload, got %v", err)
+	}
+	if payload.ID != saved.ID || payload.Event != "video.failed" {
+		t.Errorf("expected payload of delivery %s for video.failed, got %+v", saved.ID, payload)
+	}
+	if payload.Video.ID != "video-123" || payload.Video.Status != "failed" || payload.Video.ErrorMessage != "failed to read video" {
+		t.Errorf("expected the failed video in the payload, got %+v", payload.Video)
+	}
+}
@@ -0,0 +1,233 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+const (
+	// MaxWebhooksPerUser caps the endpoints a single event is posted to.
+	MaxWebhooksPerUser = 10
+	// MinWebhookSecretLength keeps user-chosen secrets hard to guess.
+	MinWebhookSecretLength = 16
+
+	DefaultWebhookDeliveriesLimit = 20
+	MaxWebhookDeliveriesLimit     = 100
+)
+
+type WebhooksUsecase struct {
+	webhookRepository  ports.WebhookRepository
+	deliveryRepository ports.WebhookDeliveryRepository
+}
+
+func NewWebhooksUsecase(webhookRepository ports.WebhookRepository, deliveryRepository ports.WebhookDeliveryRepository) *WebhooksUsecase {
+	return &WebhooksUsecase{
+		webhookRepository:  webhookRepository,
+		deliveryRepository: deliveryRepository,
+	}
+}
+
+// Create registers a webhook, generating a secret when none is given.
+func (u *WebhooksUsecase) Create(ctx context.Context, input dto.CreateWebhookInput) (*dto.WebhookOutput, error) {
+	webhooks, err := u.webhookRepository.FindByUserID(ctx, input.UserID)
+	if err != nil {
+		return nil, utils.NewInternalServerError("failed to list webhooks")
+	}
+	if len(webhooks) >= MaxWebhooksPerUser {
+		return nil, utils.NewConflictError(fmt.Sprintf("a user can have at most %d webhooks", MaxWebhooksPerUser))
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, utils.NewInternalServerError("failed to generate webhook secret")
+		}
+	}
+
+	webhook := entities.NewWebhook(input.UserID, input.URL, secret, webhookEvents(input.Events))
+	if err := validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	if err := u.webhookRepository.Save(ctx, webhook); err != nil {
+		return nil, utils.NewInternalServerError("failed to save webhook")
+	}
+
+	output := newWebhookOutput(webhook)
+	output.Secret = webhook.Secret
+	return &output, nil
+}
+
+func (u *WebhooksUsecase) List(ctx context.Context, userID string) (*dto.ListWebhooksOutput, error) {
+	webhooks, err := u.webhookRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.NewInternalServerError("failed to list webhooks")
+	}
+
+	outputs := make([]dto.WebhookOutput, len(webhooks))
+	for i, webhook := range webhooks {
+		outputs[i] = newWebhookOutput(webhook)
+	}
+
+	return &dto.ListWebhooksOutput{Webhooks: outputs}, nil
+}
+
+func (u *WebhooksUsecase) Get(ctx context.Context, webhookID, userID string) (*dto.WebhookOutput, error) {
+	webhook, err := u.findOwned(ctx, webhookID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := newWebhookOutput(webhook)
+	return &output, nil
+}
+
+func (u *WebhooksUsecase) Update(ctx context.Context, input dto.UpdateWebhookInput) (*dto.WebhookOutput, error) {
+	webhook, err := u.findOwned(ctx, input.ID, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.URL != nil {
+		webhook.URL = *input.URL
+	}
+	if input.Events != nil {
+		webhook.Events = webhookEvents(*input.Events)
+	}
+	if input.Secret != nil {
+		webhook.Secret = *input.Secret
+		if webhook.Secret == "" {
+			if webhook.Secret, err = generateWebhookSecret(); err != nil {
+				return nil, utils.NewInternalServerError("failed to generate webhook secret")
+			}
+		}
+	}
+
+	if err := validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	webhook.UpdatedAt = time.Now()
+	if err := u.webhookRepository.Update(ctx, webhook); err != nil {
+		return nil, utils.NewInternalServerError("failed to save webhook")
+	}
+
+	output := newWebhookOutput(webhook)
+	if input.Secret != nil {
+		output.Secret = webhook.Secret
+	}
+	return &output, nil
+}
+
+// Delete removes the webhook.
+func (u *WebhooksUsecase) Delete(ctx context.Context, webhookID, userID string) error {
+	if _, err := u.findOwned(ctx, webhookID, userID); err != nil {
+		return err
+	}
+
+	if err := u.webhookRepository.Delete(ctx, webhookID); err != nil {
+		return utils.NewInternalServerError("failed to delete webhook")
+	}
+	return nil
+}
+
+// Deliveries returns the latest deliveries to the webhook, newest first.
+func (u *WebhooksUsecase) Deliveries(ctx context.Context, webhookID, userID string, limit int) (*dto.ListWebhookDeliveriesOutput, error) {
+	if limit < 0 || limit > MaxWebhookDeliveriesLimit {
+		return nil, utils.NewBadRequestError(fmt.Sprintf("limit must be between 1 and %d", MaxWebhookDeliveriesLimit))
+	}
+	if limit == 0 {
+		limit = DefaultWebhookDeliveriesLimit
+	}
+
+	if _, err := u.findOwned(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := u.deliveryRepository.FindByWebhookID(ctx, webhookID, limit)
+	if err != nil {
+		return nil, utils.NewInternalServerError("failed to list webhook deliveries")
+	}
+
+	outputs := make([]dto.WebhookDeliveryOutput, len(deliveries))
+	for i, delivery := range deliveries {
+		outputs[i] = dto.WebhookDeliveryOutput{
+			ID:             delivery.ID,
+			Event:          string(delivery.Event),
+			VideoID:        delivery.VideoID,
+			Status:         string(delivery.Status),
+			Attempts:       delivery.Attempts,
+			ResponseStatus: delivery.ResponseStatus,
+			LastError:      delivery.LastError,
+			CreatedAt:      delivery.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			UpdatedAt:      delivery.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+	}
+
+	return &dto.ListWebhookDeliveriesOutput{Deliveries: outputs}, nil
+}
+
+func (u *WebhooksUsecase) findOwned(ctx context.Context, webhookID, userID string) (*entities.Webhook, error) {
+	webhook, err := u.webhookRepository.FindByID(ctx, webhookID)
+	if errors.Is(err, ports.ErrWebhookNotFound) {
+		return nil, utils.NewNotFoundError("webhook not found")
+	} else if err != nil {
+		return nil, utils.NewInternalServerError("failed to find webhook")
+	}
+
+	if webhook.UserID != userID {
+		return nil, utils.NewUnauthorizedError("you don't have permission to access this webhook")
+	}
+
+	return webhook, nil
+}
+
+func validateWebhook(webhook *entities.Webhook) error {
+	if err := webhook.Validate(); err != nil {
+		return utils.NewBadRequestError(err.Error())
+	}
+	if len(webhook.Secret) < MinWebhookSecretLength {
+		return utils.NewBadRequestError(fmt.Sprintf("secret must be at least %d characters", MinWebhookSecretLength))
+	}
+	return nil
+}
+
+func webhookEvents(names []string) []entities.WebhookEvent {
+	events := make([]entities.WebhookEvent, len(names))
+	for i, name := range names {
+		events[i] = entities.WebhookEvent(name)
+	}
+	return events
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+func newWebhookOutput(webhook *entities.Webhook) dto.WebhookOutput {
+	events := make([]string, len(webhook.Events))
+	for i, event := range webhook.Events {
+		events[i] = string(event)
+	}
+
+	return dto.WebhookOutput{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: webhook.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
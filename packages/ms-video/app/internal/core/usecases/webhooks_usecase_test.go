@@ -0,0 +1,239 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cks-solutions/hackathon/ms-video/internal/adapters/driver/dto"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/entities"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/mocks"
+	"github.com/cks-solutions/hackathon/ms-video/internal/core/ports"
+	"github.com/cks-solutions/hackathon/ms-video/pkg/utils"
+)
+
+func TestWebhooksUsecase_Create(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		input         dto.CreateWebhookInput
+		existing      int
+		expectedCode  int
+		expectSecret  string
+		expectedSaves int
+	}{
+		{
+			name:          "should create a webhook with a generated secret",
+			input:         dto.CreateWebhookInput{URL: "https://example.com/hook", Events: []string{"video.completed"}},
+			expectedSaves: 1,
+		},
+		{
+			name:          "should keep the given secret",
+			input:         dto.CreateWebhookInput{URL: "https://example.com/hook", Secret: "my-very-long-secret", Events: []string{"video.failed"}},
+			expectSecret:  "my-very-long-secret",
+			expectedSaves: 1,
+		},
+		{
+			name:         "should reject a relative url",
+			input:        dto.CreateWebhookInput{URL: "/hook", Events: []string{"video.completed"}},
+			expectedCode: 400,
+		},
+		{
+			name:         "should reject unknown events",
+			input:        dto.CreateWebhookInput{URL: "https://example.com/hook", Events: []string{"video.uploaded"}},
+			expectedCode: 400,
+		},
+		{
+			name:         "should reject a short secret",
+			input:        dto.CreateWebhookInput{URL: "https://example.com/hook", Secret: "short", Events: []string{"video.completed"}},
+			expectedCode: 400,
+		},
+		{
+			name:         "should return 409 when the user has too many webhooks",
+			input:        dto.CreateWebhookInput{URL: "https://example.com/hook", Events: []string{"video.completed"}},
+			existing:     MaxWebhooksPerUser,
+			expectedCode: 409,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saves := 0
+			webhookRepo := &mocks.MockWebhookRepository{
+				FindByUserIDFunc: func(ctx context.Context, userID string) ([]*entities.Webhook, error) {
+					return make([]*entities.Webhook, tt.existing), nil
+				},
+				SaveFunc: func(ctx context.Context, webhook *entities.Webhook) error {
+					saves++
+					return nil
+				},
+			}
+
+			tt.input.UserID = "user-123"
+			output, err := NewWebhooksUsecase(webhookRepo, &mocks.MockWebhookDeliveryRepository{}).Create(ctx, tt.input)
+
+			if saves != tt.expectedSaves {
+				t.Errorf("expected %d saves, got %d", tt.expectedSaves, saves)
+			}
+
+			if tt.expectedCode != 0 {
+				httpErr, ok := err.(*utils.HttpError)
+				if !ok {
+					t.Fatalf("expected HttpError, got %T", err)
+				}
+				if httpErr.StatusCode != tt.expectedCode {
+					t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if output.ID == "" {
+				t.Error("expected webhook ID to be set")
+			}
+			if tt.expectSecret != "" && output.Secret != tt.expectSecret {
+				t.Errorf("expected secret %q, got %q", tt.expectSecret, output.Secret)
+			}
+			if len(output.Secret) < MinWebhookSecretLength {
+				t.Errorf("expected the secret to be returned on creation, got %q", output.Secret)
+			}
+		})
+	}
+}
+
+func TestWebhooksUsecase_Update(t *testing.T) {
+	ctx := context.Background()
+	newURL := "https://example.com/new"
+	emptySecret := ""
+
+	webhook := &entities.Webhook{
+		ID:     "hook-1",
+		UserID: "user-123",
+		URL:    "https://example.com/hook",
+		Secret: "0123456789abcdef",
+		Events: []entities.WebhookEvent{entities.WebhookEventVideoCompleted},
+	}
+	var updated *entities.Webhook
+	webhookRepo := &mocks.MockWebhookRepository{
+		FindByIDFunc: func(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+			copy := *webhook
+			return &copy, nil
+		},
+		UpdateFunc: func(ctx context.Context, w *entities.Webhook) error {
+			updated = w
+			return nil
+		},
+	}
+	usecase := NewWebhooksUsecase(webhookRepo, &mocks.MockWebhookDeliveryRepository{})
+
+	output, err := usecase.Update(ctx, dto.UpdateWebhookInput{ID: "hook-1", UserID: "user-123", URL: &newURL})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.URL != newURL || updated.Secret != webhook.Secret {
+		t.Errorf("expected only the url to change, got %+v", updated)
+	}
+	if output.Secret != "" {
+		t.Errorf("expected the unchanged secret to stay hidden, got %q", output.Secret)
+	}
+
+	output, err = usecase.Update(ctx, dto.UpdateWebhookInput{ID: "hook-1", UserID: "user-123", Secret: &emptySecret})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Secret == webhook.Secret || output.Secret != updated.Secret {
+		t.Errorf("expected a new secret to be generated and returned, got %q", output.Secret)
+	}
+
+	_, err = usecase.Update(ctx, dto.UpdateWebhookInput{ID: "hook-1", UserID: "other-user", URL: &newURL})
+	if httpErr, ok := err.(*utils.HttpError); !ok || httpErr.StatusCode != 401 {
+		t.Errorf("expected 401 for another user's webhook, got %v", err)
+	}
+}
+
+func TestWebhooksUsecase_Deliveries(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		userID        string
+		limit         int
+		findErr       error
+		expectedCode  int
+		expectedLimit int
+	}{
+		{
+			name:          "should list deliveries with the default limit",
+			userID:        "user-123",
+			expectedLimit: DefaultWebhookDeliveriesLimit,
+		},
+		{
+			name:          "should pass the requested limit",
+			userID:        "user-123",
+			limit:         5,
+			expectedLimit: 5,
+		},
+		{
+			name:         "should reject a limit above the maximum",
+			userID:       "user-123",
+			limit:        MaxWebhookDeliveriesLimit + 1,
+			expectedCode: 400,
+		},
+		{
+			name:         "should return 404 when the webhook does not exist",
+			userID:       "user-123",
+			findErr:      ports.ErrWebhookNotFound,
+			expectedCode: 404,
+		},
+		{
+			name:         "should return 401 for another user's webhook",
+			userID:       "other-user",
+			expectedCode: 401,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhookRepo := &mocks.MockWebhookRepository{
+				FindByIDFunc: func(ctx context.Context, webhookID string) (*entities.Webhook, error) {
+					if tt.findErr != nil {
+						return nil, tt.findErr
+					}
+					return &entities.Webhook{ID: webhookID, UserID: "user-123"}, nil
+				},
+			}
+			requestedLimit := 0
+			deliveryRepo := &mocks.MockWebhookDeliveryRepository{
+				FindByWebhookIDFunc: func(ctx context.Context, webhookID string, limit int) ([]*entities.WebhookDelivery, error) {
+					requestedLimit = limit
+					return []*entities.WebhookDelivery{{ID: "delivery-1", Status: entities.WebhookDeliveryFailed, Attempts: 8, ResponseStatus: 500}}, nil
+				},
+			}
+
+			output, err := NewWebhooksUsecase(webhookRepo, deliveryRepo).Deliveries(ctx, "hook-1", tt.userID, tt.limit)
+
+			if tt.expectedCode != 0 {
+				httpErr, ok := err.(*utils.HttpError)
+				if !ok {
+					t.Fatalf("expected HttpError, got %T", err)
+				}
+				if httpErr.StatusCode != tt.expectedCode {
+					t.Errorf("expected status code %d, got %d", tt.expectedCode, httpErr.StatusCode)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if requestedLimit != tt.expectedLimit {
+				t.Errorf("expected limit %d, got %d", tt.expectedLimit, requestedLimit)
+			}
+			if len(output.Deliveries) != 1 || output.Deliveries[0].Status != "failed" || output.Deliveries[0].ResponseStatus != 500 {
+				t.Errorf("expected the failed delivery, got %+v", output.Deliveries)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 )
@@ -59,6 +60,11 @@ func NewDynamoClient(region Region, stage Stage) *dynamodb.Client {
 	return dynamodb.NewFromConfig(cfg)
 }
 
+func NewDynamoStreamsClient(region Region, stage Stage) *dynamodbstreams.Client {
+	cfg := NewAWSConfig(region, stage)
+	return dynamodbstreams.NewFromConfig(cfg)
+}
+
 func NewSQSClient(region Region, stage Stage) *sqs.Client {
 	cfg := NewAWSConfig(region, stage)
 	return sqs.NewFromConfig(cfg)
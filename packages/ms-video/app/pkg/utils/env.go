@@ -1,6 +1,9 @@
 package utils
 
-import "os"
+import (
+	"os"
+	"strconv"
+)
 
 func GetEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -10,6 +13,26 @@ func GetEnv(key, defaultValue string) string {
 	return value
 }
 
+// GetEnvInt reads an integer setting, falling back to defaultValue when it
+// is unset or not a number.
+func GetEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvFloat reads a decimal setting, falling back to defaultValue when it
+// is unset or not a number.
+func GetEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 func GetRegion() string {
 	return GetEnv("AWS_REGION", "us-east-1")
 }
@@ -160,3 +160,93 @@ func TestGetEnv_MultipleCallsSameKey(t *testing.T) {
 		t.Errorf("second call: expected 'new_value', got '%s'", result2)
 	}
 }
+
+func TestGetEnvInt(t *testing.T) {
+	tests := []struct {
+		name         string
+		envValue     string
+		defaultValue int
+		expected     int
+	}{
+		{
+			name:         "should parse integer value",
+			envValue:     "42",
+			defaultValue: 7,
+			expected:     42,
+		},
+		{
+			name:         "should return default when unset",
+			envValue:     "",
+			defaultValue: 7,
+			expected:     7,
+		},
+		{
+			name:         "should return default when not a number",
+			envValue:     "forty-two",
+			defaultValue: 7,
+			expected:     7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "TEST_INT_VAR"
+			os.Setenv(key, tt.envValue)
+			defer os.Unsetenv(key)
+
+			result := GetEnvInt(key, tt.defaultValue)
+
+			if result != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGetEnvFloat(t *testing.T) {
+	tests := []struct {
+		name         string
+		envValue     string
+		defaultValue float64
+		expected     float64
+	}{
+		{
+			name:         "should parse decimal value",
+			envValue:     "0.5",
+			defaultValue: 7,
+			expected:     0.5,
+		},
+		{
+			name:         "should parse integer value",
+			envValue:     "42",
+			defaultValue: 7,
+			expected:     42,
+		},
+		{
+			name:         "should return default when unset",
+			envValue:     "",
+			defaultValue: 7,
+			expected:     7,
+		},
+		{
+			name:         "should return default when not a number",
+			envValue:     "forty-two",
+			defaultValue: 7,
+			expected:     7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "TEST_FLOAT_VAR"
+			os.Setenv(key, tt.envValue)
+			defer os.Unsetenv(key)
+
+			result := GetEnvFloat(key, tt.defaultValue)
+
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
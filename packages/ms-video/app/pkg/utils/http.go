@@ -30,6 +30,10 @@ func NewNotFoundError(message string) *HttpError {
 	return NewHttpError(404, message)
 }
 
+func NewConflictError(message string) *HttpError {
+	return NewHttpError(409, message)
+}
+
 func NewInternalServerError(message string) *HttpError {
 	return NewHttpError(500, message)
 }
@@ -80,6 +80,19 @@ func TestNewNotFoundError(t *testing.T) {
 	}
 }
 
+func TestNewConflictError(t *testing.T) {
+	message := "resource already exists"
+	err := NewConflictError(message)
+
+	if err.StatusCode != 409 {
+		t.Errorf("expected status code 409, got %d", err.StatusCode)
+	}
+
+	if err.Message != message {
+		t.Errorf("expected message '%s', got '%s'", message, err.Message)
+	}
+}
+
 func TestNewInternalServerError(t *testing.T) {
 	message := "something went wrong"
 	err := NewInternalServerError(message)